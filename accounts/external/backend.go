@@ -17,12 +17,14 @@
 package external
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -229,3 +231,18 @@ func (api *ExternalSigner) pingVersion() (string, error) {
 	}
 	return v, nil
 }
+
+// NewClefTransactor is a utility method to easily create a transaction signer
+// with a clef backend. It lives here rather than in the bind package, which
+// would otherwise depend on the whole signer stack.
+func NewClefTransactor(clef *ExternalSigner, account accounts.Account) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(signer types.Signer, address common.Address, transaction *types.Transaction) (*types.Transaction, error) {
+			if address != account.Address {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return clef.SignTx(account, transaction, nil) // Clef enforces its own chain id
+		},
+	}
+}
@@ -113,6 +113,9 @@ var (
 		utils.MinerThreadsFlag,
 		utils.MinerLegacyThreadsFlag,
 		utils.MinerNotifyFlag,
+		utils.MinerNotifyNUCFlag,
+		utils.MinerStratumFlag,
+		utils.MinerStratumDifficultyFlag,
 		utils.MinerGasTargetFlag,
 		utils.MinerLegacyGasTargetFlag,
 		utils.MinerGasLimitFlag,
@@ -0,0 +1,155 @@
+// Copyright 2019 The nuc Team
+
+// nucreward simulates the NUC block rewards of synthetic participants.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+var gitDate = ""
+
+var app *cli.App
+
+var (
+	genesisFlag = cli.StringFlag{
+		Name:  "genesis",
+		Usage: "genesis JSON file whose chain config schedules the reward algorithms",
+	}
+	participantsFlag = cli.StringFlag{
+		Name:  "participants",
+		Usage: "JSON file listing the synthetic PoC/PoW/Pool/PoST participants",
+	}
+	fromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "first block to report the payouts of",
+		Value: 1,
+	}
+	toFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "last block to simulate",
+		Value: 1,
+	}
+	periodFlag = cli.Uint64Flag{
+		Name:  "period",
+		Usage: "seconds between the simulated blocks",
+		Value: 15,
+	}
+	coinbaseFlag = cli.StringFlag{
+		Name:  "coinbase",
+		Usage: "coinbase of the simulated blocks",
+	}
+	jsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "output JSON instead of human-readable format",
+	}
+)
+
+func init() {
+	app = utils.NewApp(gitCommit, gitDate, "the NUC reward simulator")
+	app.Flags = []cli.Flag{
+		genesisFlag,
+		participantsFlag,
+		fromFlag,
+		toFlag,
+		periodFlag,
+		coinbaseFlag,
+		jsonFlag,
+	}
+	app.Action = runSimulation
+	app.Description = `
+Runs the reward algorithms scheduled by the genesis chain config on top of the
+genesis state, without a live chain. The rule contract is answered from the
+given participants and the blocks contain no transactions nor uncles, so the
+output only depends on the input files.
+
+The participants file has the following layout (amounts in wei, decimal or hex):
+
+  {
+    "pocers":  [{"address": "0x..", "mortgage": "1000", "bindPool": "0x..", "bindPow": "0x..", "records": 2}],
+    "powers":  [{"address": "0x..", "mortgage": "1000", "bindPool": "0x..", "pocs": ["0x.."], "records": 2}],
+    "poolers": [{"address": "0x..", "mortgage": "1000", "pows": ["0x.."], "pocs": ["0x.."]}],
+    "posters": [{"address": "0x..", "mortgage": "1000"}],
+    "rewardRatio": 0
+  }`
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runSimulation loads the input files, simulates the requested blocks and prints
+// their payouts.
+func runSimulation(ctx *cli.Context) error {
+	if !ctx.GlobalIsSet(genesisFlag.Name) || !ctx.GlobalIsSet(participantsFlag.Name) {
+		utils.Fatalf("Both --%s and --%s are required", genesisFlag.Name, participantsFlag.Name)
+	}
+	genesis := new(core.Genesis)
+	if err := readJSON(ctx.GlobalString(genesisFlag.Name), genesis); err != nil {
+		utils.Fatalf("Failed to read genesis: %v", err)
+	}
+	participants := new(Participants)
+	if err := readJSON(ctx.GlobalString(participantsFlag.Name), participants); err != nil {
+		utils.Fatalf("Failed to read participants: %v", err)
+	}
+	var coinbase common.Address
+	if hex := ctx.GlobalString(coinbaseFlag.Name); hex != "" {
+		if !common.IsHexAddress(hex) {
+			utils.Fatalf("Invalid coinbase: %s", hex)
+		}
+		coinbase = common.HexToAddress(hex)
+	}
+	payouts, err := simulate(genesis, participants, ctx.GlobalUint64(fromFlag.Name), ctx.GlobalUint64(toFlag.Name), ctx.GlobalUint64(periodFlag.Name), coinbase)
+	if err != nil {
+		utils.Fatalf("Simulation failed: %v", err)
+	}
+	if ctx.GlobalBool(jsonFlag.Name) {
+		return printJSON(os.Stdout, payouts)
+	}
+	printPayouts(os.Stdout, payouts)
+	return nil
+}
+
+// readJSON decodes a JSON file into v.
+func readJSON(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(v)
+}
+
+// printJSON writes the payouts as indented JSON.
+func printJSON(w io.Writer, payouts []*BlockPayout) error {
+	out, err := json.MarshalIndent(payouts, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// printPayouts writes the payouts in a human-readable format.
+func printPayouts(w io.Writer, payouts []*BlockPayout) {
+	for _, block := range payouts {
+		fmt.Fprintf(w, "Block %d (reward v%d)\n", block.Number, block.Version)
+		for _, p := range block.Payouts {
+			fmt.Fprintf(w, "  %s  poc=%v pow=%v pool=%v post=%v total=%v\n", p.Address.Hex(), p.Poc, p.Pow, p.Pool, p.Post, p.Total)
+		}
+		fmt.Fprintf(w, "  leftover=%v minted=%v supply=%v\n", block.Leftover, block.Minted, block.Supply)
+	}
+}
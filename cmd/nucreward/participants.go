@@ -0,0 +1,370 @@
+// Copyright 2019 The nuc Team
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	v0 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v0"
+	v1 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v1"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Participants are the synthetic rule contract participants to simulate the
+// rewards of. Mortgages double as the buy balances of the PoW and pool
+// participants in the V4 contract.
+type Participants struct {
+	Pocers      []Pocer  `json:"pocers"`
+	Powers      []Power  `json:"powers"`
+	Poolers     []Pooler `json:"poolers"`
+	Posters     []Poster `json:"posters"`
+	RewardRatio uint64   `json:"rewardRatio"` // Block reward halving exponent
+}
+
+// Pocer is a synthetic PoC participant.
+type Pocer struct {
+	Address  common.Address        `json:"address"`
+	Mortgage *math.HexOrDecimal256 `json:"mortgage"`
+	BindPool common.Address        `json:"bindPool"` // Pool the participant is bound to (V4)
+	BindPow  common.Address        `json:"bindPow"`  // PoW participant the participant is bound to (V3)
+	Records  int                   `json:"records"`  // Number of mining records
+}
+
+// Power is a synthetic PoW participant.
+type Power struct {
+	Address  common.Address        `json:"address"`
+	Mortgage *math.HexOrDecimal256 `json:"mortgage"`
+	BindPool common.Address        `json:"bindPool"`
+	Pocs     []common.Address      `json:"pocs"`    // PoC participants bound to it
+	Records  int                   `json:"records"` // Number of mining records (V1, V2 and V4)
+}
+
+// Pooler is a synthetic pool participant.
+type Pooler struct {
+	Address  common.Address        `json:"address"`
+	Mortgage *math.HexOrDecimal256 `json:"mortgage"`
+	Pows     []common.Address      `json:"pows"` // PoW participants bound to it
+	Pocs     []common.Address      `json:"pocs"` // PoC participants bound to it (V4)
+}
+
+// Poster is a synthetic PoST participant, only rewarded by V2 and V3.
+type Poster struct {
+	Address  common.Address        `json:"address"`
+	Mortgage *math.HexOrDecimal256 `json:"mortgage"`
+}
+
+// amount converts an optional JSON amount into a big integer.
+func amount(v *math.HexOrDecimal256) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set((*big.Int)(v))
+}
+
+// addresses returns a non-nil copy of an address list, as the ABI encodes it.
+func addresses(list []common.Address) []common.Address {
+	return append([]common.Address{}, list...)
+}
+
+// contractCaller answers the rule contract calls of a reward algorithm.
+type contractCaller interface {
+	call(input []byte) ([]byte, error)
+}
+
+// ruleContract answers the list getters of a rule contract ABI from synthetic
+// participants, paging them the way the deployed contract does.
+type ruleContract struct {
+	abi   abi.ABI
+	lists map[string]interface{} // List getter name => slice of participant structs
+	ratio *big.Int
+}
+
+// countGetters maps the count getters of the rule contract to the list they count.
+var countGetters = map[string]string{
+	"PocCount":  "AllPocers",
+	"PowCount":  "AllPowers",
+	"PoolCount": "AllPoolers",
+	"PostCount": "AllPosters",
+}
+
+// newRuleContractV1 serves the participants through the V4 rule contract ABI.
+func newRuleContractV1(p *Participants) (*ruleContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(v1.NUCABI))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		pocers  = make([]v1.Pocer, len(p.Pocers))
+		powers  = make([]v1.Power, len(p.Powers))
+		poolers = make([]v1.Pooler, len(p.Poolers))
+	)
+	for i, u := range p.Pocers {
+		pocers[i] = v1.Pocer{Records: make([]v1.Record, u.Records), UserAddr: u.Address, GetReward: new(big.Int), Index: big.NewInt(int64(i)), MortageBalance: amount(u.Mortgage), BindPoolAddr: u.BindPool}
+		for j := range pocers[i].Records {
+			pocers[i].Records[j].CreateTime = new(big.Int)
+		}
+	}
+	for i, u := range p.Powers {
+		powers[i] = v1.Power{CreateTime: new(big.Int), Index: big.NewInt(int64(i)), BuyBalance: amount(u.Mortgage), BindPoolAddr: u.BindPool, PocAddrs: addresses(u.Pocs), UserAddr: u.Address, Records: make([]v1.Record, u.Records)}
+		for j := range powers[i].Records {
+			powers[i].Records[j].CreateTime = new(big.Int)
+		}
+	}
+	for i, u := range p.Poolers {
+		poolers[i] = v1.Pooler{CreateTime: new(big.Int), Index: big.NewInt(int64(i)), BuyBalance: amount(u.Mortgage), PowAddrs: addresses(u.Pows), PocAddrs: addresses(u.Pocs), UserAddr: u.Address}
+	}
+	return &ruleContract{
+		abi:   parsed,
+		lists: map[string]interface{}{"AllPocers": pocers, "AllPowers": powers, "AllPoolers": poolers},
+		ratio: new(big.Int).SetUint64(p.RewardRatio),
+	}, nil
+}
+
+// newRuleContractV0 serves the participants through the V3 rule contract ABI.
+// The records of the PoC participants are created expired, as V3 only weighs
+// those and would otherwise depend on the wall clock.
+func newRuleContractV0(p *Participants) (*ruleContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(v0.TokenABI))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		pocers  = make([]v0.Pocer, len(p.Pocers))
+		powers  = make([]v0.Power, len(p.Powers))
+		poolers = make([]v0.Pooler, len(p.Poolers))
+		posters = make([]v0.Poster, len(p.Posters))
+	)
+	for i, u := range p.Pocers {
+		pocers[i] = v0.Pocer{Records: make([]v0.Record, u.Records), UserAddr: u.Address, Index: big.NewInt(int64(i)), MortageBalance: amount(u.Mortgage), BindPowAddr: u.BindPow}
+		for j := range pocers[i].Records {
+			pocers[i].Records[j] = v0.Record{CreateTime: new(big.Int), ExpireTime: new(big.Int)}
+		}
+	}
+	for i, u := range p.Powers {
+		powers[i] = v0.Power{CreateTime: new(big.Int), Index: big.NewInt(int64(i)), MortageBalance: amount(u.Mortgage), BindPoolAddr: u.BindPool, PocAddrs: addresses(u.Pocs), UserAddr: u.Address}
+	}
+	for i, u := range p.Poolers {
+		poolers[i] = v0.Pooler{CreateTime: new(big.Int), Index: big.NewInt(int64(i)), MortageBalance: amount(u.Mortgage), PowAddrs: addresses(u.Pows), UserAddr: u.Address}
+	}
+	for i, u := range p.Posters {
+		posters[i] = v0.Poster{CreateTime: new(big.Int), Index: big.NewInt(int64(i)), MortageBalance: amount(u.Mortgage), UserAddr: u.Address}
+	}
+	return &ruleContract{
+		abi:   parsed,
+		lists: map[string]interface{}{"AllPocers": pocers, "AllPowers": powers, "AllPoolers": poolers, "AllPosters": posters},
+		ratio: new(big.Int).SetUint64(p.RewardRatio),
+	}, nil
+}
+
+// call answers a single rule contract call.
+func (c *ruleContract) call(input []byte) ([]byte, error) {
+	method, err := c.abi.MethodById(input)
+	if err != nil {
+		return nil, err
+	}
+	if method.Name == "GetRewardRatio" {
+		return method.Outputs.Pack(c.ratio)
+	}
+	if name, ok := countGetters[method.Name]; ok {
+		list, ok := c.lists[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported rule contract method %s", method.Name)
+		}
+		return method.Outputs.Pack(big.NewInt(int64(reflect.ValueOf(list).Len())))
+	}
+	list, ok := c.lists[method.Name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported rule contract method %s", method.Name)
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	offset, ok1 := args[0].(*big.Int)
+	size, ok2 := args[1].(*big.Int)
+	if !ok1 || !ok2 {
+		return nil, errors.New("invalid page arguments")
+	}
+	var (
+		items = reflect.ValueOf(list)
+		start = new(big.Int).Set(offset)
+		end   = new(big.Int).Add(offset, size)
+		n     = big.NewInt(int64(items.Len()))
+	)
+	if start.Cmp(n) > 0 {
+		start.Set(n)
+	}
+	if end.Cmp(n) > 0 {
+		end.Set(n)
+	}
+	return method.Outputs.Pack(items.Slice(int(start.Int64()), int(end.Int64())).Interface())
+}
+
+// Selectors of the rule contract methods the V1 and V2 reward algorithms call.
+// Those predate the generated bindings and call the contract by raw selector.
+const (
+	selectorOwner          = "8da5cb5b"
+	selectorRewardRatio    = "76b8dde1"
+	selectorPowCount       = "cca1aa47"
+	selectorPocUsers       = "4d4df113"
+	selectorValidPocUsers  = "6957fbaf"
+	selectorPowUsers       = "93510d62"
+	selectorValidPowUsers  = "f7c4de01"
+	selectorPoolUsers      = "3dd544e3"
+	selectorPostUsers      = "fa3f5e26"
+	selectorPowRank        = "13c7bbce"
+	selectorPowRankPoc     = "2b8c9583"
+	selectorPoolRank       = "d6badb3f"
+	selectorPoolRankPow    = "02896c3a"
+	selectorPowRankAmount  = "682c73e3"
+	selectorPowMortgage    = "44aa7c3b"
+	selectorPoolRankAmount = "cfa14566"
+	selectorPoolMortgage   = "2a100e87"
+	selectorPostTopAmount  = "cddbd64d"
+	selectorPostMortgage   = "d4bdc612"
+)
+
+// legacyContract answers the raw selector calls of the V1 and V2 reward
+// algorithms from synthetic participants. Participants are listed once per
+// mining record, which V2 counts as their weight, and mortgages are reported in
+// whole NUC, the unit the deployed contract counted them in.
+type legacyContract struct {
+	lists  map[string][]common.Address // Selector => address list
+	ranks  map[string][]common.Address // Selector => fixed size ranking
+	counts map[common.Address]*big.Int // Mortgage counts of the ranked and PoST participants
+	posts  []*big.Int                  // Mortgage counts of the PoST participants, descending
+	ratio  *big.Int
+}
+
+// newLegacyContract serves the participants through the raw selectors of the
+// V1 and V2 rule contract.
+func newLegacyContract(p *Participants) *legacyContract {
+	c := &legacyContract{
+		lists:  make(map[string][]common.Address),
+		ranks:  make(map[string][]common.Address),
+		counts: make(map[common.Address]*big.Int),
+		ratio:  new(big.Int).SetUint64(p.RewardRatio),
+	}
+	count := func(v *math.HexOrDecimal256) *big.Int {
+		return new(big.Int).Div(amount(v), big.NewInt(params.Ether))
+	}
+	for _, u := range p.Pocers {
+		for i := 0; i < records(u.Records); i++ {
+			c.lists[selectorPocUsers] = append(c.lists[selectorPocUsers], u.Address)
+			if u.BindPool != (common.Address{}) || u.BindPow != (common.Address{}) {
+				c.lists[selectorValidPocUsers] = append(c.lists[selectorValidPocUsers], u.Address)
+			}
+		}
+	}
+	powers := make([]Power, len(p.Powers))
+	copy(powers, p.Powers)
+	sort.SliceStable(powers, func(i, j int) bool { return amount(powers[i].Mortgage).Cmp(amount(powers[j].Mortgage)) > 0 })
+	for _, u := range powers {
+		for i := 0; i < records(u.Records); i++ {
+			c.lists[selectorPowUsers] = append(c.lists[selectorPowUsers], u.Address)
+			if u.BindPool != (common.Address{}) {
+				c.lists[selectorValidPowUsers] = append(c.lists[selectorValidPowUsers], u.Address)
+			}
+		}
+		c.ranks[selectorPowRank] = append(c.ranks[selectorPowRank], u.Address)
+		c.lists[selectorPowRankPoc] = append(c.lists[selectorPowRankPoc], u.Pocs...)
+		c.counts[u.Address] = count(u.Mortgage)
+	}
+	poolers := make([]Pooler, len(p.Poolers))
+	copy(poolers, p.Poolers)
+	sort.SliceStable(poolers, func(i, j int) bool { return amount(poolers[i].Mortgage).Cmp(amount(poolers[j].Mortgage)) > 0 })
+	for _, u := range poolers {
+		c.lists[selectorPoolUsers] = append(c.lists[selectorPoolUsers], u.Address)
+		c.ranks[selectorPoolRank] = append(c.ranks[selectorPoolRank], u.Address)
+		c.lists[selectorPoolRankPow] = append(c.lists[selectorPoolRankPow], u.Pows...)
+		c.counts[u.Address] = count(u.Mortgage)
+	}
+	for _, u := range p.Posters {
+		c.lists[selectorPostUsers] = append(c.lists[selectorPostUsers], u.Address)
+		c.counts[u.Address] = count(u.Mortgage)
+		c.posts = append(c.posts, count(u.Mortgage))
+	}
+	sort.SliceStable(c.posts, func(i, j int) bool { return c.posts[i].Cmp(c.posts[j]) > 0 })
+	return c
+}
+
+// records returns the number of times a participant is listed, at least once.
+func records(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// call answers a single rule contract call.
+func (c *legacyContract) call(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errors.New("missing rule contract selector")
+	}
+	selector, args := hex.EncodeToString(input[:4]), input[4:]
+	switch selector {
+	case selectorOwner:
+		return make([]byte, 32), nil
+	case selectorRewardRatio:
+		return common.LeftPadBytes(c.ratio.Bytes(), 32), nil
+	case selectorPowCount:
+		return common.LeftPadBytes(big.NewInt(int64(len(c.lists[selectorPowUsers]))).Bytes(), 32), nil
+	case selectorPocUsers, selectorValidPocUsers, selectorPowUsers, selectorValidPowUsers,
+		selectorPoolUsers, selectorPostUsers, selectorPowRankPoc, selectorPoolRankPow:
+		// Dynamic address arrays: offset, length and the padded addresses
+		list := c.lists[selector]
+		out := append(common.LeftPadBytes(big.NewInt(32).Bytes(), 32), common.LeftPadBytes(big.NewInt(int64(len(list))).Bytes(), 32)...)
+		for _, addr := range list {
+			out = append(out, common.LeftPadBytes(addr.Bytes(), 32)...)
+		}
+		return out, nil
+	case selectorPowRank, selectorPoolRank:
+		// Fixed size address arrays: the padded addresses only
+		var out []byte
+		for _, addr := range c.ranks[selector] {
+			out = append(out, common.LeftPadBytes(addr.Bytes(), 32)...)
+		}
+		return out, nil
+	case selectorPowRankAmount, selectorPoolRankAmount:
+		rank := selectorPowRank
+		if selector == selectorPoolRankAmount {
+			rank = selectorPoolRank
+		}
+		total := new(big.Int)
+		for _, addr := range c.ranks[rank] {
+			total.Add(total, c.counts[addr])
+		}
+		return common.LeftPadBytes(total.Bytes(), 32), nil
+	case selectorPowMortgage, selectorPoolMortgage, selectorPostMortgage:
+		if len(args) != 32 {
+			return nil, fmt.Errorf("invalid arguments for selector 0x%s", selector)
+		}
+		if count := c.counts[common.BytesToAddress(args)]; count != nil {
+			return common.LeftPadBytes(count.Bytes(), 32), nil
+		}
+		return make([]byte, 32), nil
+	case selectorPostTopAmount:
+		if len(args) != 32 {
+			return nil, fmt.Errorf("invalid arguments for selector 0x%s", selector)
+		}
+		n := new(big.Int).SetBytes(args)
+		total := new(big.Int)
+		for i, count := range c.posts {
+			if big.NewInt(int64(i)).Cmp(n) >= 0 {
+				break
+			}
+			total.Add(total, count)
+		}
+		return common.LeftPadBytes(total.Bytes(), 32), nil
+	}
+	return nil, fmt.Errorf("unsupported rule contract selector 0x%s", selector)
+}
@@ -0,0 +1,254 @@
+// Copyright 2019 The nuc Team
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errNoState is returned when the reward algorithms ask for historical state,
+// which the simulator doesn't keep.
+var errNoState = errors.New("historical state not available in simulation")
+
+// AddressPayout is the reward credited to an address by a simulated block.
+type AddressPayout struct {
+	Address common.Address `json:"address"`
+	Poc     *big.Int       `json:"poc"`
+	Pow     *big.Int       `json:"pow"`
+	Pool    *big.Int       `json:"pool"` // Cuts of the bound PoC and PoW rewards for V4
+	Post    *big.Int       `json:"post"`
+	Total   *big.Int       `json:"total"`
+}
+
+// BlockPayout is the outcome of a simulated block.
+type BlockPayout struct {
+	Number   uint64           `json:"number"`
+	Version  uint64           `json:"version"` // Reward algorithm version in force
+	Payouts  []*AddressPayout `json:"payouts"`
+	Leftover *big.Int         `json:"leftover"` // Unassigned reward sent to ethash.DefaultCoinbaseAddr
+	Minted   *big.Int         `json:"minted"`
+	Supply   *big.Int         `json:"supply"` // Cumulative supply including the genesis allocation
+}
+
+// simulation runs the NUC reward algorithms on top of a genesis state, serving
+// the rule contract calls from synthetic participants. It implements the chain
+// reader the consensus engine needs.
+type simulation struct {
+	config   *params.ChainConfig
+	contract map[uint64]contractCaller // Rule contract per reward version
+	headers  map[common.Hash]*types.Header
+	numbers  map[uint64]*types.Header
+	current  *types.Header
+}
+
+// simulate runs the rewards of the blocks up to to, returning the payouts of the
+// blocks from from onwards. Blocks contain no transactions nor uncles and are
+// period seconds apart, mined by coinbase.
+func simulate(genesis *core.Genesis, participants *Participants, from, to, period uint64, coinbase common.Address) ([]*BlockPayout, error) {
+	if genesis.Config == nil {
+		return nil, errors.New("genesis has no chain config")
+	}
+	if from == 0 || from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	v0, err := newRuleContractV0(participants)
+	if err != nil {
+		return nil, err
+	}
+	v1, err := newRuleContractV1(participants)
+	if err != nil {
+		return nil, err
+	}
+	legacy := newLegacyContract(participants)
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		block = genesis.ToBlock(db)
+		sim   = &simulation{
+			config: genesis.Config,
+			contract: map[uint64]contractCaller{
+				params.NUCRewardV1: legacy,
+				params.NUCRewardV2: legacy,
+				params.NUCRewardV3: v0,
+				params.NUCRewardV4: v1,
+			},
+			headers: make(map[common.Hash]*types.Header),
+			numbers: make(map[uint64]*types.Header),
+		}
+		engine = ethash.NewFaker()
+		supply = new(big.Int)
+		result []*BlockPayout
+	)
+	sim.insert(block.Header())
+	for _, account := range genesis.Alloc {
+		if account.Balance != nil {
+			supply.Add(supply, account.Balance)
+		}
+	}
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	if err != nil {
+		return nil, err
+	}
+	for n := block.NumberU64() + 1; n <= to; n++ {
+		parent := sim.current
+		header := &types.Header{
+			ParentHash:    parent.Hash(),
+			Number:        new(big.Int).SetUint64(n),
+			Time:          parent.Time + period,
+			Coinbase:      coinbase,
+			Difficulty:    parent.Difficulty,
+			NUCDifficulty: parent.NUCDifficulty,
+			GasLimit:      parent.GasLimit,
+		}
+		version := sim.config.NUCRewardVersion(header.Number)
+		if _, ok := sim.contract[version]; !ok {
+			return nil, fmt.Errorf("block %d: reward version %d can't be simulated", n, version)
+		}
+		before := new(big.Int).Set(statedb.GetBalance(ethash.DefaultCoinbaseAddr))
+		if err := engine.Finalize(sim, header, statedb, nil, nil); err != nil {
+			return nil, fmt.Errorf("block %d: %v", n, err)
+		}
+		leftover := new(big.Int).Sub(statedb.GetBalance(ethash.DefaultCoinbaseAddr), before)
+
+		var rewards []types.CoinbaseTx
+		if version == params.NUCRewardV1 {
+			rewards, err = decodeRewardsV1(header.CoinbaseTxs)
+		} else {
+			rewards, err = types.DecodeBlockCoinbaseTxs(sim.config, header.Number, header.CoinbaseTxs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %v", n, err)
+		}
+		minted := new(big.Int).Set(leftover)
+		payout := &BlockPayout{Number: n, Version: version, Payouts: make([]*AddressPayout, 0, len(rewards)), Leftover: leftover}
+		for _, reward := range rewards {
+			total := reward.Total()
+			payout.Payouts = append(payout.Payouts, &AddressPayout{
+				Address: reward.Address,
+				Poc:     reward.PocReward,
+				Pow:     reward.PowReward,
+				Pool:    reward.PoolReward,
+				Post:    reward.PostReward,
+				Total:   total,
+			})
+			minted.Add(minted, total)
+		}
+		supply.Add(supply, minted)
+		payout.Minted = minted
+		payout.Supply = new(big.Int).Set(supply)
+		if n >= from {
+			result = append(result, payout)
+		}
+		sim.insert(header)
+	}
+	return result, nil
+}
+
+// decodeRewardsV1 parses the header.CoinbaseTxs written by the V1 reward
+// algorithm: a PoW, a PoC and a pool section, each made of the section type, the
+// reward per address on 8 bytes, the little endian address count on 4 bytes and
+// the addresses. The pool section runs to the end of the blob, as its count is
+// the PoW count rather than the number of pool addresses. The rewards are summed
+// per address, leaving out ethash.DefaultCoinbaseAddr whose credit is accounted
+// as leftover.
+func decodeRewardsV1(b []byte) ([]types.CoinbaseTx, error) {
+	var (
+		rewards = make(map[common.Address]*types.CoinbaseTx)
+		addrs   []common.Address
+	)
+	for _, kind := range []byte{ethash.NUC_POW, ethash.NUC_POC, ethash.NUC_POOL} {
+		if len(b) < 13 || b[0] != kind {
+			return nil, fmt.Errorf("malformed v1 coinbase txs section %d", kind)
+		}
+		reward := new(big.Int).SetBytes(b[1:9])
+		n := int(binary.LittleEndian.Uint32(b[9:13]))
+		if kind == ethash.NUC_POOL {
+			n = (len(b) - 13) / common.AddressLength
+		}
+		b = b[13:]
+		if len(b) < n*common.AddressLength {
+			return nil, fmt.Errorf("truncated v1 coinbase txs section %d", kind)
+		}
+		for ; n > 0; n-- {
+			addr := common.BytesToAddress(b[:common.AddressLength])
+			b = b[common.AddressLength:]
+			if addr == ethash.DefaultCoinbaseAddr {
+				continue
+			}
+			tx, ok := rewards[addr]
+			if !ok {
+				tx = &types.CoinbaseTx{Address: addr, PocReward: new(big.Int), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)}
+				rewards[addr] = tx
+				addrs = append(addrs, addr)
+			}
+			switch kind {
+			case ethash.NUC_POW:
+				tx.PowReward.Add(tx.PowReward, reward)
+			case ethash.NUC_POC:
+				tx.PocReward.Add(tx.PocReward, reward)
+			case ethash.NUC_POOL:
+				tx.PoolReward.Add(tx.PoolReward, reward)
+			}
+		}
+	}
+	if len(b) != 0 {
+		return nil, errors.New("trailing v1 coinbase txs data")
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	txs := make([]types.CoinbaseTx, len(addrs))
+	for i, addr := range addrs {
+		txs[i] = *rewards[addr]
+	}
+	return txs, nil
+}
+
+// insert makes a header the head of the simulated chain.
+func (s *simulation) insert(header *types.Header) {
+	s.headers[header.Hash()] = header
+	s.numbers[header.Number.Uint64()] = header
+	s.current = header
+}
+
+// CallRuleContract implements ethash.RuleContractBackend.
+func (s *simulation) CallRuleContract(header *types.Header, input []byte) ([]byte, error) {
+	version := s.config.NUCRewardVersion(header.Number)
+	contract, ok := s.contract[version]
+	if !ok {
+		return nil, fmt.Errorf("no rule contract for reward version %d", version)
+	}
+	return contract.call(input)
+}
+
+func (s *simulation) Config() *params.ChainConfig      { return s.config }
+func (s *simulation) ChainConfig() *params.ChainConfig { return s.config }
+func (s *simulation) CurrentHeader() *types.Header     { return s.current }
+
+func (s *simulation) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := s.headers[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func (s *simulation) GetHeaderByNumber(number uint64) *types.Header  { return s.numbers[number] }
+func (s *simulation) GetHeaderByHash(hash common.Hash) *types.Header { return s.headers[hash] }
+func (s *simulation) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if header := s.GetHeader(hash, number); header != nil {
+		return types.NewBlockWithHeader(header)
+	}
+	return nil
+}
+
+func (s *simulation) StateAt(root common.Hash) (*state.StateDB, error) { return nil, errNoState }
@@ -0,0 +1,153 @@
+// Copyright 2019 The nuc Team
+
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testPocBound   = common.HexToAddress("0x01")
+	testPocUnbound = common.HexToAddress("0x02")
+	testPool       = common.HexToAddress("0x03")
+	testFunded     = common.HexToAddress("0x04")
+)
+
+// nuc converts a NUC amount into wei.
+func nuc(amount float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(params.Ether)).Int(nil)
+	return wei
+}
+
+// newTestGenesis creates a genesis running the given reward version from the
+// first block on.
+func newTestGenesis(version uint64) *core.Genesis {
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{
+		CoinbaseTxsBlock: big.NewInt(0),
+		RewardRules:      []params.NUCRewardRule{{Block: big.NewInt(0), Version: version}},
+	}
+	return &core.Genesis{
+		Config: &config,
+		Alloc:  core.GenesisAlloc{testFunded: {Balance: nuc(100)}},
+	}
+}
+
+// newTestParticipants creates two PoC participants with the same weight, one of
+// them bound to a pool.
+func newTestParticipants() *Participants {
+	mortgage := (*math.HexOrDecimal256)(nuc(1000))
+	return &Participants{
+		Pocers: []Pocer{
+			{Address: testPocBound, Mortgage: mortgage, BindPool: testPool, BindPow: testPool, Records: 2},
+			{Address: testPocUnbound, Mortgage: mortgage, Records: 2},
+		},
+		Poolers: []Pooler{
+			{Address: testPool, Mortgage: mortgage, Pocs: []common.Address{testPocBound}},
+		},
+	}
+}
+
+// Tests that the V4 rewards cut 10% of a bound PoC reward for its pool, halve the
+// unbound ones and send whatever is left of the block reward to the default
+// coinbase.
+func TestSimulateV4(t *testing.T) {
+	payouts, err := simulate(newTestGenesis(params.NUCRewardV4), newTestParticipants(), 2, 3, 15, common.Address{})
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if len(payouts) != 2 {
+		t.Fatalf("payout count mismatch: have %d, want %d", len(payouts), 2)
+	}
+	// The PoC reward of 10 NUC is split 2.5 NUC per record
+	want := map[common.Address]*big.Int{
+		testPocBound:   nuc(4.5),
+		testPocUnbound: nuc(2.5),
+		testPool:       nuc(0.5),
+	}
+	for i, block := range payouts {
+		if block.Number != uint64(i+2) || block.Version != params.NUCRewardV4 {
+			t.Errorf("block %d: header mismatch: have #%d v%d", i, block.Number, block.Version)
+		}
+		have := make(map[common.Address]*big.Int)
+		for _, p := range block.Payouts {
+			have[p.Address] = p.Total
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("block %d: payouts mismatch: have %v, want %v", block.Number, have, want)
+		}
+		if block.Leftover.Cmp(nuc(4.5)) != 0 {
+			t.Errorf("block %d: leftover mismatch: have %v, want %v", block.Number, block.Leftover, nuc(4.5))
+		}
+		if block.Minted.Cmp(ethash.FrontierBlockReward) != 0 {
+			t.Errorf("block %d: minted mismatch: have %v, want %v", block.Number, block.Minted, ethash.FrontierBlockReward)
+		}
+		supply := new(big.Int).Mul(ethash.FrontierBlockReward, new(big.Int).SetUint64(block.Number))
+		supply.Add(supply, nuc(100))
+		if block.Supply.Cmp(supply) != 0 {
+			t.Errorf("block %d: supply mismatch: have %v, want %v", block.Number, block.Supply, supply)
+		}
+	}
+}
+
+// Tests that the V1 rewards are split evenly per listed record, the reward of the
+// missing PoW participant going to the default coinbase.
+func TestSimulateV1(t *testing.T) {
+	payouts, err := simulate(newTestGenesis(params.NUCRewardV1), newTestParticipants(), 1, 1, 15, common.Address{})
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if len(payouts) != 1 {
+		t.Fatalf("payout count mismatch: have %d, want %d", len(payouts), 1)
+	}
+	want := map[common.Address]*big.Int{
+		testPocBound:   nuc(5),
+		testPocUnbound: nuc(5),
+		testPool:       nuc(1),
+	}
+	have := make(map[common.Address]*big.Int)
+	for _, p := range payouts[0].Payouts {
+		have[p.Address] = p.Total
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("payouts mismatch: have %v, want %v", have, want)
+	}
+	if payouts[0].Leftover.Cmp(nuc(2)) != 0 {
+		t.Errorf("leftover mismatch: have %v, want %v", payouts[0].Leftover, nuc(2))
+	}
+	if payouts[0].Minted.Cmp(nuc(13)) != 0 {
+		t.Errorf("minted mismatch: have %v, want %v", payouts[0].Minted, nuc(13))
+	}
+}
+
+// Tests that simulations only depend on their inputs.
+func TestSimulateDeterministic(t *testing.T) {
+	for _, version := range []uint64{params.NUCRewardV1, params.NUCRewardV2, params.NUCRewardV3, params.NUCRewardV4} {
+		first, err := simulate(newTestGenesis(version), newTestParticipants(), 1, 4, 15, common.Address{})
+		if err != nil {
+			t.Fatalf("v%d: simulation failed: %v", version, err)
+		}
+		second, err := simulate(newTestGenesis(version), newTestParticipants(), 1, 4, 15, common.Address{})
+		if err != nil {
+			t.Fatalf("v%d: simulation failed: %v", version, err)
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("v%d: simulations differ", version)
+		}
+	}
+}
+
+// Tests that invalid block ranges are rejected.
+func TestSimulateInvalidRange(t *testing.T) {
+	if _, err := simulate(newTestGenesis(params.NUCRewardV4), newTestParticipants(), 3, 2, 15, common.Address{}); err == nil {
+		t.Errorf("invalid range accepted")
+	}
+}
@@ -410,6 +410,19 @@ var (
 		Name:  "miner.notify",
 		Usage: "Comma separated HTTP URL list to notify of new work packages",
 	}
+	MinerNotifyNUCFlag = cli.BoolFlag{
+		Name:  "miner.notify.nuc",
+		Usage: "Notify with NUC work packages (as served by eth_getWorkNUC) instead of classic ones",
+	}
+	MinerStratumFlag = cli.StringFlag{
+		Name:  "miner.stratum",
+		Usage: "Listening address of the Stratum (EthereumStratum/1.0.0) mining server (disabled if empty)",
+	}
+	MinerStratumDifficultyFlag = cli.Float64Flag{
+		Name:  "miner.stratum.diff",
+		Usage: "Share difficulty assigned to the Stratum workers (1 = 2^32 hashes)",
+		Value: ethash.DefaultStratumDifficulty,
+	}
 	MinerGasTargetFlag = cli.Uint64Flag{
 		Name:  "miner.gastarget",
 		Usage: "Target gas floor for mined blocks",
@@ -1301,6 +1314,11 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerNotifyFlag.Name) {
 		cfg.Notify = strings.Split(ctx.GlobalString(MinerNotifyFlag.Name), ",")
 	}
+	cfg.NotifyNUC = ctx.GlobalBool(MinerNotifyNUCFlag.Name)
+	if ctx.GlobalIsSet(MinerStratumFlag.Name) {
+		cfg.Stratum = ctx.GlobalString(MinerStratumFlag.Name)
+	}
+	cfg.StratumDifficulty = ctx.GlobalFloat64(MinerStratumDifficultyFlag.Name)
 	if ctx.GlobalIsSet(MinerLegacyExtraDataFlag.Name) {
 		cfg.ExtraData = []byte(ctx.GlobalString(MinerLegacyExtraDataFlag.Name))
 	}
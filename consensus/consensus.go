@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -54,6 +55,60 @@ type ChainReader interface {
 	ChainConfig() *params.ChainConfig
 }
 
+// SystemCaller is implemented by chains running the calls a consensus engine
+// makes to system contracts, e.g. the NUC rule contract, in the EVM. Engines
+// can't run the EVM against a chain on their own without depending on it.
+type SystemCaller interface {
+	// SystemCall runs a read-only call of a contract in the context of the given
+	// header, leaving the given state untouched, see core.SystemCall.
+	SystemCall(header *types.Header, statedb *state.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, error)
+
+	// ApplySystemMessage runs a call of a contract in the context of the given
+	// header as a message sent by from, applying its state changes to the given
+	// state, see core.ApplySystemMessage. Failed reports whether the call failed
+	// in the EVM.
+	ApplySystemMessage(header *types.Header, statedb vm.StateDB, from, to common.Address, input []byte, gas uint64) (ret []byte, failed bool, err error)
+}
+
+// NUCDifficultyVerifier is implemented by engines enforcing the NUC difficulty
+// discount rules. The rules depend on recent block bodies and the parent state,
+// so they can only be fully checked once those are available locally.
+type NUCDifficultyVerifier interface {
+	// VerifyNUCDifficulty checks the header's NUC difficulty against the
+	// discount rules active at its block number.
+	VerifyNUCDifficulty(chain ChainReader, header *types.Header) error
+}
+
+// SupplyReporter is implemented by engines able to tell how many coins the
+// rewards of a block created, without re-executing it.
+type SupplyReporter interface {
+	// BlockIssuance recomputes the coins created by the rewards of the given
+	// block from its header and body.
+	BlockIssuance(chain ChainReader, block *types.Block) (*types.BlockIssuance, error)
+}
+
+// ParticipantReporter is implemented by engines whose rewards are shared with
+// participants registered on chain, e.g. in the NUC rule contract.
+type ParticipantReporter interface {
+	// Participants returns the participants registered as of the given state,
+	// which is the post state of the given header's block. The state is left
+	// untouched.
+	Participants(chain ChainReader, header *types.Header, state *state.StateDB) []*types.Participant
+
+	// ParticipantRegistry returns the account holding the participants as of the
+	// given header's block. The participants don't change as long as neither the
+	// storage nor the code of the account do.
+	ParticipantRegistry(chain ChainReader, header *types.Header) common.Address
+}
+
+// RewardReporter is implemented by engines able to break down the rewards a
+// finalized block credits, e.g. to preview those of the block being sealed.
+type RewardReporter interface {
+	// BlockRewards recomputes the rewards credited by the given finalized block
+	// from its header, body and receipts.
+	BlockRewards(chain ChainReader, block *types.Block, receipts []*types.Receipt) (*types.BlockRewards, error)
+}
+
 // Engine is an algorithm agnostic consensus engine.
 type Engine interface {
 	// Author retrieves the Ethereum address of the account that minted the given
@@ -85,12 +140,13 @@ type Engine interface {
 	Prepare(chain ChainReader, header *types.Header) error
 
 	// Finalize runs any post-transaction state modifications (e.g. block rewards)
-	// but does not assemble the block.
+	// but does not assemble the block. An error is returned if the block breaks
+	// the consensus rules applied at finalization.
 	//
 	// Note: The block header and state database might be updated to reflect any
 	// consensus rules that happen at finalization (e.g. block rewards).
 	Finalize(chain ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
-		uncles []*types.Header)
+		uncles []*types.Header) error
 
 	// FinalizeAndAssemble runs any post-transaction state modifications (e.g. block
 	// rewards) and assembles the final block.
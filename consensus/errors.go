@@ -34,4 +34,12 @@ var (
 	// ErrInvalidNumber is returned if a block's number doesn't equal its parent's
 	// plus one.
 	ErrInvalidNumber = errors.New("invalid block number")
+
+	// ErrInvalidNUCTxCountDifficulty is returned if a block's NUC difficulty
+	// doesn't match the discount its miner earns by recently sent transactions.
+	ErrInvalidNUCTxCountDifficulty = errors.New("invalid nuc difficulty: tx count discount mismatch")
+
+	// ErrInvalidNUCBalanceDifficulty is returned if a block's NUC difficulty
+	// doesn't match the discount its miner earns by its balance.
+	ErrInvalidNUCBalanceDifficulty = errors.New("invalid nuc difficulty: balance discount mismatch")
 )
@@ -62,6 +62,34 @@ func (api *API) GetWork() ([4]string, error) {
 	}
 }
 
+// GetWorkNUC returns a NUC work package for external miners, carrying the
+// effective seal target along with the NUC difficulty, version, coinbase and
+// projected rewards of the block being sealed. Solutions are submitted through
+// SubmitWork against its seal hash.
+func (api *API) GetWorkNUC() (*NUCWork, error) {
+	if api.ethash.config.PowMode != ModeNormal && api.ethash.config.PowMode != ModeTest {
+		return nil, errors.New("not supported")
+	}
+
+	var (
+		workCh = make(chan *NUCWork, 1)
+		errc   = make(chan error, 1)
+	)
+
+	select {
+	case api.ethash.fetchWorkCh <- &sealWork{errc: errc, nuc: workCh}:
+	case <-api.ethash.exitCh:
+		return nil, errEthashStopped
+	}
+
+	select {
+	case work := <-workCh:
+		return work, nil
+	case err := <-errc:
+		return nil, err
+	}
+}
+
 // SubmitWork can be used by external miner to submit their POW solution.
 // It returns an indication if the work was accepted.
 // Note either an invalid solution, a stale work a non-existent work will return false.
@@ -23,7 +23,6 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/bitutil"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"math/big"
 	"runtime"
@@ -36,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"golang.org/x/crypto/sha3"
@@ -60,7 +60,6 @@ var (
 	ConstantinopleBlockReward = big.NewInt(2e+18)                                        // Block reward in wei for successfully mining a block upward from Constantinople
 	maxUncles                 = 2                                                        // Maximum number of uncles allowed in a single block
 	allowedFutureBlockTime    = 60 * time.Second                                         // Max time from current time allowed for blocks, before they're considered future blocks
-	NucRuleContractAddr       = common.HexToAddress("0000000000000000000000000000000000000011")
 	// calcDifficultyConstantinople is the difficulty adjustment algorithm for Constantinople.
 	// It returns the difficulty that a new block should have when created at time given the
 	// parent block's time and difficulty. The calculation uses the Byzantium rules, but with
@@ -90,6 +89,10 @@ var (
 	errInvalidDifficulty = errors.New("non-positive difficulty")
 	errInvalidMixDigest  = errors.New("invalid mix digest")
 	errInvalidPoW        = errors.New("invalid proof-of-work")
+
+	// errUnknownRewardVersion is returned when the chain config schedules a
+	// reward algorithm version no implementation exists for.
+	errUnknownRewardVersion = errors.New("unknown reward version")
 )
 
 // Author implements consensus.Engine, returning the header's coinbase as the
@@ -301,6 +304,14 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainReader, header, parent *
 	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(big.NewInt(1)) != 0 {
 		return consensus.ErrInvalidNumber
 	}
+	// Verify the NUC difficulty discounts, unless the parent block and state
+	// aren't imported yet (e.g. batch verification), in which case the check is
+	// done again once the block body is validated.
+	switch err := ethash.VerifyNUCDifficulty(chain, header); err {
+	case nil, consensus.ErrUnknownAncestor, consensus.ErrPrunedAncestor:
+	default:
+		return err
+	}
 	// Verify the engine specific seal securing the block
 	if seal {
 		if err := ethash.VerifySeal(chain, header); err != nil {
@@ -476,7 +487,6 @@ func calcDifficultyFrontier(time uint64, parent *types.Header) *big.Int {
 	bigTime.SetUint64(time)
 	bigParentTime.SetUint64(parent.Time)
 	// offset := bigTime.Sub(bigTime, bigParentTime)
-	// fmt.Println("difficulty ajustment ===========================", offset.Uint64(), params.DurationLimit, adjust, parent.Difficulty)
 	if bigTime.Sub(bigTime, bigParentTime).Cmp(params.DurationLimit) < 0 {
 		diff.Add(parent.Difficulty, adjust)
 	} else {
@@ -584,17 +594,22 @@ func (ethash *Ethash) Prepare(chain consensus.ChainReader, header *types.Header)
 
 // Finalize implements consensus.Engine, accumulating the block and uncle rewards,
 // setting the final state on the header
-func (ethash *Ethash) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
+func (ethash *Ethash) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) error {
 	// Accumulate any block and uncle rewards and commit the final state root
-	accumulateRewards(chain, state, header, uncles, txs)
+	if err := accumulateRewards(chain, state, header, uncles, txs); err != nil {
+		return err
+	}
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	return nil
 }
 
 // FinalizeAndAssemble implements consensus.Engine, accumulating the block and
 // uncle rewards, setting the final state and assembling the block.
 func (ethash *Ethash) FinalizeAndAssemble(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
 	// Accumulate any block and uncle rewards and commit the final state root
-	accumulateRewards(chain, state, header, uncles, txs)
+	if err := accumulateRewards(chain, state, header, uncles, txs); err != nil {
+		return nil, err
+	}
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 
 	// Header seems complete, assemble into a block and return
@@ -639,34 +654,72 @@ const (
 	NUC_POOL = 2
 )
 
+// rewardAlgorithm computes the per address rewards of a block.
+type rewardAlgorithm func(header *types.Header, state *state.StateDB, c consensus.ChainReader) *CoinbaseTxs
+
+// rewardAlgorithms maps the reward versions that can be scheduled in the chain
+// config to their implementation. NUCRewardV1 predates the CoinbaseTxs based
+// accounting and is handled by accumulateRewardsold.
+var rewardAlgorithms = map[uint64]rewardAlgorithm{
+	params.NUCRewardV2: NUCReward2,
+	params.NUCRewardV3: NUCReward3,
+	params.NUCRewardV4: NUCReward4,
+}
+
+// accumulateRewards credits the block rewards using the reward algorithm the
+// chain config schedules for the header's block number, rejecting the block if
+// no such algorithm exists.
 func accumulateRewards(c consensus.ChainReader, state *state.StateDB, header *types.Header,
-	uncles []*types.Header, txs []*types.Transaction) {
+	uncles []*types.Header, txs []*types.Transaction) error {
+	version := c.Config().NUCRewardVersion(header.Number)
+	if version == params.NUCRewardV1 {
+		accumulateRewardsold(c, state, header, uncles, txs)
+		return nil
+	}
+	algorithm, ok := rewardAlgorithms[version]
+	if !ok {
+		return errUnknownRewardVersion
+	}
+	return accumulateNUCRewards(c, state, header, uncles, txs, algorithm)
+}
+
+// accumulateNUCRewards credits every address returned by the reward algorithm
+// with its rewards plus an equal share of the transaction fees, and records the
+// rewards in the header. From the NUC supply invariant fork on, the block is
+// rejected if the coins credited don't match the issuance accounted from the
+// recorded rewards; before it, the mismatch is only logged.
+func accumulateNUCRewards(c consensus.ChainReader, state *state.StateDB, header *types.Header,
+	uncles []*types.Header, txs []*types.Transaction, algorithm rewardAlgorithm) error {
 	blockReward := FrontierBlockReward
-	fee := big.NewInt(0)
-	r := new(big.Int)
+	credited := big.NewInt(0)
 	for _, uncle := range uncles {
-		r.Add(uncle.Number, big8)
-		r.Sub(r, header.Number)
-		r.Mul(r, blockReward)
-		r.Div(r, big8)
+		r := uncleReward(header, uncle, blockReward)
 		state.AddBalance(uncle.Coinbase, r)
-
-		r.Div(blockReward, big32)
-		fee.Add(fee, r)
-	}
-	for _, tx := range txs {
-		fee.Add(fee, new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas())))
-		// 70% fee to pow and pool
-		fee = fee.Mul(fee, big.NewInt(70))
-		fee = fee.Div(fee, big.NewInt(100))
+		credited.Add(credited, r)
 	}
-	teamFee := big.NewInt(0).Mul(fee, big.NewInt(5))
-	teamFee = teamFee.Div(teamFee, big.NewInt(100))
-	powFee := big.NewInt(0).Mul(fee, big.NewInt(95))
-	powFee = powFee.Div(powFee, big.NewInt(100))
+	var (
+		policy   = c.Config().NUCFeePolicy(header.Number)
+		treasury = DefaultCoinbaseAddr
+		teamFee  *big.Int
+		powFee   *big.Int
+		fees     *big.Int
+	)
+	if policy == nil {
+		teamFee, powFee = splitNUCFee(nucFee(txs, uncles, blockReward))
+	} else {
+		// The state transition split the transaction fees already, pooling the
+		// participants' share, which is shared out along with the includer reward
+		pooled := new(big.Int).Set(state.GetBalance(params.NUCFeePoolAddress))
+		state.SubBalance(params.NUCFeePoolAddress, pooled)
+		credited.Sub(credited, pooled)
 
+		treasury = policy.Treasury
+		powFee = new(big.Int).Add(pooled, includerReward(uncles, blockReward))
+		fees = new(big.Int).Set(powFee)
+	}
 	allBlockReward := big.NewInt(0)
-	ctxs := NUCReward4(header, state, c)
+	scheduled := big.NewInt(0)
+	ctxs := algorithm(header, state, c)
 	if len(*ctxs) > 0 {
 		powFee = powFee.Div(powFee, big.NewInt(int64(len(*ctxs))))
 	}
@@ -690,28 +743,52 @@ func accumulateRewards(c consensus.ChainReader, state *state.StateDB, header *ty
 		//all reward = poc reward + pow reward + pool reward + post reward
 		reward := new(big.Int).Add(pocReward, powReward)
 		reward = reward.Add(reward, poolReward)
+		scheduled.Add(scheduled, reward)
 		reward = reward.Add(reward, powFee)
 		if reward.Cmp(big.NewInt(0)) > 0 {
 			state.AddBalance(addr, reward)
 		}
 		allBlockReward.Add(allBlockReward, reward)
 	}
-	if allBlockReward.Cmp(blockReward) < 0 {
-		leftReward := big.NewInt(0).Sub(blockReward, allBlockReward)
+	credited.Add(credited, allBlockReward)
+
+	// Under a fee policy, the fees don't cut into the block reward, and the fees
+	// no participant got (rounding dust included) go to the treasury
+	minted := allBlockReward
+	if policy != nil {
+		minted = scheduled
+		teamFee = fees.Sub(fees, new(big.Int).Sub(allBlockReward, scheduled))
+	}
+	if minted.Cmp(blockReward) < 0 {
+		leftReward := big.NewInt(0).Sub(blockReward, minted)
 		state.AddBalance(DefaultCoinbaseAddr, leftReward)
+		credited.Add(credited, leftReward)
 	}
 	if teamFee.Cmp(big.NewInt(0)) > 0 {
-		state.AddBalance(DefaultCoinbaseAddr, teamFee)
+		state.AddBalance(treasury, teamFee)
+		credited.Add(credited, teamFee)
 	}
-	header.CoinbaseTxs = ctxs.Encode()
-	_ = ctxs.Save(header.Number)
+	rewards := ctxs.Entries()
+	coinbaseTxs, err := types.EncodeBlockCoinbaseTxs(c.Config(), header.Number, rewards)
+	if err != nil {
+		return err
+	}
+	header.CoinbaseTxs = coinbaseTxs
+
+	if err := checkIssuance(header, rewards, txs, uncles, policy, credited); err != nil {
+		if c.Config().IsNUCSupplyInvariant(header.Number) {
+			return fmt.Errorf("supply invariant violated: %v", err)
+		}
+		log.Error("NUC supply invariant violated", "number", header.Number, "err", err)
+	}
+	return nil
 }
 
 // AccumulateRewards credits the coinbase of the given block with the mining
 // reward. The total reward consists of the static block reward and rewards for
 // included uncles. The coinbase of each uncle block is also rewarded.
 func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header *types.Header, uncles []*types.Header, txs []*types.Transaction) {
-	header.Coinbase = GetCoinbase(header, c.(*core.BlockChain), state)
+	header.Coinbase = GetCoinbase(header, c, state)
 	// Select the correct block reward based on chain progression
 	blockReward := FrontierBlockReward
 	// if c.Config().IsByzantium(header.Number) {
@@ -742,7 +819,7 @@ func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header
 
 	// ========================== get reward reduce ratio ===========================
 	data := common.FromHex("0x76b8dde1")
-	ratio := GetReduceRatio(data, header, c.(*core.BlockChain), state)
+	ratio := GetReduceRatio(data, header, c, state)
 	if ratio == 25 && header.Number.Cmp(big.NewInt(10000)) <= 0 {
 		ratio = 0
 	}
@@ -760,11 +837,11 @@ func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header
 	powFee = powFee.Div(powFee, big.NewInt(100))
 	powReward = powReward.Add(powReward, powFee)
 	data = common.FromHex("0xcca1aa47") //pow Count
-	powCount := CallPowCount(data, header, c.(*core.BlockChain), state)
+	powCount := CallPowCount(data, header, c, state)
 	//pow users
 	data = common.FromHex("0x93510d62")
 
-	pows := CallContract(data, header, c.(*core.BlockChain), state)
+	pows := CallContract(data, header, c, state)
 	l := len(pows)
 
 	if powCount > 1 {
@@ -804,8 +881,8 @@ func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header
 	}
 
 	data = common.FromHex("0x4d4df113")
-	CallContract(data, header, c.(*core.BlockChain), state)
-	pocs := CallContract(data, header, c.(*core.BlockChain), state)
+	CallContract(data, header, c, state)
+	pocs := CallContract(data, header, c, state)
 
 	l = len(pocs)
 	if l > 1 {
@@ -841,7 +918,7 @@ func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header
 	poolFee = poolFee.Div(poolFee, big.NewInt(100))
 	poolReward = poolReward.Add(poolReward, poolFee)
 
-	pools := CallContract(data, header, c.(*core.BlockChain), state)
+	pools := CallContract(data, header, c, state)
 	l = len(pools)
 	if powCount > 1 {
 		poolReward = poolReward.Div(poolReward, big.NewInt(powCount))
@@ -873,102 +950,66 @@ func accumulateRewardsold(c consensus.ChainReader, state *state.StateDB, header
 	// state.AddBalance(header.Coinbase, blockReward)
 }
 
-func CallContract(input []byte, header *types.Header, c *core.BlockChain, state *state.StateDB) []common.Address {
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-		} else {
-			return GetAddressesFromContractHex(hex.EncodeToString(res))
-		}
+func CallContract(input []byte, header *types.Header, c consensus.ChainReader, state vm.StateDB) []common.Address {
+	res, err := applyRuleCall(input, header, c, state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+	} else {
+		return GetAddressesFromContractHex(hex.EncodeToString(res))
 	}
 	return []common.Address{}
 }
 
-func CallContractArr(input []byte, header *types.Header, c *core.BlockChain, state *state.StateDB) []common.Address {
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-		} else {
-			return GetArrayAddressesFromContractHex(hex.EncodeToString(res))
-		}
+func CallContractArr(input []byte, header *types.Header, c consensus.ChainReader, state vm.StateDB) []common.Address {
+	res, err := applyRuleCall(input, header, c, state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+	} else {
+		return GetArrayAddressesFromContractHex(hex.EncodeToString(res))
 	}
 	return []common.Address{}
 }
 
-func CallPowCount(input []byte, header *types.Header, c *core.BlockChain, state *state.StateDB) int64 {
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-		} else {
-			bigN := new(big.Int)
-			bigN.SetBytes(res)
-			return int64(bigN.Uint64())
-		}
+func CallPowCount(input []byte, header *types.Header, c consensus.ChainReader, state vm.StateDB) int64 {
+	res, err := applyRuleCall(input, header, c, state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+	} else {
+		bigN := new(big.Int)
+		bigN.SetBytes(res)
+		return int64(bigN.Uint64())
 	}
 	return 0
 }
 
-func GetCoinbase(header *types.Header, c *core.BlockChain, state *state.StateDB) common.Address {
+func GetCoinbase(header *types.Header, c consensus.ChainReader, state vm.StateDB) common.Address {
 	//coinbase 0x8da5cb5b
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), common.FromHex("0x8da5cb5b"), false)
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println("coinbase get ==========coinbase call contract error", failed, err)
-		} else {
-			addr := common.BytesToAddress(res)
-			if addr.String() == "0x0000000000000000000000000000000000000000" {
-				return DefaultCoinbaseAddr
-			}
-			return addr
+	res, err := applyRuleCall(common.FromHex("0x8da5cb5b"), header, c, state)
+	if err != nil {
+		log.Debug("Rule contract coinbase lookup failed", "err", err)
+	} else {
+		addr := common.BytesToAddress(res)
+		if addr.String() == "0x0000000000000000000000000000000000000000" {
+			return DefaultCoinbaseAddr
 		}
+		return addr
 	}
 	return DefaultCoinbaseAddr
 }
 
-func GetReduceRatio(input []byte, header *types.Header, c *core.BlockChain, state *state.StateDB) uint64 {
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-		} else {
-			// fmt.Println(hex.EncodeToString(res), "hex.EncodeToString(res)")
-			if res == nil || len(res) < 1 {
-				return 1
-			}
-			r := bitutil.NewUint256FromString(hex.EncodeToString(res))
-			// fmt.Println("ratio:", r.BigInt().Uint64())
-			if r.BigInt().Uint64() <= 0 {
-				return 0
-			}
-			return r.BigInt().Uint64()
+func GetReduceRatio(input []byte, header *types.Header, c consensus.ChainReader, state vm.StateDB) uint64 {
+	res, err := applyRuleCall(input, header, c, state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+	} else {
+		if res == nil || len(res) < 1 {
+			return 1
 		}
+		r := bitutil.NewUint256FromString(hex.EncodeToString(res))
+		if r.BigInt().Uint64() <= 0 {
+			return 0
+		}
+		return r.BigInt().Uint64()
 	}
 	return 0
 }
@@ -995,7 +1036,7 @@ func GetAddressesFromContractHex(h string) []common.Address {
 }
 
 func GetArrayAddressesFromContractHex(h string) []common.Address {
-	if len(h)%64 != 0 {
+	if len(h) == 0 || len(h)%64 != 0 {
 		return []common.Address{}
 	}
 	res := make([]common.Address, 0)
@@ -0,0 +1,98 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nucDiscountRule is a single NUC difficulty discount rule.
+type nucDiscountRule struct {
+	apply    func(diff *big.Int) *big.Int // Applies the rule to diff
+	divisors []uint64                     // Divisors the rule may discount by
+}
+
+// VerifyNUCDifficulty implements consensus.NUCDifficultyVerifier, checking the
+// header's NUC difficulty against the tx count and balance discount rules that
+// are enforced at its block number. A rule that isn't enforced yet may have been
+// applied by the miner or not, both are accepted.
+//
+// The rules need the parent block's state and the bodies of the recent blocks,
+// consensus.ErrUnknownAncestor or consensus.ErrPrunedAncestor is returned if
+// those aren't available locally.
+func (ethash *Ethash) VerifyNUCDifficulty(chain consensus.ChainReader, header *types.Header) error {
+	// If we're running a full engine faking, accept any input as valid
+	if ethash.config.PowMode == ModeFullFake {
+		return nil
+	}
+	var (
+		config        = chain.Config()
+		txActive      = config.IsNUCTxCountDiscount(header.Number)
+		balanceActive = config.IsNUCBalanceDiscount(header.Number)
+	)
+	if !txActive && !balanceActive {
+		return nil
+	}
+	number := header.Number.Uint64()
+	parent := chain.GetBlock(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	if _, err := chain.StateAt(parent.Root()); err != nil {
+		return consensus.ErrPrunedAncestor
+	}
+	rules := config.NUCDifficultyRules(header.Number)
+	txRule := nucDiscountRule{
+		apply: func(diff *big.Int) *big.Int {
+			discounted, _ := consensus.GetNUCDifficultyByTxCount(*diff, chain, header.ParentHash, number-1, header.Coinbase, 0)
+			return discounted
+		},
+		divisors: []uint64{rules.TxDivisor},
+	}
+	balanceRule := nucDiscountRule{
+		apply: func(diff *big.Int) *big.Int {
+			return consensus.GetNUCDifficultyByMinerAccount(*diff, header.Coinbase, chain, header.ParentHash, number-1)
+		},
+	}
+	for _, tier := range rules.BalanceTiers {
+		balanceRule.divisors = append(balanceRule.divisors, tier.Divisor)
+	}
+	if matchNUCDifficulty(header, txRule, txActive, balanceRule, balanceActive) {
+		return nil
+	}
+	// Blame the tx count rule if relaxing it alone would make the header valid
+	if !balanceActive || matchNUCDifficulty(header, txRule, false, balanceRule, true) {
+		return consensus.ErrInvalidNUCTxCountDifficulty
+	}
+	return consensus.ErrInvalidNUCBalanceDifficulty
+}
+
+// matchNUCDifficulty reports whether the header's NUC difficulty can be derived
+// from its difficulty by applying the tx count rule followed by the balance rule.
+func matchNUCDifficulty(header *types.Header, txRule nucDiscountRule, txActive bool, balanceRule nucDiscountRule, balanceActive bool) bool {
+	for _, diff := range nucDifficultyCandidates(header.Difficulty, txRule, txActive) {
+		for _, nucdiff := range nucDifficultyCandidates(diff, balanceRule, balanceActive) {
+			if nucdiff.Cmp(header.NUCDifficulty) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nucDifficultyCandidates returns the difficulties acceptable after a discount
+// rule: the exact outcome of an enforced rule, or either the plain or any of the
+// discounted difficulties of a rule that isn't enforced yet.
+func nucDifficultyCandidates(diff *big.Int, rule nucDiscountRule, active bool) []*big.Int {
+	if active {
+		return []*big.Int{rule.apply(diff)}
+	}
+	candidates := []*big.Int{diff}
+	for _, divisor := range rule.divisors {
+		candidates = append(candidates, consensus.NUCDifficultyDiscount(diff, divisor))
+	}
+	return candidates
+}
@@ -0,0 +1,373 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// nucTestChain is a minimal consensus.ChainReader backed by in-memory blocks
+// sharing a single state.
+type nucTestChain struct {
+	config *params.ChainConfig
+	db     state.Database
+	blocks map[common.Hash]*types.Block
+	head   *types.Block
+}
+
+func (c *nucTestChain) Config() *params.ChainConfig      { return c.config }
+func (c *nucTestChain) ChainConfig() *params.ChainConfig { return c.config }
+func (c *nucTestChain) CurrentHeader() *types.Header     { return c.head.Header() }
+
+func (c *nucTestChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := c.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+func (c *nucTestChain) GetHeaderByNumber(number uint64) *types.Header {
+	for _, block := range c.blocks {
+		if block.NumberU64() == number {
+			return block.Header()
+		}
+	}
+	return nil
+}
+
+func (c *nucTestChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	if block, ok := c.blocks[hash]; ok {
+		return block.Header()
+	}
+	return nil
+}
+
+func (c *nucTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := c.blocks[hash]; ok && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
+
+func (c *nucTestChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db)
+}
+
+// newNUCTestChain creates a chain of length blocks on top of a genesis funding
+// the rich account with 2000 NUC. Every block contains txsPerBlock transactions
+// sent by the rich account.
+func newNUCTestChain(t *testing.T, config *params.ChainConfig, length int, txsPerBlock int) (*nucTestChain, common.Address) {
+	return newNUCTestChainFunded(t, config, length, txsPerBlock, 2000)
+}
+
+// newNUCTestChainFunded is newNUCTestChain funding the rich account with the
+// given number of NUC.
+func newNUCTestChainFunded(t *testing.T, config *params.ChainConfig, length int, txsPerBlock int, funds int64) (*nucTestChain, common.Address) {
+	key, _ := crypto.GenerateKey()
+	rich := crypto.PubkeyToAddress(key.PublicKey)
+
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, db)
+	statedb.AddBalance(rich, new(big.Int).Mul(big.NewInt(funds), big.NewInt(params.Ether)))
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit genesis state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit genesis trie: %v", err)
+	}
+	chain := &nucTestChain{config: config, db: db, blocks: make(map[common.Hash]*types.Block)}
+	chain.head = types.NewBlock(&types.Header{
+		Number:        big.NewInt(0),
+		Root:          root,
+		Time:          uint64(time.Now().Unix()) - 1000,
+		GasLimit:      8000000,
+		Difficulty:    big.NewInt(1000000),
+		NUCDifficulty: big.NewInt(1000000),
+	}, nil, nil, nil)
+	chain.blocks[chain.head.Hash()] = chain.head
+
+	signer := types.NewEIP155Signer(config.ChainID)
+	nonce := uint64(0)
+	for i := 0; i < length; i++ {
+		var txs []*types.Transaction
+		for j := 0; j < txsPerBlock; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+			txs = append(txs, tx)
+			nonce++
+		}
+		parent := chain.head.Header()
+		chain.head = types.NewBlock(&types.Header{
+			ParentHash:    parent.Hash(),
+			Number:        new(big.Int).Add(parent.Number, big1),
+			Root:          root,
+			Time:          parent.Time + 10,
+			GasLimit:      parent.GasLimit,
+			Difficulty:    parent.Difficulty,
+			NUCDifficulty: parent.Difficulty,
+		}, txs, nil, nil)
+		chain.blocks[chain.head.Hash()] = chain.head
+	}
+	return chain, rich
+}
+
+// nucTestHeader creates the next header of the chain, mined by coinbase.
+func nucTestHeader(chain *nucTestChain, coinbase common.Address, nucdiff func(diff *big.Int) *big.Int) *types.Header {
+	parent := chain.head.Header()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   coinbase,
+		Number:     new(big.Int).Add(parent.Number, big1),
+		Root:       parent.Root,
+		Time:       parent.Time + 10,
+		GasLimit:   parent.GasLimit,
+	}
+	header.Difficulty = CalcDifficulty(chain.config, header.Time, parent)
+	header.NUCDifficulty = nucdiff(header.Difficulty)
+	return header
+}
+
+func TestVerifyNUCDifficulty(t *testing.T) {
+	var (
+		none    = func(diff *big.Int) *big.Int { return new(big.Int).Set(diff) }
+		half    = func(diff *big.Int) *big.Int { return new(big.Int).Div(diff, big.NewInt(2)) }
+		quarter = func(diff *big.Int) *big.Int { return new(big.Int).Div(diff, big.NewInt(4)) }
+		forged  = func(diff *big.Int) *big.Int { return new(big.Int).Div(diff, big.NewInt(1000)) }
+		poor    = common.HexToAddress("0x00000000000000000000000000000000000000ff")
+	)
+	newConfig := func(txCount, balance *big.Int) *params.ChainConfig {
+		return &params.ChainConfig{
+			ChainID: big.NewInt(100),
+			Ethash:  new(params.EthashConfig),
+			NUC:     &params.NUCConfig{TxCountDiscountBlock: txCount, BalanceDiscountBlock: balance},
+		}
+	}
+	tests := []struct {
+		config  *params.ChainConfig
+		rich    bool
+		nucdiff func(diff *big.Int) *big.Int
+		err     error
+	}{
+		// No rule enforced, any NUC difficulty is accepted
+		{newConfig(nil, nil), true, forged, nil},
+		{newConfig(nil, nil), false, quarter, nil},
+
+		// Both rules enforced
+		{newConfig(common.Big0, common.Big0), true, quarter, nil},
+		{newConfig(common.Big0, common.Big0), true, half, consensus.ErrInvalidNUCTxCountDifficulty},
+		{newConfig(common.Big0, common.Big0), true, none, consensus.ErrInvalidNUCBalanceDifficulty},
+		{newConfig(common.Big0, common.Big0), true, forged, consensus.ErrInvalidNUCBalanceDifficulty},
+		{newConfig(common.Big0, common.Big0), false, none, nil},
+		{newConfig(common.Big0, common.Big0), false, half, consensus.ErrInvalidNUCTxCountDifficulty},
+
+		// Only the balance rule enforced, the tx count discount is optional
+		{newConfig(nil, common.Big0), true, half, nil},
+		{newConfig(nil, common.Big0), true, quarter, nil},
+		{newConfig(nil, common.Big0), true, none, consensus.ErrInvalidNUCBalanceDifficulty},
+		{newConfig(nil, common.Big0), false, half, nil},
+		{newConfig(nil, common.Big0), false, quarter, consensus.ErrInvalidNUCBalanceDifficulty},
+
+		// Only the tx count rule enforced, the balance discount is optional
+		{newConfig(common.Big0, nil), true, half, nil},
+		{newConfig(common.Big0, nil), true, quarter, nil},
+		{newConfig(common.Big0, nil), true, none, consensus.ErrInvalidNUCTxCountDifficulty},
+		{newConfig(common.Big0, nil), false, forged, consensus.ErrInvalidNUCTxCountDifficulty},
+
+		// Rules scheduled after the verified block aren't enforced yet
+		{newConfig(big.NewInt(100), big.NewInt(100)), true, forged, nil},
+	}
+	for i, tt := range tests {
+		chain, rich := newNUCTestChain(t, tt.config, 3, 4)
+		coinbase := poor
+		if tt.rich {
+			coinbase = rich
+		}
+		header := nucTestHeader(chain, coinbase, tt.nucdiff)
+
+		ethash := NewFaker()
+		if err := ethash.VerifyNUCDifficulty(chain, header); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err := ethash.VerifyHeader(chain, header, false); err != tt.err {
+			t.Errorf("test %d: header verification error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that the NUC difficulty discounts follow the difficulty rules scheduled
+// in the chain config, the miner and the verifier agreeing on every tier.
+func TestNUCDifficultyRules(t *testing.T) {
+	tiered := params.NUCDifficultyRules{
+		Block:        common.Big0,
+		TxCount:      10,
+		TxBlocks:     6,
+		TxDivisor:    2,
+		BalanceTiers: []params.NUCBalanceTier{{Balance: 1000, Divisor: 2}, {Balance: 10000, Divisor: 4}},
+	}
+	withRules := func(update func(rules *params.NUCDifficultyRules)) []params.NUCDifficultyRules {
+		rules := tiered
+		update(&rules)
+		return []params.NUCDifficultyRules{rules}
+	}
+	tests := []struct {
+		rules   []params.NUCDifficultyRules
+		txs     int   // Transactions sent by the coinbase per block
+		funds   int64 // Balance of the coinbase in NUC
+		divisor int64 // Expected overall discount
+	}{
+		// Defaults: halved for the tx count, halved again above 1000 NUC
+		{nil, 4, 500, 2},
+		{nil, 4, 2000, 4},
+		{nil, 4, 20000, 4},
+		{nil, 0, 20000, 2},
+
+		// Balance tiers, only the highest reached one applies
+		{withRules(func(*params.NUCDifficultyRules) {}), 0, 1000, 1},
+		{withRules(func(*params.NUCDifficultyRules) {}), 0, 1001, 2},
+		{withRules(func(*params.NUCDifficultyRules) {}), 0, 10001, 4},
+		{withRules(func(*params.NUCDifficultyRules) {}), 4, 10001, 8},
+		{withRules(func(r *params.NUCDifficultyRules) { r.BalanceTiers = nil }), 4, 20000, 2},
+
+		// Tx count parameters
+		{withRules(func(r *params.NUCDifficultyRules) { r.TxCount = 20 }), 4, 500, 1},
+		{withRules(func(r *params.NUCDifficultyRules) { r.TxBlocks = 2 }), 4, 500, 1},
+		{withRules(func(r *params.NUCDifficultyRules) { r.TxBlocks = 3 }), 4, 500, 2},
+		{withRules(func(r *params.NUCDifficultyRules) { r.TxDivisor = 3 }), 4, 2000, 6},
+
+		// Rules scheduled after the verified block aren't in force yet
+		{withRules(func(r *params.NUCDifficultyRules) { r.Block = big.NewInt(100) }), 4, 20000, 4},
+	}
+	for i, tt := range tests {
+		config := &params.ChainConfig{
+			ChainID: big.NewInt(100),
+			Ethash:  new(params.EthashConfig),
+			NUC:     &params.NUCConfig{TxCountDiscountBlock: common.Big0, BalanceDiscountBlock: common.Big0, DifficultyRules: tt.rules},
+		}
+		if err := config.CheckConfigForkOrder(); err != nil {
+			t.Fatalf("test %d: invalid config: %v", i, err)
+		}
+		chain, rich := newNUCTestChainFunded(t, config, 3, tt.txs, tt.funds)
+
+		// Mine the header the way the worker does
+		header := nucTestHeader(chain, rich, func(diff *big.Int) *big.Int { return nil })
+		number := header.Number.Uint64()
+		header.NUCDifficulty, _ = consensus.GetNUCDifficultyByTxCount(*header.Difficulty, chain, header.ParentHash, number-1, header.Coinbase, 0)
+		header.NUCDifficulty = consensus.GetNUCDifficultyByMinerAccount(*header.NUCDifficulty, header.Coinbase, chain, header.ParentHash, number-1)
+
+		if want := new(big.Int).Div(header.Difficulty, big.NewInt(tt.divisor)); header.NUCDifficulty.Cmp(want) != 0 {
+			t.Errorf("test %d: mined NUC difficulty mismatch: have %v, want %v", i, header.NUCDifficulty, want)
+		}
+		ethash := NewFaker()
+		if err := ethash.VerifyNUCDifficulty(chain, header); err != nil {
+			t.Errorf("test %d: mined header rejected: %v", i, err)
+		}
+		header.NUCDifficulty = new(big.Int).Div(header.Difficulty, big.NewInt(tt.divisor*2))
+		if err := ethash.VerifyNUCDifficulty(chain, header); err == nil {
+			t.Errorf("test %d: over discounted header accepted", i)
+		}
+	}
+}
+
+// Tests that the NUC difficulty rules are deferred by the header verifier if
+// the parent block isn't available yet, but enforced once it is.
+func TestVerifyNUCDifficultyUnknownAncestor(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID: big.NewInt(100),
+		Ethash:  new(params.EthashConfig),
+		NUC:     &params.NUCConfig{TxCountDiscountBlock: common.Big0, BalanceDiscountBlock: common.Big0},
+	}
+	chain, rich := newNUCTestChain(t, config, 3, 4)
+	header := nucTestHeader(chain, rich, func(diff *big.Int) *big.Int { return new(big.Int).Set(diff) })
+
+	ethash := NewFaker()
+	delete(chain.blocks, header.ParentHash)
+	if err := ethash.VerifyNUCDifficulty(chain, header); err != consensus.ErrUnknownAncestor {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrUnknownAncestor)
+	}
+	if err := ethash.verifyHeader(chain, header, chain.head.Header(), false, false); err != nil {
+		t.Fatalf("header verification should defer the NUC rules: %v", err)
+	}
+	chain.blocks[header.ParentHash] = chain.head
+	if err := ethash.VerifyNUCDifficulty(chain, header); err != consensus.ErrInvalidNUCBalanceDifficulty {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrInvalidNUCBalanceDifficulty)
+	}
+}
+
+// Tests that the NUC difficulty breakdown reports the discounts making up for
+// the header's NUC difficulty, or projects it if there is none.
+func TestExplainNUCDifficulty(t *testing.T) {
+	var (
+		half    = func(diff *big.Int) *big.Int { return new(big.Int).Div(diff, big.NewInt(2)) }
+		quarter = func(diff *big.Int) *big.Int { return new(big.Int).Div(diff, big.NewInt(4)) }
+		unset   = func(diff *big.Int) *big.Int { return nil }
+		poor    = common.HexToAddress("0x00000000000000000000000000000000000000ff")
+	)
+	newConfig := func(txCount, balance *big.Int) *params.ChainConfig {
+		return &params.ChainConfig{
+			ChainID: big.NewInt(100),
+			Ethash:  new(params.EthashConfig),
+			NUC:     &params.NUCConfig{TxCountDiscountBlock: txCount, BalanceDiscountBlock: balance},
+		}
+	}
+	tests := []struct {
+		config   *params.ChainConfig
+		rich     bool
+		nucdiff  func(diff *big.Int) *big.Int
+		enforced [2]bool
+		applied  [2]bool
+		want     func(diff *big.Int) *big.Int
+	}{
+		// Enforced rules apply whenever the coinbase qualifies
+		{newConfig(common.Big0, common.Big0), true, quarter, [2]bool{true, true}, [2]bool{true, true}, quarter},
+		{newConfig(common.Big0, common.Big0), false, unset, [2]bool{true, true}, [2]bool{false, false}, unset},
+
+		// Rules not enforced yet may have been skipped by the miner
+		{newConfig(nil, nil), true, half, [2]bool{false, false}, [2]bool{false, true}, half},
+		{newConfig(nil, common.Big0), true, half, [2]bool{false, true}, [2]bool{false, true}, half},
+
+		// Projections apply every qualifying discount
+		{newConfig(nil, nil), true, unset, [2]bool{false, false}, [2]bool{true, true}, quarter},
+	}
+	for i, tt := range tests {
+		chain, rich := newNUCTestChain(t, tt.config, 3, 4)
+		coinbase := poor
+		if tt.rich {
+			coinbase = rich
+		}
+		header := nucTestHeader(chain, coinbase, tt.nucdiff)
+		parentState, err := chain.StateAt(chain.head.Root())
+		if err != nil {
+			t.Fatalf("test %d: failed to open parent state: %v", i, err)
+		}
+		breakdown := consensus.ExplainNUCDifficulty(chain, header, parentState)
+
+		want := tt.want(header.Difficulty)
+		if want == nil {
+			want = header.Difficulty
+		}
+		if breakdown.NUCDifficulty.Cmp(want) != 0 {
+			t.Errorf("test %d: NUC difficulty mismatch: have %v, want %v", i, breakdown.NUCDifficulty, want)
+		}
+		for j, factor := range breakdown.Factors {
+			if factor.Qualified != tt.rich {
+				t.Errorf("test %d: %s rule qualification mismatch: have %v, want %v", i, factor.Rule, factor.Qualified, tt.rich)
+			}
+			if factor.Enforced != tt.enforced[j] || factor.Applied != tt.applied[j] {
+				t.Errorf("test %d: %s rule mismatch: have enforced %v applied %v, want %v %v", i, factor.Rule, factor.Enforced, factor.Applied, tt.enforced[j], tt.applied[j])
+			}
+		}
+		if tx := breakdown.Factors[0]; tt.rich && tx.Value.Uint64() < params.DefaultNUCDifficultyRules.TxCount {
+			t.Errorf("test %d: recent tx count mismatch: have %v, want at least %d", i, tx.Value, params.DefaultNUCDifficultyRules.TxCount)
+		}
+	}
+}
@@ -1,17 +1,16 @@
 package ethash
 
 import (
+	"bytes"
 	"encoding/hex"
-	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/bitutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
-	"github.com/ethereum/go-ethereum/ethdb/reward"
+	"github.com/ethereum/go-ethereum/log"
 	"math/big"
 	"sort"
 )
@@ -185,7 +184,6 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 			//invalid
 			user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 		}
-		// fmt.Println("============ poc reward============", everyPocUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pow users
 	allPowers := GetAllPowers(header, state, c)
@@ -202,7 +200,6 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 			//invalid
 			user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 		}
-		// fmt.Println("============ pow reward============", everyPowUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pool users***********************************
 	allPoolers := GetAllPoolers(header, state, c)
@@ -215,11 +212,10 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	for _, user := range allPoolers {
 		user.Reward = user.Reward.Add(user.Reward, everyPoolUserReward)
 		user.Reward = user.Reward.Mul(user.Reward, big.NewInt(user.Weight))
-		// fmt.Println("============ pool reward============", everyPoolUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pow mortage reward ***********************************
 	allTopPowRewardUsers := GetTopPowReward(header, state, c)
-	allTopPowAmount := GetContractValue(common.FromHex("0x682c73e3"), header, c.(*core.BlockChain), state)
+	allTopPowAmount := GetContractValue(common.FromHex("0x682c73e3"), header, c, state)
 	topPowReward := big.NewInt(0).Add(big.NewInt(0), PowReward)
 	if allTopPowAmount.Cmp(big.NewInt(0)) > 0 {
 		topPowReward = topPowReward.Div(topPowReward, allTopPowAmount)
@@ -227,7 +223,7 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	for _, user := range allTopPowRewardUsers {
 		input := "0x44aa7c3b000000000000000000000000"
 		input += user.Address.String()[2:]
-		currentMortageCount := GetContractValue(common.FromHex(input), header, c.(*core.BlockChain), state)
+		currentMortageCount := GetContractValue(common.FromHex(input), header, c, state)
 		user.Reward = user.Reward.Mul(topPowReward, currentMortageCount)
 		user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 	}
@@ -241,7 +237,7 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 
 	//***********************pool mortage reward***********************************
 	allTopPoolRewardUsers := GetTopPoolReward(header, state, c)
-	allTopPoolAmount := GetContractValue(common.FromHex("0xcfa14566"), header, c.(*core.BlockChain), state)
+	allTopPoolAmount := GetContractValue(common.FromHex("0xcfa14566"), header, c, state)
 	topPoolReward := big.NewInt(0).Add(big.NewInt(0), PoolReward)
 	if allTopPoolAmount.Cmp(big.NewInt(0)) > 0 {
 		topPoolReward = topPoolReward.Div(topPoolReward, allTopPoolAmount)
@@ -250,7 +246,7 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	for _, user := range allTopPoolRewardUsers {
 		input := "0x2a100e87000000000000000000000000"
 		input += user.Address.String()[2:]
-		currentMortageCount := GetContractValue(common.FromHex(input), header, c.(*core.BlockChain), state)
+		currentMortageCount := GetContractValue(common.FromHex(input), header, c, state)
 		user.Reward = user.Reward.Mul(topPoolReward, currentMortageCount)
 		user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 	}
@@ -275,42 +271,42 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	//***********************all top 5 post mortage reward***********************************
 	allTop5PostRewardUsers := GetPostUsers(header, state, c)
 	allTop5PostReward := big.NewInt(0).Add(big.NewInt(0), Top5PoSTReward)
-	allTop5PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000005"), header, c.(*core.BlockChain), state)
+	allTop5PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000005"), header, c, state)
 	if allTop5PostAmount.Cmp(big.NewInt(0)) > 0 {
 		allTop5PostReward = allTop5PostReward.Div(allTop5PostReward, allTop5PostAmount)
 	}
 	for _, user := range allTop5PostRewardUsers {
 		input := "0xd4bdc612000000000000000000000000"
 		input += user.Address.String()[2:]
-		currentMortageBalance := GetContractValue(common.FromHex(input), header, c.(*core.BlockChain), state)
+		currentMortageBalance := GetContractValue(common.FromHex(input), header, c, state)
 		user.Reward = user.Reward.Mul(allTop5PostReward, currentMortageBalance)
 	}
 
 	//***********************all top 20 post mortage reward***********************************
 	allTop20PostRewardUsers := GetPostUsers(header, state, c)
 	allTop20PostReward := big.NewInt(0).Add(big.NewInt(0), Top20PoSTReward)
-	allTop20PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000014"), header, c.(*core.BlockChain), state)
+	allTop20PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000014"), header, c, state)
 	if allTop5PostAmount.Cmp(big.NewInt(0)) > 0 {
 		allTop20PostReward = allTop20PostReward.Div(allTop20PostReward, allTop20PostAmount)
 	}
 	for _, user := range allTop20PostRewardUsers {
 		input := "0xd4bdc612000000000000000000000000"
 		input += user.Address.String()[2:]
-		currentMortageBalance := GetContractValue(common.FromHex(input), header, c.(*core.BlockChain), state)
+		currentMortageBalance := GetContractValue(common.FromHex(input), header, c, state)
 		user.Reward = user.Reward.Mul(Top20PoSTReward, currentMortageBalance)
 	}
 
 	//***********************all top 100 post mortage reward***********************************
 	allTop100PostRewardUsers := GetPostUsers(header, state, c)
 	allTop100PostReward := big.NewInt(0).Add(big.NewInt(0), Top100PoSTReward)
-	allTop100PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000064"), header, c.(*core.BlockChain), state)
+	allTop100PostAmount := GetContractValue(common.FromHex("0xcddbd64d0000000000000000000000000000000000000000000000000000000000000064"), header, c, state)
 	if allTop100PostAmount.Cmp(big.NewInt(0)) > 0 {
 		allTop100PostReward = allTop100PostReward.Div(allTop100PostReward, allTop100PostAmount)
 	}
 	for _, user := range allTop100PostRewardUsers {
 		input := "0xd4bdc612000000000000000000000000"
 		input += user.Address.String()[2:]
-		currentMortageBalance := GetContractValue(common.FromHex(input), header, c.(*core.BlockChain), state)
+		currentMortageBalance := GetContractValue(common.FromHex(input), header, c, state)
 		user.Reward = user.Reward.Mul(topPoolReward, currentMortageBalance)
 	}
 
@@ -324,7 +320,7 @@ func NUCReward2(header *types.Header, state *state.StateDB, c consensus.ChainRea
 func GetRewardByType(reward *big.Int, header *types.Header, state *state.StateDB, c consensus.ChainReader) *big.Int {
 	// ========================== get reward reduce ratio ===========================
 	data := common.FromHex("0x76b8dde1")
-	ratio := GetReduceRatio(data, header, c.(*core.BlockChain), state)
+	ratio := GetReduceRatio(data, header, c, state)
 	if ratio == 25 && header.Number.Cmp(big.NewInt(10000)) <= 0 {
 		ratio = 0
 	}
@@ -396,7 +392,7 @@ func GetUsersByType(allInput, validInput []byte, header *types.Header, state *st
 
 func GetMiningUsers(contractType []byte, header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
 	users := MiningUsers{}
-	addrs := CallContract(contractType, header, c.(*core.BlockChain), state)
+	addrs := CallContract(contractType, header, c, state)
 	for _, power := range addrs {
 		if power.String() == "0x0000000000000000000000000000000000000000" {
 			continue
@@ -412,7 +408,7 @@ func GetMiningUsers(contractType []byte, header *types.Header, state *state.Stat
 
 func GetMiningUsersTop(contractType []byte, header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
 	users := MiningUsers{}
-	addrs := CallContractArr(contractType, header, c.(*core.BlockChain), state)
+	addrs := CallContractArr(contractType, header, c, state)
 	for _, power := range addrs {
 		if power.String() == "0x0000000000000000000000000000000000000000" {
 			continue
@@ -426,27 +422,19 @@ func GetMiningUsersTop(contractType []byte, header *types.Header, state *state.S
 	return users
 }
 
-func GetContractValue(input []byte, header *types.Header, c *core.BlockChain, state *state.StateDB) *big.Int {
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-
-	context := core.NewEVMContext(msg, header, c, nil)
-	evm := vm.NewEVM(context, state, c.Config(), *c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err := core.ApplyMessage(evm, msg, gp)
-		if failed || err != nil {
-			fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-		} else {
-			// fmt.Println(hex.EncodeToString(res), "hex.EncodeToString(res)")
-			if res == nil || len(res) < 1 {
-				return big.NewInt(0)
-			}
-			r := bitutil.NewUint256FromString(hex.EncodeToString(res))
-			if r.BigInt().Uint64() <= 0 {
-				return big.NewInt(0)
-			}
-			return r.BigInt()
+func GetContractValue(input []byte, header *types.Header, c consensus.ChainReader, state vm.StateDB) *big.Int {
+	res, err := applyRuleCall(input, header, c, state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+	} else {
+		if res == nil || len(res) < 1 {
+			return big.NewInt(0)
+		}
+		r := bitutil.NewUint256FromString(hex.EncodeToString(res))
+		if r.BigInt().Uint64() <= 0 {
+			return big.NewInt(0)
 		}
+		return r.BigInt()
 	}
 	return big.NewInt(0)
 }
@@ -478,42 +466,52 @@ func (this *CoinbaseTxs) Add(addr common.Address) {
 	}
 	return
 }
-func (this *CoinbaseTxs) Encode() []byte {
-	b := make([]byte, 0)
-	for addr, v := range *this {
-		// if v.PoolReward.Cmp(big.NewInt(0)) > 0 {
-		// 	// fmt.Println("===================encode v.PoolReward, v.PowReward",
-		// 	// 	addr.String(), v.PoolReward, v.PowReward)
-		// }
-		// fmt.Println("===================encode v.PoolReward, v.PowReward",
-		// 	addr.String(), v.PoolReward, v.PowReward)
-		b = append(b, addr.Bytes()...)                    //20 bytes
-		b = append(b, FormatUint64Bytes(v.PocReward)...)  // 8bytes
-		b = append(b, FormatUint64Bytes(v.PowReward)...)  // 8bytes
-		b = append(b, FormatUint64Bytes(v.PoolReward)...) // 8bytes
+// Addresses returns the rewarded addresses in ascending byte order, which is
+// the canonical order used when encoding the rewards into a header.
+func (this *CoinbaseTxs) Addresses() []common.Address {
+	addrs := make([]common.Address, 0, len(*this))
+	for addr := range *this {
+		addrs = append(addrs, addr)
 	}
-	return b
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs
 }
-func (this *CoinbaseTxs) Save(number *big.Int) error {
-	rewardRecords := this.Encode()
-	db := reward.NewRecordDB()
-	// fmt.Println("rewardRecords", hex.EncodeToString(rewardRecords), number.Uint64())
-	return db.Put(number.Bytes(), rewardRecords)
+
+// Entries returns the rewards sorted by address, in the order they're encoded
+// into a header.
+func (this *CoinbaseTxs) Entries() []types.CoinbaseTx {
+	entries := make([]types.CoinbaseTx, 0, len(*this))
+	for _, addr := range this.Addresses() {
+		v := (*this)[addr]
+		entries = append(entries, types.CoinbaseTx{
+			Address:    addr,
+			PocReward:  v.PocReward,
+			PowReward:  v.PowReward,
+			PoolReward: v.PoolReward,
+			PostReward: v.PostReward,
+		})
+	}
+	return entries
 }
+
+// DecodeFromBytes parses hex encoded header.CoinbaseTxs of a block before the NUC
+// coinbase txs fork, see types.DecodeLegacyCoinbaseTxs. Malformed data decodes
+// into an empty reward set.
 func DecodeFromBytes(coinbaseTxs string) *CoinbaseTxs {
-	b := common.FromHex(coinbaseTxs)
 	coTxs := &CoinbaseTxs{}
-	//every user has 52 bytes
-	for i := 0; i < len(b); {
-		addr := common.BytesToAddress(b[i : i+20])
-		coTxs.Add(addr)
-		i += 20
-		(*coTxs)[addr].PocReward.SetBytes(b[i : i+8])
-		i += 8
-		(*coTxs)[addr].PowReward.SetBytes(b[i : i+8])
-		i += 8
-		(*coTxs)[addr].PoolReward.SetBytes(b[i : i+8])
-		i += 8
+	entries, err := types.DecodeLegacyCoinbaseTxs(common.FromHex(coinbaseTxs))
+	if err != nil {
+		return coTxs
+	}
+	for _, entry := range entries {
+		(*coTxs)[entry.Address] = &CoinbaseUserReward{
+			PoolReward: entry.PoolReward,
+			PocReward:  entry.PocReward,
+			PowReward:  entry.PowReward,
+			PostReward: entry.PostReward,
+		}
 	}
 	return coTxs
 }
@@ -1,12 +1,12 @@
 package ethash
 
 import (
-	"fmt"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
-	"github.com/ethereum/go-ethereum/core"
+	v0 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v0"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	math1 "math"
 	"math/big"
 	"time"
@@ -23,12 +23,10 @@ func NUCReward3(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	}
 	for _, user := range allPocers {
 		user.Reward = user.Reward.Add(user.Reward, everyPocUserReward)
-		// fmt.Println("==========everyPocUserReward", everyPocUserReward, user.Weight)
 		if !user.HasBind {
 			//invalid
 			user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 		}
-		// fmt.Println("============ poc reward============", everyPocUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pow users reward*****************************************************//
 	allPowers := GetAllPowers1(allPocers, header, state, c)
@@ -46,7 +44,6 @@ func NUCReward3(header *types.Header, state *state.StateDB, c consensus.ChainRea
 			//invalid
 			user.Reward = user.Reward.Div(user.Reward, big.NewInt(2))
 		}
-		// fmt.Println("============ pow reward============", everyPowUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pool users reward **************************************************//
 	allPoolers := GetAllPoolers1(header, state, c)
@@ -173,16 +170,13 @@ func MergeCoinbasetxs1(pocUsers, powUsers, poolUsers, powUsers1, poolUsers1,
 		}
 		(*coinbaseTxs)[user.Address].PowReward.Add((*coinbaseTxs)[user.Address].PowReward, user.Reward)
 	}
-	// for _, pool := range poolUsers {
-	// 	fmt.Println("===========pool", pool.Address.String(), pool.Reward)
-	// }
 	poolUsers.MergeReward(poolUsers1)
 	for _, user := range poolUsers {
 		if !coinbaseTxs.Has(user.Address) {
 			coinbaseTxs.Add(user.Address)
 		}
 		(*coinbaseTxs)[user.Address].PoolReward.Add((*coinbaseTxs)[user.Address].PoolReward, user.Reward)
-		fmt.Println("poolUsers length", user.Address.String(), len(poolUsers), (*coinbaseTxs)[user.Address].PoolReward)
+		log.Debug("Pool reward", "address", user.Address, "pools", len(poolUsers), "reward", (*coinbaseTxs)[user.Address].PoolReward)
 	}
 	allPostRewardUsers.MergeReward(allTop5PostRewardUsers)
 	allPostRewardUsers.MergeReward(allTop20PostRewardUsers)
@@ -198,7 +192,7 @@ func MergeCoinbasetxs1(pocUsers, powUsers, poolUsers, powUsers1, poolUsers1,
 
 //get all powers
 func GetAllPowers1(pocUsers MiningUsers, header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := NewNUCCaller(header, c.(*core.BlockChain), state)
+	caller := v0.NewNUCCaller(newRuleContractCaller(header, state, c))
 	allCount, err := caller.PowerCount()
 	l := MiningUsers{}
 	if err != nil {
@@ -233,7 +227,7 @@ func GetAllPowers1(pocUsers MiningUsers, header *types.Header, state *state.Stat
 
 //get all powers
 func GetAllPocers1(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := NewNUCCaller(header, c.(*core.BlockChain), state)
+	caller := v0.NewNUCCaller(newRuleContractCaller(header, state, c))
 	allCount, err := caller.PocerCount()
 	l := MiningUsers{}
 	if err != nil {
@@ -265,7 +259,7 @@ func GetAllPocers1(header *types.Header, state *state.StateDB, c consensus.Chain
 
 //get all powers
 func GetAllPoolers1(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := NewNUCCaller(header, c.(*core.BlockChain), state)
+	caller := v0.NewNUCCaller(newRuleContractCaller(header, state, c))
 	allCount, err := caller.PoolerCount()
 	l := MiningUsers{}
 	if err != nil {
@@ -296,7 +290,7 @@ func GetAllPoolers1(header *types.Header, state *state.StateDB, c consensus.Chai
 
 //get all powers
 func GetAllPosters1(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := NewNUCCaller(header, c.(*core.BlockChain), state)
+	caller := v0.NewNUCCaller(newRuleContractCaller(header, state, c))
 	allCount, err := caller.PosterCount()
 	l := MiningUsers{}
 	if err != nil {
@@ -319,7 +313,7 @@ func GetAllPosters1(header *types.Header, state *state.StateDB, c consensus.Chai
 
 func GetRewardByType1(reward *big.Int, header *types.Header, state *state.StateDB, c consensus.ChainReader) *big.Int {
 	// ========================== get reward reduce ratio ===========================
-	caller := NewNUCCaller(header, c.(*core.BlockChain), state)
+	caller := v0.NewNUCCaller(newRuleContractCaller(header, state, c))
 	ratioBigData, err := caller.GetRewardRatio()
 	if err != nil {
 		return big.NewInt(0)
@@ -1,23 +1,40 @@
 package ethash
 
 import (
-	"fmt"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
-	v1 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v1"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
-	math1 "math"
+	"github.com/ethereum/go-ethereum/log"
 	"math/big"
 )
 
+// poolCutPercent is the share of a bound participant's reward going to its pool.
+const poolCutPercent = 10
+
+// BoundPoolCut returns the cut a pool received from a bound participant, given
+// the reward NUCReward4 left to the participant. The cut is rounded down from
+// the participant's full share, which the reward doesn't entirely determine, so
+// the result may exceed the actual cut by one wei.
+func BoundPoolCut(reward *big.Int) *big.Int {
+	cut := new(big.Int).Mul(reward, big.NewInt(poolCutPercent))
+	return cut.Div(cut, big.NewInt(100-poolCutPercent))
+}
+
+// NUCReward4 distributes the block reward between the PoC, PoW and pool
+// participants registered in the rule contract.
 func NUCReward4(header *types.Header, state *state.StateDB, c consensus.ChainReader) *CoinbaseTxs {
+	snap := GetRuleSnapshot(header, state, c)
+	// Each list and the reward ratio, twice, used to be looked up by separate calls
+	if !c.Config().IsNUCSystemCall(header.Number) {
+		chargeRuleCalls(header, state, snap.poolCalls+snap.pocCalls+snap.powCalls+2*snap.ratioCalls)
+	}
+
 	//******** pool users//
-	allPoolers := GetAllPoolers3(header, state, c)
+	allPoolers := GetAllPoolers3(snap)
 	//***********************poc users reward logic*****************************************************//
-	allPocers := GetAllPocers3(header, state, c)
-	pocReward := GetRewardByType3(PocBlockReward, header, state, c)
+	allPocers := GetAllPocers3(snap, state)
+	pocReward := GetRewardByType3(PocBlockReward, header, snap)
 	everyPocUserReward := big.NewInt(0).Add(big.NewInt(0), pocReward)
 	allPocerWeight := allPocers.AllWeight()
 	if allPocerWeight > 0 {
@@ -26,12 +43,11 @@ func NUCReward4(header *types.Header, state *state.StateDB, c consensus.ChainRea
 	for _, user := range allPocers {
 		pocR := big.NewInt(0).Add(big.NewInt(0), everyPocUserReward)
 		pocR = pocR.Mul(pocR, big.NewInt(user.Weight))
-		// fmt.Println("==========everyPocUserReward", everyPocUserReward, user.Weight)
 		if !user.HasBind {
 			//未绑定获取 50%
 			pocR = pocR.Div(pocR, big.NewInt(2))
 		} else {
-			poolR := new(big.Int).Mul(pocR, big.NewInt(10))
+			poolR := new(big.Int).Mul(pocR, big.NewInt(poolCutPercent))
 			poolR = poolR.Div(poolR, big.NewInt(100))
 			pocR = pocR.Sub(pocR, poolR)
 			//绑定矿池节点
@@ -42,11 +58,10 @@ func NUCReward4(header *types.Header, state *state.StateDB, c consensus.ChainRea
 		}
 
 		user.Reward = user.Reward.Add(user.Reward, pocR)
-		// fmt.Println("============ poc reward============", everyPocUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//***********************pow users reward*****************************************************//
-	allPowers := GetAllPowers3(header, state, c)
-	powReward := GetRewardByType3(PowBlockReward, header, state, c)
+	allPowers := GetAllPowers3(snap)
+	powReward := GetRewardByType3(PowBlockReward, header, snap)
 	everyPowUserReward := big.NewInt(0).Add(big.NewInt(0), powReward)
 	//所有的 power 之和
 	allPowerWeight := allPowers.AllWeight()
@@ -60,7 +75,7 @@ func NUCReward4(header *types.Header, state *state.StateDB, c consensus.ChainRea
 			//未绑定获取 50%
 			powR = powR.Div(powR, big.NewInt(2))
 		} else {
-			poolR := new(big.Int).Mul(powR, big.NewInt(10))
+			poolR := new(big.Int).Mul(powR, big.NewInt(poolCutPercent))
 			poolR = poolR.Div(poolR, big.NewInt(100))
 			powR = powR.Sub(powR, poolR)
 			//绑定矿池节点
@@ -70,7 +85,6 @@ func NUCReward4(header *types.Header, state *state.StateDB, c consensus.ChainRea
 			}
 		}
 		user.Reward = user.Reward.Add(user.Reward, powR)
-		// fmt.Println("============ pow reward============", everyPowUserReward, user.Weight, user.Address.String(), user.Reward)
 	}
 	//
 	ctxs := MergeCoinbasetxs3(allPocers, allPowers, allPoolers)
@@ -96,101 +110,61 @@ func MergeCoinbasetxs3(pocUsers, powUsers, poolUsers MiningUsers) *CoinbaseTxs {
 			coinbaseTxs.Add(user.Address)
 		}
 		(*coinbaseTxs)[user.Address].PoolReward.Add((*coinbaseTxs)[user.Address].PoolReward, user.Reward)
-		fmt.Println("poolUsers length", user.Address.String(), len(poolUsers), (*coinbaseTxs)[user.Address].PoolReward)
+		log.Debug("Pool reward", "address", user.Address, "pools", len(poolUsers), "reward", (*coinbaseTxs)[user.Address].PoolReward)
 	}
 	return coinbaseTxs
 }
 
-//get all pocers
-func GetAllPocers3(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := v1.NewNUCCaller(header, c.(*core.BlockChain), state)
-	allCount, err := caller.PocerCount()
+// GetAllPocers3 returns the PoC participants of the snapshot that haven't yet
+// earned their maximum reward.
+func GetAllPocers3(snap *RuleSnapshot, state *state.StateDB) MiningUsers {
 	l := MiningUsers{}
-	if err != nil {
-		fmt.Println("PocCount", err)
-		return l
-	}
-	pageSize := 1000
-	pageCount := math1.Ceil(float64(allCount.Uint64()) / float64(pageSize))
-	for page := 0; page < int(pageCount); page++ {
-		users, err := caller.AllPocers(big.NewInt(int64(page*pageSize)), big.NewInt(int64(pageSize)))
-		if err != nil {
-			return l
-		}
-		for _, u := range users {
-			maxReward := u.CanGetMaxReward()
-			hasGetReward := state.GetAllPocBalance(u.UserAddr)
+	for _, u := range snap.Pocers {
+		maxReward := u.CanGetMaxReward()
+		hasGetReward := state.GetAllPocBalance(u.UserAddr)
 
-			if maxReward.Cmp(hasGetReward) <= 0 { //如果收益达到120% 则自动收益停止
-				continue
-			}
-			l.Add(u.UserAddr)
-			if u.BindPoolAddr.String() != ZERO_ADDR {
-				l.SetBindAddr(u.UserAddr, u.BindPoolAddr)
-			}
-			l.SetMortageBalance(u.UserAddr, u.MortageBalance)
-			l.AddWeightCount(u.UserAddr, int64(len(u.Records))-1)
+		if maxReward.Cmp(hasGetReward) <= 0 { //如果收益达到120% 则自动收益停止
+			continue
+		}
+		l.Add(u.UserAddr)
+		if u.BindPoolAddr.String() != ZERO_ADDR {
+			l.SetBindAddr(u.UserAddr, u.BindPoolAddr)
 		}
+		l.SetMortageBalance(u.UserAddr, new(big.Int).Set(u.MortageBalance))
+		l.AddWeightCount(u.UserAddr, int64(len(u.Records))-1)
 	}
 	return l
 }
 
-//get all powers
-func GetAllPowers3(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := v1.NewNUCCaller(header, c.(*core.BlockChain), state)
-	allCount, err := caller.PowerCount()
+// GetAllPowers3 returns the PoW participants of the snapshot.
+func GetAllPowers3(snap *RuleSnapshot) MiningUsers {
 	l := MiningUsers{}
-	if err != nil {
-		return l
-	}
-	pageSize := 1000
-	pageCount := math1.Ceil(float64(allCount.Uint64()) / float64(pageSize))
-	for page := 0; page < int(pageCount); page++ {
-		users, err := caller.AllPowers(big.NewInt(int64(page*pageSize)), big.NewInt(int64(pageSize)))
-		if err != nil {
-			return l
-		}
-		for _, u := range users {
-			l.Add(u.UserAddr)
-			l.AddWeightCount(u.UserAddr, int64(len(u.Records))-1)
-			if u.BindPoolAddr.String() != ZERO_ADDR {
-				l.SetBindAddr(u.UserAddr, u.BindPoolAddr)
-			}
+	for _, u := range snap.Powers {
+		l.Add(u.UserAddr)
+		l.AddWeightCount(u.UserAddr, int64(len(u.Records))-1)
+		if u.BindPoolAddr.String() != ZERO_ADDR {
+			l.SetBindAddr(u.UserAddr, u.BindPoolAddr)
 		}
 	}
 	return l
 }
 
-//get all poolers
-func GetAllPoolers3(header *types.Header, state *state.StateDB, c consensus.ChainReader) MiningUsers {
-	caller := v1.NewNUCCaller(header, c.(*core.BlockChain), state)
-	allCount, err := caller.PoolerCount()
+// GetAllPoolers3 returns the pool participants of the snapshot.
+func GetAllPoolers3(snap *RuleSnapshot) MiningUsers {
 	l := MiningUsers{}
-	if err != nil {
-		return l
-	}
-	pageSize := 1000
-	pageCount := math1.Ceil(float64(allCount.Uint64()) / float64(pageSize))
-	for page := 0; page < int(pageCount); page++ {
-		users, err := caller.AllPoolers(big.NewInt(int64(page*pageSize)), big.NewInt(int64(pageSize)))
-		if err != nil {
-			return l
-		}
-		for _, u := range users {
-			l.Add(u.UserAddr)
-		}
+	for _, u := range snap.Poolers {
+		l.Add(u.UserAddr)
 	}
 	return l
 }
 
-func GetRewardByType3(reward *big.Int, header *types.Header, state *state.StateDB, c consensus.ChainReader) *big.Int {
+// GetRewardByType3 reduces a block reward by the halving ratio of the snapshot.
+func GetRewardByType3(reward *big.Int, header *types.Header, snap *RuleSnapshot) *big.Int {
 	// ========================== get reward reduce ratio ===========================
-	caller := v1.NewNUCCaller(header, c.(*core.BlockChain), state)
-	ratioBigData, err := caller.GetRewardRatio()
-	if err != nil {
+	if snap.RewardRatio == nil {
 		return big.NewInt(0)
 	}
-	ratio := ratioBigData.Uint64()
+	ratio := snap.RewardRatio.Uint64()
 	if ratio == 25 && header.Number.Cmp(big.NewInt(10000)) <= 0 {
 		ratio = 0
 	}
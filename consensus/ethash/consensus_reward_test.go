@@ -0,0 +1,149 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// randomCoinbaseTxs generates a reward set with random amounts.
+func randomCoinbaseTxs(r *rand.Rand, n int) *CoinbaseTxs {
+	ctxs := &CoinbaseTxs{}
+	for i := 0; i < n; i++ {
+		var addr common.Address
+		r.Read(addr[:])
+		ctxs.Add(addr)
+
+		user := (*ctxs)[addr]
+		user.PocReward.Rand(r, new(big.Int).Lsh(big1, 100))
+		user.PowReward.Rand(r, new(big.Int).Lsh(big1, 80))
+		user.PoolReward.Rand(r, new(big.Int).Lsh(big1, 72))
+		user.PostReward.Rand(r, new(big.Int).Lsh(big1, 64))
+	}
+	return ctxs
+}
+
+// Tests that the encoding doesn't depend on map iteration order.
+func TestCoinbaseTxsDeterministic(t *testing.T) {
+	ctxs := randomCoinbaseTxs(rand.New(rand.NewSource(2)), 64)
+	want, err := types.EncodeCoinbaseTxs(ctxs.Entries())
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	for i := 0; i < 32; i++ {
+		clone := &CoinbaseTxs{}
+		for addr, user := range *ctxs {
+			(*clone)[addr] = user
+		}
+		have, err := types.EncodeCoinbaseTxs(clone.Entries())
+		if err != nil {
+			t.Fatalf("encode failed: %v", err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Fatalf("run %d: encoding differs", i)
+		}
+	}
+}
+
+// Tests that the rewards of the blocks before the NUC coinbase txs fork are
+// encoded the legacy way: 44 bytes per address, rewards truncated to 8 bytes and
+// post rewards dropped.
+func TestLegacyCoinbaseTxs(t *testing.T) {
+	wide := new(big.Int).Lsh(big.NewInt(0x0102), 64) // 10 bytes, 0x0102 followed by 8 zero bytes
+
+	ctxs := &CoinbaseTxs{}
+	ctxs.Add(common.Address{2})
+	ctxs.Add(common.Address{1})
+	(*ctxs)[common.Address{1}].PocReward.SetUint64(1)
+	(*ctxs)[common.Address{1}].PowReward.SetUint64(2)
+	(*ctxs)[common.Address{1}].PoolReward.Set(wide)
+	(*ctxs)[common.Address{1}].PostReward.SetUint64(4)
+
+	enc := types.EncodeLegacyCoinbaseTxs(ctxs.Entries())
+	if len(enc) != 2*44 {
+		t.Fatalf("encoding size mismatch: have %d, want %d", len(enc), 2*44)
+	}
+	dec := DecodeFromBytes(common.ToHex(enc))
+	if len(*dec) != 2 {
+		t.Fatalf("entry count mismatch: have %d, want %d", len(*dec), 2)
+	}
+	have := (*dec)[common.Address{1}]
+	if have.PocReward.Uint64() != 1 || have.PowReward.Uint64() != 2 || have.PostReward.Sign() != 0 {
+		t.Errorf("reward mismatch: have %+v", have)
+	}
+	if want := new(big.Int).Lsh(big.NewInt(0x0102), 48); have.PoolReward.Cmp(want) != 0 {
+		t.Errorf("truncated reward mismatch: have %x, want %x", have.PoolReward, want)
+	}
+	if _, err := types.DecodeLegacyCoinbaseTxs(enc[:len(enc)-1]); err != types.ErrLegacyCoinbaseTxsSize {
+		t.Errorf("truncated encoding error mismatch: have %v, want %v", err, types.ErrLegacyCoinbaseTxsSize)
+	}
+}
+
+// Tests that the rewards are recorded in the header with the encoding the chain
+// config schedules, and that the rewards of legacy blocks aren't accounted.
+func TestCoinbaseTxsFork(t *testing.T) {
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{CoinbaseTxsBlock: big.NewInt(10)}
+	chain := &nucTestChain{config: &config}
+
+	algorithm := func(header *types.Header, state *state.StateDB, c consensus.ChainReader) *CoinbaseTxs {
+		ctxs := &CoinbaseTxs{}
+		ctxs.Add(common.Address{1})
+		(*ctxs)[common.Address{1}].PowReward.SetUint64(1)
+		return ctxs
+	}
+	for _, number := range []int64{9, 10} {
+		header := &types.Header{Number: big.NewInt(number)}
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		if err := accumulateNUCRewards(chain, statedb, header, nil, nil, algorithm); err != nil {
+			t.Fatalf("block %d: failed to credit rewards: %v", number, err)
+		}
+		rewards, err := types.DecodeBlockCoinbaseTxs(&config, header.Number, header.CoinbaseTxs)
+		if err != nil {
+			t.Fatalf("block %d: failed to decode rewards: %v", number, err)
+		}
+		if len(rewards) != 1 || rewards[0].PowReward.Uint64() != 1 {
+			t.Errorf("block %d: rewards mismatch: have %+v", number, rewards)
+		}
+		legacy := len(header.CoinbaseTxs) == 44
+		if legacy != (number < 10) {
+			t.Errorf("block %d: encoding mismatch: have %x", number, header.CoinbaseTxs)
+		}
+		block := types.NewBlockWithHeader(header)
+		if _, err := new(Ethash).BlockIssuance(chain, block); (err == errLegacyRewards) != legacy {
+			t.Errorf("block %d: issuance error mismatch: have %v, legacy %v", number, err, legacy)
+		}
+	}
+}
+
+// Tests that every reward version accepted by the chain config has an
+// implementation.
+func TestRewardAlgorithmsComplete(t *testing.T) {
+	for version := uint64(params.NUCRewardV2); version <= params.NUCRewardLatest; version++ {
+		if _, ok := rewardAlgorithms[version]; !ok {
+			t.Errorf("missing reward algorithm for version %d", version)
+		}
+	}
+}
+
+// Tests that the pool cut derived from a bound participant's reward matches the
+// cut taken by NUCReward4, up to the wei lost to its rounding.
+func TestBoundPoolCut(t *testing.T) {
+	for share := int64(0); share < 1000; share++ {
+		cut := share * poolCutPercent / 100
+		have := BoundPoolCut(big.NewInt(share - cut))
+		if have.Int64() != cut && !(share%10 == 9 && have.Int64() == cut+1) {
+			t.Errorf("share %d: pool cut mismatch: have %v, want %d", share, have, cut)
+		}
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethdb/reward"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -144,30 +143,6 @@ func TestDecodeCoinbaseTXS2(t *testing.T) {
 	}
 }
 
-func TestDecodeCoinbaseTXS3(t *testing.T) {
-	data := reward.GetRewardsByNumber(big.NewInt(110))
-	ctxs := DecodeFromBytes(common.ToHex(data))
-	fmt.Println("all Addr Count", len(*ctxs))
-	i := 0
-	for addr, v := range *ctxs {
-		if i == len(*ctxs)-1 {
-			fmt.Println(addr.String())
-			fmt.Println("PocReward", v.PocReward)
-			fmt.Println("PowReward", v.PowReward)
-			fmt.Println("PoolReward", v.PoolReward)
-			fmt.Println("PostReward", v.PostReward)
-		}
-		if addr.String() == "0xe53617F9e0f76E8B63B9741F5f670C6d81c86e96" {
-			fmt.Println(addr.String())
-			fmt.Println("PocReward", v.PocReward)
-			fmt.Println("PowReward", v.PowReward)
-			fmt.Println("PoolReward", v.PoolReward)
-			fmt.Println("PostReward", v.PostReward)
-		}
-		i++
-	}
-}
-
 func TestBigBytes(t *testing.T) {
 	powReward := new(big.Int).Set(PowBlockReward)
 	ratio := int64(25)
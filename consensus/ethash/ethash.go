@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/golang-lru/simplelru"
 )
@@ -50,7 +51,7 @@ var (
 	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
 
 	// sharedEthash is a full instance that can be shared between multiple users.
-	sharedEthash = New(Config{"", 3, 0, "", 1, 0, ModeNormal}, nil, false)
+	sharedEthash = New(Config{"", 3, 0, "", 1, 0, ModeNormal, false, "", 0}, nil, false)
 
 	// algorithmRevision is the data structure version used for file naming.
 	algorithmRevision = 23
@@ -403,11 +404,21 @@ type Config struct {
 	DatasetsInMem  int
 	DatasetsOnDisk int
 	PowMode        Mode
+
+	// NotifyNUC pushes NUC work packages to the remote miners to notify instead
+	// of the classic ones.
+	NotifyNUC bool `toml:",omitempty"`
+
+	// StratumAddr is the TCP listening address of the stratum server, which is
+	// disabled if empty. StratumDifficulty is the share difficulty it assigns.
+	StratumAddr       string  `toml:",omitempty"`
+	StratumDifficulty float64 `toml:",omitempty"`
 }
 
 // sealTask wraps a seal block with relative result channel for remote sealer thread.
 type sealTask struct {
 	block   *types.Block
+	config  *params.ChainConfig // Chain config the block's rewards are encoded under, nil if unknown
 	results chan<- *types.Block
 }
 
@@ -433,6 +444,7 @@ type hashrate struct {
 type sealWork struct {
 	errc chan error
 	res  chan [4]string
+	nuc  chan *NUCWork // Receives the NUC work package instead of res, if set
 }
 
 // Ethash is a consensus engine based on proof-of-work implementing the ethash
@@ -455,6 +467,7 @@ type Ethash struct {
 	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
 	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
 	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	stratum      *stratumServer   // Stratum endpoint of the remote sealer, nil if disabled
 
 	// The fields below are hooks for testing
 	shared    *Ethash       // Shared PoW verifier to avoid cache regeneration
@@ -493,6 +506,14 @@ func New(config Config, notify []string, noverify bool) *Ethash {
 		submitRateCh: make(chan *hashrate),
 		exitCh:       make(chan chan error),
 	}
+	if config.StratumAddr != "" {
+		stratum, err := newStratumServer(ethash, config.StratumAddr, config.StratumDifficulty)
+		if err != nil {
+			log.Error("Failed to start stratum server", "addr", config.StratumAddr, "err", err)
+		} else {
+			ethash.stratum = stratum
+		}
+	}
 	go ethash.remote(notify, noverify)
 	return ethash
 }
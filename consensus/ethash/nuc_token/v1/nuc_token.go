@@ -1,33 +1,35 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
 package v1
 
 import (
-	"encoding/hex"
-	"errors"
-	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/core/vm"
-	"math"
-	"math/big"
-	"strings"
+	"github.com/ethereum/go-ethereum/event"
 )
 
-type NUCCaller struct {
-	Abi    abi.ABI
-	header *types.Header
-	c      *core.BlockChain
-	state  *state.StateDB
-}
-
-var NucRuleContractAddr = common.HexToAddress("0000000000000000000000000000000000000011")
-var CallContractGuessGas = uint64(math.MaxUint64 / 2)
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = abi.U256
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
 
-// Struct1 is an auto generated low-level Go binding around an user-defined struct.
-type Struct1 struct {
-	Records        []Struct0
+// Pocer is an auto generated low-level Go binding around an user-defined struct.
+type Pocer struct {
+	Records        []Record
 	UserAddr       common.Address
 	GetReward      *big.Int
 	Index          *big.Int
@@ -35,176 +37,1176 @@ type Struct1 struct {
 	BindPoolAddr   common.Address
 }
 
-func (this *Struct1) CanGetMaxReward() *big.Int {
-	reward := this.MortageBalance.Mul(this.MortageBalance, big.NewInt(120))
-	reward = reward.Div(reward, big.NewInt(100))
-	return reward
-}
-
-// Struct0 is an auto generated low-level Go binding around an user-defined struct.
-type Struct0 struct {
+// Pooler is an auto generated low-level Go binding around an user-defined struct.
+type Pooler struct {
 	CreateTime *big.Int
+	Index      *big.Int
+	BuyBalance *big.Int
+	PowAddrs   []common.Address
+	PocAddrs   []common.Address
+	UserAddr   common.Address
 }
 
-// Struct2 is an auto generated low-level Go binding around an user-defined struct.
-type Struct2 struct {
+// Power is an auto generated low-level Go binding around an user-defined struct.
+type Power struct {
 	CreateTime   *big.Int
 	Index        *big.Int
 	BuyBalance   *big.Int
 	BindPoolAddr common.Address
 	PocAddrs     []common.Address
 	UserAddr     common.Address
-	Records      []Struct0
+	Records      []Record
 }
 
-// Struct3 is an auto generated low-level Go binding around an user-defined struct.
-type Struct3 struct {
+// Record is an auto generated low-level Go binding around an user-defined struct.
+type Record struct {
 	CreateTime *big.Int
-	Index      *big.Int
-	BuyBalance *big.Int
-	PowAddrs   []common.Address
-	PocAddrs   []common.Address
-	UserAddr   common.Address
 }
 
-// TokenABI is the input ABI used to generate the binding from.
-const TokenABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"GetPoCTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"times\",\"type\":\"uint256\"}],\"name\":\"InitPow\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPocers\",\"outputs\":[{\"components\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"getReward\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"mortageBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPowers\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\"}],\"name\":\"\",\"type\":\"tuple[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"poolUserAddr\",\"type\":\"address\"}],\"name\":\"PocBindPoolUser\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"powAddresses\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"poolUserAddr\",\"type\":\"address\"}],\"name\":\"PowBindPoolUser\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"frozenBalancers\",\"outputs\":[{\"name\":\"Start\",\"type\":\"uint256\"},{\"name\":\"End\",\"type\":\"uint256\"},{\"name\":\"Addr\",\"type\":\"address\"},{\"name\":\"Balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPooler\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"powAddrs\",\"type\":\"address[]\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"burnAmount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPocer\",\"outputs\":[{\"components\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"getReward\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"mortageBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pocTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPool\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PocExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner1\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetRewardRatio\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"poolTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPow\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"powTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPoolers\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"powAddrs\",\"type\":\"address[]\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetCurrentTime\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"}],\"name\":\"GetLeftFrozenBalance\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetPowTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PowCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPoc\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"reduceDuration\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PoolCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPower\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\"}],\"name\":\"\",\"type\":\"tuple\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"start\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PoolExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pocExpired\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"InitContract\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PowExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetPoolTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PocCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"times\",\"type\":\"uint256\"}],\"name\":\"InitPoc\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"ChangeOwner\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"},{\"name\":\"_start\",\"type\":\"uint256\"},{\"name\":\"_end\",\"type\":\"uint256\"},{\"name\":\"balance\",\"type\":\"uint256\"}],\"name\":\"SetFrozenBalance\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"ChangeOwner1\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+// NUCABI is the input ABI used to generate the binding from.
+const NUCABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"GetPoCTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"times\",\"type\":\"uint256\"}],\"name\":\"InitPow\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPocers\",\"outputs\":[{\"components\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\",\"internalType\":\"structRecord[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"getReward\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"mortageBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple[]\",\"internalType\":\"structPocer[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPowers\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\",\"internalType\":\"structRecord[]\"}],\"name\":\"\",\"type\":\"tuple[]\",\"internalType\":\"structPower[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"poolUserAddr\",\"type\":\"address\"}],\"name\":\"PocBindPoolUser\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"powAddresses\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"poolUserAddr\",\"type\":\"address\"}],\"name\":\"PowBindPoolUser\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"frozenBalancers\",\"outputs\":[{\"name\":\"Start\",\"type\":\"uint256\"},{\"name\":\"End\",\"type\":\"uint256\"},{\"name\":\"Addr\",\"type\":\"address\"},{\"name\":\"Balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPooler\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"powAddrs\",\"type\":\"address[]\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structPooler\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"burnAmount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPocer\",\"outputs\":[{\"components\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\",\"internalType\":\"structRecord[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"getReward\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"mortageBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structPocer\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pocTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPool\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PocExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner1\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetRewardRatio\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"poolTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPow\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"powTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"offset\",\"type\":\"uint256\"},{\"name\":\"pageSize\",\"type\":\"uint256\"}],\"name\":\"AllPoolers\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"powAddrs\",\"type\":\"address[]\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"\",\"type\":\"tuple[]\",\"internalType\":\"structPooler[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetCurrentTime\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"}],\"name\":\"GetLeftFrozenBalance\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetPowTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PowCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"BuyPoc\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"reduceDuration\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PoolCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"GetPower\",\"outputs\":[{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"},{\"name\":\"Index\",\"type\":\"uint256\"},{\"name\":\"buyBalance\",\"type\":\"uint256\"},{\"name\":\"bindPoolAddr\",\"type\":\"address\"},{\"name\":\"pocAddrs\",\"type\":\"address[]\"},{\"name\":\"userAddr\",\"type\":\"address\"},{\"components\":[{\"name\":\"createTime\",\"type\":\"uint256\"}],\"name\":\"records\",\"type\":\"tuple[]\",\"internalType\":\"structRecord[]\"}],\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structPower\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"start\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PoolExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pocExpired\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"InitContract\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_user\",\"type\":\"address\"}],\"name\":\"PowExisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"GetPoolTicket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"PocCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"},{\"name\":\"times\",\"type\":\"uint256\"}],\"name\":\"InitPoc\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"ChangeOwner\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"},{\"name\":\"_start\",\"type\":\"uint256\"},{\"name\":\"_end\",\"type\":\"uint256\"},{\"name\":\"balance\",\"type\":\"uint256\"}],\"name\":\"SetFrozenBalance\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"userAddr\",\"type\":\"address\"}],\"name\":\"ChangeOwner1\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
 
-func NewNUCCaller(header *types.Header, c *core.BlockChain, state *state.StateDB) *NUCCaller {
-	parsed, err := abi.JSON(strings.NewReader(TokenABI))
-	if err != nil {
-		return nil
-	}
-	return &NUCCaller{
-		Abi:    parsed,
-		header: header,
-		c:      c,
-		state:  state,
-	}
+// NUC is an auto generated Go binding around an Ethereum contract.
+type NUC struct {
+	NUCCaller     // Read-only binding to the contract
+	NUCTransactor // Write-only binding to the contract
+	NUCFilterer   // Log filterer for contract events
 }
 
-func (this *NUCCaller) AllPowers(offset, pageSize *big.Int) ([]Struct2, error) {
-	method := "AllPowers"
-	input, err := this.Abi.Pack(method, offset, pageSize)
-	if err != nil {
-		fmt.Println("+++++++==", err)
-		return nil, err
-	}
-	out := new([]Struct2)
-	// fmt.Println("===========input", hex.EncodeToString(input))
-	output, err := this.CallContract(input)
-	if err != nil {
-		return nil, err
-	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, nil
+// NUCCaller is an auto generated read-only Go binding around an Ethereum contract.
+type NUCCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
 }
 
-func (this *NUCCaller) PowerCount() (*big.Int, error) {
-	method := "PowCount"
-	input, err := this.Abi.Pack(method)
-	if err != nil {
-		return nil, err
-	}
-	out := new(*big.Int)
-	output, err := this.CallContract(input)
-	if err != nil {
-		return nil, err
-	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, err
+// NUCTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type NUCTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
 }
 
-func (this *NUCCaller) AllPocers(offset, pageSize *big.Int) ([]Struct1, error) {
-	method := "AllPocers"
-	input, err := this.Abi.Pack(method, offset, pageSize)
-	if err != nil {
-		return nil, err
-	}
-	out := new([]Struct1)
-	output, err := this.CallContract(input)
-	if err != nil {
-		return nil, err
-	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, nil
+// NUCFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type NUCFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
 }
 
-func (this *NUCCaller) PocerCount() (*big.Int, error) {
-	method := "PocCount"
-	input, err := this.Abi.Pack(method)
-	if err != nil {
-		return nil, err
-	}
-	out := new(*big.Int)
-	output, err := this.CallContract(input)
-	if err != nil {
-		return nil, err
-	}
-	if len(output) <= 0 {
-		return big.NewInt(0), nil
-	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, err
+// NUCSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type NUCSession struct {
+	Contract     *NUC              // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
 }
 
-func (this *NUCCaller) AllPoolers(offset, pageSize *big.Int) ([]Struct3, error) {
-	method := "AllPoolers"
-	input, err := this.Abi.Pack(method, offset, pageSize)
-	if err != nil {
-		return nil, err
-	}
-	out := new([]Struct3)
-	output, err := this.CallContract(input)
+// NUCCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type NUCCallerSession struct {
+	Contract *NUCCaller    // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// NUCTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type NUCTransactorSession struct {
+	Contract     *NUCTransactor    // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// NUCRaw is an auto generated low-level Go binding around an Ethereum contract.
+type NUCRaw struct {
+	Contract *NUC // Generic contract binding to access the raw methods on
+}
+
+// NUCCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type NUCCallerRaw struct {
+	Contract *NUCCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// NUCTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type NUCTransactorRaw struct {
+	Contract *NUCTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewNUC creates a new instance of NUC, bound to a specific deployed contract.
+func NewNUC(address common.Address, backend bind.ContractBackend) (*NUC, error) {
+	contract, err := bindNUC(address, backend, backend, backend)
 	if err != nil {
 		return nil, err
 	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, nil
+	return &NUC{NUCCaller: NUCCaller{contract: contract}, NUCTransactor: NUCTransactor{contract: contract}, NUCFilterer: NUCFilterer{contract: contract}}, nil
 }
 
-func (this *NUCCaller) PoolerCount() (*big.Int, error) {
-	method := "PoolCount"
-	input, err := this.Abi.Pack(method)
+// NewNUCCaller creates a new read-only instance of NUC, bound to a specific deployed contract.
+func NewNUCCaller(address common.Address, caller bind.ContractCaller) (*NUCCaller, error) {
+	contract, err := bindNUC(address, caller, nil, nil)
 	if err != nil {
 		return nil, err
 	}
-	out := new(*big.Int)
-	output, err := this.CallContract(input)
+	return &NUCCaller{contract: contract}, nil
+}
+
+// NewNUCTransactor creates a new write-only instance of NUC, bound to a specific deployed contract.
+func NewNUCTransactor(address common.Address, transactor bind.ContractTransactor) (*NUCTransactor, error) {
+	contract, err := bindNUC(address, nil, transactor, nil)
 	if err != nil {
 		return nil, err
 	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, err
+	return &NUCTransactor{contract: contract}, nil
 }
 
-func (this *NUCCaller) GetRewardRatio() (*big.Int, error) {
-	method := "GetRewardRatio"
-	input, err := this.Abi.Pack(method)
+// NewNUCFilterer creates a new log filterer instance of NUC, bound to a specific deployed contract.
+func NewNUCFilterer(address common.Address, filterer bind.ContractFilterer) (*NUCFilterer, error) {
+	contract, err := bindNUC(address, nil, nil, filterer)
 	if err != nil {
 		return nil, err
 	}
-	out := new(*big.Int)
-	output, err := this.CallContract(input)
+	return &NUCFilterer{contract: contract}, nil
+}
+
+// bindNUC binds a generic wrapper to an already deployed contract.
+func bindNUC(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(NUCABI))
 	if err != nil {
 		return nil, err
 	}
-	err = this.Abi.Unpack(out, method, output)
-	return *out, err
-}
-
-func (this *NUCCaller) CallContract(input []byte) (res []byte, err error) {
-	failed := false
-	msg := types.NewMessage(NucRuleContractAddr, &NucRuleContractAddr, 0, big.NewInt(0), CallContractGuessGas, big.NewInt(0), input, false)
-	context := core.NewEVMContext(msg, this.header, this.c, nil)
-	evm := vm.NewEVM(context, this.state, this.c.Config(), *this.c.GetVMConfig())
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
-	if evm != nil {
-		res, _, failed, err = core.ApplyMessage(evm, msg, gp)
-		if !failed && err == nil {
-			return res, nil
-		}
-	}
-	fmt.Println(hex.EncodeToString(input), "==========coinbase call contract error", failed, err)
-	return nil, errors.New(hex.EncodeToString(input) + "==========coinbase call contract error")
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NUC *NUCRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _NUC.Contract.NUCCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NUC *NUCRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.Contract.NUCTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NUC *NUCRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NUC.Contract.NUCTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NUC *NUCCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _NUC.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NUC *NUCTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NUC *NUCTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NUC.Contract.contract.Transact(opts, method, params...)
+}
+
+// AllPocers is a free data retrieval call binding the contract method 0x12c81dd5.
+//
+// Solidity: function AllPocers(uint256 offset, uint256 pageSize) constant returns([]Pocer)
+func (_NUC *NUCCaller) AllPocers(opts *bind.CallOpts, offset *big.Int, pageSize *big.Int) ([]Pocer, error) {
+	var (
+		ret0 = new([]Pocer)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "AllPocers", offset, pageSize)
+	return *ret0, err
+}
+
+// AllPocers is a free data retrieval call binding the contract method 0x12c81dd5.
+//
+// Solidity: function AllPocers(uint256 offset, uint256 pageSize) constant returns([]Pocer)
+func (_NUC *NUCSession) AllPocers(offset *big.Int, pageSize *big.Int) ([]Pocer, error) {
+	return _NUC.Contract.AllPocers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// AllPocers is a free data retrieval call binding the contract method 0x12c81dd5.
+//
+// Solidity: function AllPocers(uint256 offset, uint256 pageSize) constant returns([]Pocer)
+func (_NUC *NUCCallerSession) AllPocers(offset *big.Int, pageSize *big.Int) ([]Pocer, error) {
+	return _NUC.Contract.AllPocers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// AllPoolers is a free data retrieval call binding the contract method 0x8b996537.
+//
+// Solidity: function AllPoolers(uint256 offset, uint256 pageSize) constant returns([]Pooler)
+func (_NUC *NUCCaller) AllPoolers(opts *bind.CallOpts, offset *big.Int, pageSize *big.Int) ([]Pooler, error) {
+	var (
+		ret0 = new([]Pooler)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "AllPoolers", offset, pageSize)
+	return *ret0, err
+}
+
+// AllPoolers is a free data retrieval call binding the contract method 0x8b996537.
+//
+// Solidity: function AllPoolers(uint256 offset, uint256 pageSize) constant returns([]Pooler)
+func (_NUC *NUCSession) AllPoolers(offset *big.Int, pageSize *big.Int) ([]Pooler, error) {
+	return _NUC.Contract.AllPoolers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// AllPoolers is a free data retrieval call binding the contract method 0x8b996537.
+//
+// Solidity: function AllPoolers(uint256 offset, uint256 pageSize) constant returns([]Pooler)
+func (_NUC *NUCCallerSession) AllPoolers(offset *big.Int, pageSize *big.Int) ([]Pooler, error) {
+	return _NUC.Contract.AllPoolers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// AllPowers is a free data retrieval call binding the contract method 0x188919f9.
+//
+// Solidity: function AllPowers(uint256 offset, uint256 pageSize) constant returns([]Power)
+func (_NUC *NUCCaller) AllPowers(opts *bind.CallOpts, offset *big.Int, pageSize *big.Int) ([]Power, error) {
+	var (
+		ret0 = new([]Power)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "AllPowers", offset, pageSize)
+	return *ret0, err
+}
+
+// AllPowers is a free data retrieval call binding the contract method 0x188919f9.
+//
+// Solidity: function AllPowers(uint256 offset, uint256 pageSize) constant returns([]Power)
+func (_NUC *NUCSession) AllPowers(offset *big.Int, pageSize *big.Int) ([]Power, error) {
+	return _NUC.Contract.AllPowers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// AllPowers is a free data retrieval call binding the contract method 0x188919f9.
+//
+// Solidity: function AllPowers(uint256 offset, uint256 pageSize) constant returns([]Power)
+func (_NUC *NUCCallerSession) AllPowers(offset *big.Int, pageSize *big.Int) ([]Power, error) {
+	return _NUC.Contract.AllPowers(&_NUC.CallOpts, offset, pageSize)
+}
+
+// GetCurrentTime is a free data retrieval call binding the contract method 0xa60e02ab.
+//
+// Solidity: function GetCurrentTime() constant returns(uint256)
+func (_NUC *NUCCaller) GetCurrentTime(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetCurrentTime")
+	return *ret0, err
+}
+
+// GetCurrentTime is a free data retrieval call binding the contract method 0xa60e02ab.
+//
+// Solidity: function GetCurrentTime() constant returns(uint256)
+func (_NUC *NUCSession) GetCurrentTime() (*big.Int, error) {
+	return _NUC.Contract.GetCurrentTime(&_NUC.CallOpts)
+}
+
+// GetCurrentTime is a free data retrieval call binding the contract method 0xa60e02ab.
+//
+// Solidity: function GetCurrentTime() constant returns(uint256)
+func (_NUC *NUCCallerSession) GetCurrentTime() (*big.Int, error) {
+	return _NUC.Contract.GetCurrentTime(&_NUC.CallOpts)
+}
+
+// GetLeftFrozenBalance is a free data retrieval call binding the contract method 0xa735b75e.
+//
+// Solidity: function GetLeftFrozenBalance(address addr) constant returns(uint256)
+func (_NUC *NUCCaller) GetLeftFrozenBalance(opts *bind.CallOpts, addr common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetLeftFrozenBalance", addr)
+	return *ret0, err
+}
+
+// GetLeftFrozenBalance is a free data retrieval call binding the contract method 0xa735b75e.
+//
+// Solidity: function GetLeftFrozenBalance(address addr) constant returns(uint256)
+func (_NUC *NUCSession) GetLeftFrozenBalance(addr common.Address) (*big.Int, error) {
+	return _NUC.Contract.GetLeftFrozenBalance(&_NUC.CallOpts, addr)
+}
+
+// GetLeftFrozenBalance is a free data retrieval call binding the contract method 0xa735b75e.
+//
+// Solidity: function GetLeftFrozenBalance(address addr) constant returns(uint256)
+func (_NUC *NUCCallerSession) GetLeftFrozenBalance(addr common.Address) (*big.Int, error) {
+	return _NUC.Contract.GetLeftFrozenBalance(&_NUC.CallOpts, addr)
+}
+
+// GetPoCTicket is a free data retrieval call binding the contract method 0x001b2aeb.
+//
+// Solidity: function GetPoCTicket() constant returns(uint256)
+func (_NUC *NUCCaller) GetPoCTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPoCTicket")
+	return *ret0, err
+}
+
+// GetPoCTicket is a free data retrieval call binding the contract method 0x001b2aeb.
+//
+// Solidity: function GetPoCTicket() constant returns(uint256)
+func (_NUC *NUCSession) GetPoCTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPoCTicket(&_NUC.CallOpts)
+}
+
+// GetPoCTicket is a free data retrieval call binding the contract method 0x001b2aeb.
+//
+// Solidity: function GetPoCTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) GetPoCTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPoCTicket(&_NUC.CallOpts)
+}
+
+// GetPocer is a free data retrieval call binding the contract method 0x56f46892.
+//
+// Solidity: function GetPocer(address userAddr) constant returns(Pocer)
+func (_NUC *NUCCaller) GetPocer(opts *bind.CallOpts, userAddr common.Address) (Pocer, error) {
+	var (
+		ret0 = new(Pocer)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPocer", userAddr)
+	return *ret0, err
+}
+
+// GetPocer is a free data retrieval call binding the contract method 0x56f46892.
+//
+// Solidity: function GetPocer(address userAddr) constant returns(Pocer)
+func (_NUC *NUCSession) GetPocer(userAddr common.Address) (Pocer, error) {
+	return _NUC.Contract.GetPocer(&_NUC.CallOpts, userAddr)
+}
+
+// GetPocer is a free data retrieval call binding the contract method 0x56f46892.
+//
+// Solidity: function GetPocer(address userAddr) constant returns(Pocer)
+func (_NUC *NUCCallerSession) GetPocer(userAddr common.Address) (Pocer, error) {
+	return _NUC.Contract.GetPocer(&_NUC.CallOpts, userAddr)
+}
+
+// GetPoolTicket is a free data retrieval call binding the contract method 0xe7687d27.
+//
+// Solidity: function GetPoolTicket() constant returns(uint256)
+func (_NUC *NUCCaller) GetPoolTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPoolTicket")
+	return *ret0, err
+}
+
+// GetPoolTicket is a free data retrieval call binding the contract method 0xe7687d27.
+//
+// Solidity: function GetPoolTicket() constant returns(uint256)
+func (_NUC *NUCSession) GetPoolTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPoolTicket(&_NUC.CallOpts)
+}
+
+// GetPoolTicket is a free data retrieval call binding the contract method 0xe7687d27.
+//
+// Solidity: function GetPoolTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) GetPoolTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPoolTicket(&_NUC.CallOpts)
+}
+
+// GetPooler is a free data retrieval call binding the contract method 0x454fbaf1.
+//
+// Solidity: function GetPooler(address userAddr) constant returns(Pooler)
+func (_NUC *NUCCaller) GetPooler(opts *bind.CallOpts, userAddr common.Address) (Pooler, error) {
+	var (
+		ret0 = new(Pooler)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPooler", userAddr)
+	return *ret0, err
+}
+
+// GetPooler is a free data retrieval call binding the contract method 0x454fbaf1.
+//
+// Solidity: function GetPooler(address userAddr) constant returns(Pooler)
+func (_NUC *NUCSession) GetPooler(userAddr common.Address) (Pooler, error) {
+	return _NUC.Contract.GetPooler(&_NUC.CallOpts, userAddr)
+}
+
+// GetPooler is a free data retrieval call binding the contract method 0x454fbaf1.
+//
+// Solidity: function GetPooler(address userAddr) constant returns(Pooler)
+func (_NUC *NUCCallerSession) GetPooler(userAddr common.Address) (Pooler, error) {
+	return _NUC.Contract.GetPooler(&_NUC.CallOpts, userAddr)
+}
+
+// GetPowTicket is a free data retrieval call binding the contract method 0xaab907d0.
+//
+// Solidity: function GetPowTicket() constant returns(uint256)
+func (_NUC *NUCCaller) GetPowTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPowTicket")
+	return *ret0, err
+}
+
+// GetPowTicket is a free data retrieval call binding the contract method 0xaab907d0.
+//
+// Solidity: function GetPowTicket() constant returns(uint256)
+func (_NUC *NUCSession) GetPowTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPowTicket(&_NUC.CallOpts)
+}
+
+// GetPowTicket is a free data retrieval call binding the contract method 0xaab907d0.
+//
+// Solidity: function GetPowTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) GetPowTicket() (*big.Int, error) {
+	return _NUC.Contract.GetPowTicket(&_NUC.CallOpts)
+}
+
+// GetPower is a free data retrieval call binding the contract method 0xb8fac43b.
+//
+// Solidity: function GetPower(address userAddr) constant returns(Power)
+func (_NUC *NUCCaller) GetPower(opts *bind.CallOpts, userAddr common.Address) (Power, error) {
+	var (
+		ret0 = new(Power)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetPower", userAddr)
+	return *ret0, err
+}
+
+// GetPower is a free data retrieval call binding the contract method 0xb8fac43b.
+//
+// Solidity: function GetPower(address userAddr) constant returns(Power)
+func (_NUC *NUCSession) GetPower(userAddr common.Address) (Power, error) {
+	return _NUC.Contract.GetPower(&_NUC.CallOpts, userAddr)
+}
+
+// GetPower is a free data retrieval call binding the contract method 0xb8fac43b.
+//
+// Solidity: function GetPower(address userAddr) constant returns(Power)
+func (_NUC *NUCCallerSession) GetPower(userAddr common.Address) (Power, error) {
+	return _NUC.Contract.GetPower(&_NUC.CallOpts, userAddr)
+}
+
+// GetRewardRatio is a free data retrieval call binding the contract method 0x76b8dde1.
+//
+// Solidity: function GetRewardRatio() constant returns(uint256)
+func (_NUC *NUCCaller) GetRewardRatio(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "GetRewardRatio")
+	return *ret0, err
+}
+
+// GetRewardRatio is a free data retrieval call binding the contract method 0x76b8dde1.
+//
+// Solidity: function GetRewardRatio() constant returns(uint256)
+func (_NUC *NUCSession) GetRewardRatio() (*big.Int, error) {
+	return _NUC.Contract.GetRewardRatio(&_NUC.CallOpts)
+}
+
+// GetRewardRatio is a free data retrieval call binding the contract method 0x76b8dde1.
+//
+// Solidity: function GetRewardRatio() constant returns(uint256)
+func (_NUC *NUCCallerSession) GetRewardRatio() (*big.Int, error) {
+	return _NUC.Contract.GetRewardRatio(&_NUC.CallOpts)
+}
+
+// PocCount is a free data retrieval call binding the contract method 0xee35bf7e.
+//
+// Solidity: function PocCount() constant returns(uint256)
+func (_NUC *NUCCaller) PocCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PocCount")
+	return *ret0, err
+}
+
+// PocCount is a free data retrieval call binding the contract method 0xee35bf7e.
+//
+// Solidity: function PocCount() constant returns(uint256)
+func (_NUC *NUCSession) PocCount() (*big.Int, error) {
+	return _NUC.Contract.PocCount(&_NUC.CallOpts)
+}
+
+// PocCount is a free data retrieval call binding the contract method 0xee35bf7e.
+//
+// Solidity: function PocCount() constant returns(uint256)
+func (_NUC *NUCCallerSession) PocCount() (*big.Int, error) {
+	return _NUC.Contract.PocCount(&_NUC.CallOpts)
+}
+
+// PocExisted is a free data retrieval call binding the contract method 0x6459cb03.
+//
+// Solidity: function PocExisted(address _user) constant returns(bool)
+func (_NUC *NUCCaller) PocExisted(opts *bind.CallOpts, _user common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PocExisted", _user)
+	return *ret0, err
+}
+
+// PocExisted is a free data retrieval call binding the contract method 0x6459cb03.
+//
+// Solidity: function PocExisted(address _user) constant returns(bool)
+func (_NUC *NUCSession) PocExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PocExisted(&_NUC.CallOpts, _user)
+}
+
+// PocExisted is a free data retrieval call binding the contract method 0x6459cb03.
+//
+// Solidity: function PocExisted(address _user) constant returns(bool)
+func (_NUC *NUCCallerSession) PocExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PocExisted(&_NUC.CallOpts, _user)
+}
+
+// PoolCount is a free data retrieval call binding the contract method 0xb4894e51.
+//
+// Solidity: function PoolCount() constant returns(uint256)
+func (_NUC *NUCCaller) PoolCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PoolCount")
+	return *ret0, err
+}
+
+// PoolCount is a free data retrieval call binding the contract method 0xb4894e51.
+//
+// Solidity: function PoolCount() constant returns(uint256)
+func (_NUC *NUCSession) PoolCount() (*big.Int, error) {
+	return _NUC.Contract.PoolCount(&_NUC.CallOpts)
+}
+
+// PoolCount is a free data retrieval call binding the contract method 0xb4894e51.
+//
+// Solidity: function PoolCount() constant returns(uint256)
+func (_NUC *NUCCallerSession) PoolCount() (*big.Int, error) {
+	return _NUC.Contract.PoolCount(&_NUC.CallOpts)
+}
+
+// PoolExisted is a free data retrieval call binding the contract method 0xcd9899ce.
+//
+// Solidity: function PoolExisted(address _user) constant returns(bool)
+func (_NUC *NUCCaller) PoolExisted(opts *bind.CallOpts, _user common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PoolExisted", _user)
+	return *ret0, err
+}
+
+// PoolExisted is a free data retrieval call binding the contract method 0xcd9899ce.
+//
+// Solidity: function PoolExisted(address _user) constant returns(bool)
+func (_NUC *NUCSession) PoolExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PoolExisted(&_NUC.CallOpts, _user)
+}
+
+// PoolExisted is a free data retrieval call binding the contract method 0xcd9899ce.
+//
+// Solidity: function PoolExisted(address _user) constant returns(bool)
+func (_NUC *NUCCallerSession) PoolExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PoolExisted(&_NUC.CallOpts, _user)
+}
+
+// PowCount is a free data retrieval call binding the contract method 0xad6f0f8e.
+//
+// Solidity: function PowCount() constant returns(uint256)
+func (_NUC *NUCCaller) PowCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PowCount")
+	return *ret0, err
+}
+
+// PowCount is a free data retrieval call binding the contract method 0xad6f0f8e.
+//
+// Solidity: function PowCount() constant returns(uint256)
+func (_NUC *NUCSession) PowCount() (*big.Int, error) {
+	return _NUC.Contract.PowCount(&_NUC.CallOpts)
+}
+
+// PowCount is a free data retrieval call binding the contract method 0xad6f0f8e.
+//
+// Solidity: function PowCount() constant returns(uint256)
+func (_NUC *NUCCallerSession) PowCount() (*big.Int, error) {
+	return _NUC.Contract.PowCount(&_NUC.CallOpts)
+}
+
+// PowExisted is a free data retrieval call binding the contract method 0xe5177c97.
+//
+// Solidity: function PowExisted(address _user) constant returns(bool)
+func (_NUC *NUCCaller) PowExisted(opts *bind.CallOpts, _user common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "PowExisted", _user)
+	return *ret0, err
+}
+
+// PowExisted is a free data retrieval call binding the contract method 0xe5177c97.
+//
+// Solidity: function PowExisted(address _user) constant returns(bool)
+func (_NUC *NUCSession) PowExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PowExisted(&_NUC.CallOpts, _user)
+}
+
+// PowExisted is a free data retrieval call binding the contract method 0xe5177c97.
+//
+// Solidity: function PowExisted(address _user) constant returns(bool)
+func (_NUC *NUCCallerSession) PowExisted(_user common.Address) (bool, error) {
+	return _NUC.Contract.PowExisted(&_NUC.CallOpts, _user)
+}
+
+// BurnAmount is a free data retrieval call binding the contract method 0x486a7e6b.
+//
+// Solidity: function burnAmount() constant returns(uint256)
+func (_NUC *NUCCaller) BurnAmount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "burnAmount")
+	return *ret0, err
+}
+
+// BurnAmount is a free data retrieval call binding the contract method 0x486a7e6b.
+//
+// Solidity: function burnAmount() constant returns(uint256)
+func (_NUC *NUCSession) BurnAmount() (*big.Int, error) {
+	return _NUC.Contract.BurnAmount(&_NUC.CallOpts)
+}
+
+// BurnAmount is a free data retrieval call binding the contract method 0x486a7e6b.
+//
+// Solidity: function burnAmount() constant returns(uint256)
+func (_NUC *NUCCallerSession) BurnAmount() (*big.Int, error) {
+	return _NUC.Contract.BurnAmount(&_NUC.CallOpts)
+}
+
+// FrozenBalancers is a free data retrieval call binding the contract method 0x38929326.
+//
+// Solidity: function frozenBalancers(address ) constant returns(uint256 Start, uint256 End, address Addr, uint256 Balance)
+func (_NUC *NUCCaller) FrozenBalancers(opts *bind.CallOpts, arg0 common.Address) (struct {
+	Start   *big.Int
+	End     *big.Int
+	Addr    common.Address
+	Balance *big.Int
+}, error) {
+	ret := new(struct {
+		Start   *big.Int
+		End     *big.Int
+		Addr    common.Address
+		Balance *big.Int
+	})
+	out := ret
+	err := _NUC.contract.Call(opts, out, "frozenBalancers", arg0)
+	return *ret, err
+}
+
+// FrozenBalancers is a free data retrieval call binding the contract method 0x38929326.
+//
+// Solidity: function frozenBalancers(address ) constant returns(uint256 Start, uint256 End, address Addr, uint256 Balance)
+func (_NUC *NUCSession) FrozenBalancers(arg0 common.Address) (struct {
+	Start   *big.Int
+	End     *big.Int
+	Addr    common.Address
+	Balance *big.Int
+}, error) {
+	return _NUC.Contract.FrozenBalancers(&_NUC.CallOpts, arg0)
+}
+
+// FrozenBalancers is a free data retrieval call binding the contract method 0x38929326.
+//
+// Solidity: function frozenBalancers(address ) constant returns(uint256 Start, uint256 End, address Addr, uint256 Balance)
+func (_NUC *NUCCallerSession) FrozenBalancers(arg0 common.Address) (struct {
+	Start   *big.Int
+	End     *big.Int
+	Addr    common.Address
+	Balance *big.Int
+}, error) {
+	return _NUC.Contract.FrozenBalancers(&_NUC.CallOpts, arg0)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_NUC *NUCCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "owner")
+	return *ret0, err
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_NUC *NUCSession) Owner() (common.Address, error) {
+	return _NUC.Contract.Owner(&_NUC.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_NUC *NUCCallerSession) Owner() (common.Address, error) {
+	return _NUC.Contract.Owner(&_NUC.CallOpts)
+}
+
+// Owner1 is a free data retrieval call binding the contract method 0x73688914.
+//
+// Solidity: function owner1() constant returns(address)
+func (_NUC *NUCCaller) Owner1(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "owner1")
+	return *ret0, err
+}
+
+// Owner1 is a free data retrieval call binding the contract method 0x73688914.
+//
+// Solidity: function owner1() constant returns(address)
+func (_NUC *NUCSession) Owner1() (common.Address, error) {
+	return _NUC.Contract.Owner1(&_NUC.CallOpts)
+}
+
+// Owner1 is a free data retrieval call binding the contract method 0x73688914.
+//
+// Solidity: function owner1() constant returns(address)
+func (_NUC *NUCCallerSession) Owner1() (common.Address, error) {
+	return _NUC.Contract.Owner1(&_NUC.CallOpts)
+}
+
+// PocExpired is a free data retrieval call binding the contract method 0xd182247a.
+//
+// Solidity: function pocExpired() constant returns(uint256)
+func (_NUC *NUCCaller) PocExpired(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "pocExpired")
+	return *ret0, err
+}
+
+// PocExpired is a free data retrieval call binding the contract method 0xd182247a.
+//
+// Solidity: function pocExpired() constant returns(uint256)
+func (_NUC *NUCSession) PocExpired() (*big.Int, error) {
+	return _NUC.Contract.PocExpired(&_NUC.CallOpts)
+}
+
+// PocExpired is a free data retrieval call binding the contract method 0xd182247a.
+//
+// Solidity: function pocExpired() constant returns(uint256)
+func (_NUC *NUCCallerSession) PocExpired() (*big.Int, error) {
+	return _NUC.Contract.PocExpired(&_NUC.CallOpts)
+}
+
+// PocTicket is a free data retrieval call binding the contract method 0x57ba5892.
+//
+// Solidity: function pocTicket() constant returns(uint256)
+func (_NUC *NUCCaller) PocTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "pocTicket")
+	return *ret0, err
+}
+
+// PocTicket is a free data retrieval call binding the contract method 0x57ba5892.
+//
+// Solidity: function pocTicket() constant returns(uint256)
+func (_NUC *NUCSession) PocTicket() (*big.Int, error) {
+	return _NUC.Contract.PocTicket(&_NUC.CallOpts)
+}
+
+// PocTicket is a free data retrieval call binding the contract method 0x57ba5892.
+//
+// Solidity: function pocTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) PocTicket() (*big.Int, error) {
+	return _NUC.Contract.PocTicket(&_NUC.CallOpts)
+}
+
+// PoolTicket is a free data retrieval call binding the contract method 0x79f903dc.
+//
+// Solidity: function poolTicket() constant returns(uint256)
+func (_NUC *NUCCaller) PoolTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "poolTicket")
+	return *ret0, err
+}
+
+// PoolTicket is a free data retrieval call binding the contract method 0x79f903dc.
+//
+// Solidity: function poolTicket() constant returns(uint256)
+func (_NUC *NUCSession) PoolTicket() (*big.Int, error) {
+	return _NUC.Contract.PoolTicket(&_NUC.CallOpts)
+}
+
+// PoolTicket is a free data retrieval call binding the contract method 0x79f903dc.
+//
+// Solidity: function poolTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) PoolTicket() (*big.Int, error) {
+	return _NUC.Contract.PoolTicket(&_NUC.CallOpts)
+}
+
+// PowAddresses is a free data retrieval call binding the contract method 0x244ea7b7.
+//
+// Solidity: function powAddresses(uint256 ) constant returns(address)
+func (_NUC *NUCCaller) PowAddresses(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "powAddresses", arg0)
+	return *ret0, err
+}
+
+// PowAddresses is a free data retrieval call binding the contract method 0x244ea7b7.
+//
+// Solidity: function powAddresses(uint256 ) constant returns(address)
+func (_NUC *NUCSession) PowAddresses(arg0 *big.Int) (common.Address, error) {
+	return _NUC.Contract.PowAddresses(&_NUC.CallOpts, arg0)
+}
+
+// PowAddresses is a free data retrieval call binding the contract method 0x244ea7b7.
+//
+// Solidity: function powAddresses(uint256 ) constant returns(address)
+func (_NUC *NUCCallerSession) PowAddresses(arg0 *big.Int) (common.Address, error) {
+	return _NUC.Contract.PowAddresses(&_NUC.CallOpts, arg0)
+}
+
+// PowTicket is a free data retrieval call binding the contract method 0x7d57ae04.
+//
+// Solidity: function powTicket() constant returns(uint256)
+func (_NUC *NUCCaller) PowTicket(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "powTicket")
+	return *ret0, err
+}
+
+// PowTicket is a free data retrieval call binding the contract method 0x7d57ae04.
+//
+// Solidity: function powTicket() constant returns(uint256)
+func (_NUC *NUCSession) PowTicket() (*big.Int, error) {
+	return _NUC.Contract.PowTicket(&_NUC.CallOpts)
+}
+
+// PowTicket is a free data retrieval call binding the contract method 0x7d57ae04.
+//
+// Solidity: function powTicket() constant returns(uint256)
+func (_NUC *NUCCallerSession) PowTicket() (*big.Int, error) {
+	return _NUC.Contract.PowTicket(&_NUC.CallOpts)
+}
+
+// ReduceDuration is a free data retrieval call binding the contract method 0xaf8eb966.
+//
+// Solidity: function reduceDuration() constant returns(uint256)
+func (_NUC *NUCCaller) ReduceDuration(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "reduceDuration")
+	return *ret0, err
+}
+
+// ReduceDuration is a free data retrieval call binding the contract method 0xaf8eb966.
+//
+// Solidity: function reduceDuration() constant returns(uint256)
+func (_NUC *NUCSession) ReduceDuration() (*big.Int, error) {
+	return _NUC.Contract.ReduceDuration(&_NUC.CallOpts)
+}
+
+// ReduceDuration is a free data retrieval call binding the contract method 0xaf8eb966.
+//
+// Solidity: function reduceDuration() constant returns(uint256)
+func (_NUC *NUCCallerSession) ReduceDuration() (*big.Int, error) {
+	return _NUC.Contract.ReduceDuration(&_NUC.CallOpts)
+}
+
+// Start is a free data retrieval call binding the contract method 0xbe9a6555.
+//
+// Solidity: function start() constant returns(uint256)
+func (_NUC *NUCCaller) Start(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _NUC.contract.Call(opts, out, "start")
+	return *ret0, err
+}
+
+// Start is a free data retrieval call binding the contract method 0xbe9a6555.
+//
+// Solidity: function start() constant returns(uint256)
+func (_NUC *NUCSession) Start() (*big.Int, error) {
+	return _NUC.Contract.Start(&_NUC.CallOpts)
+}
+
+// Start is a free data retrieval call binding the contract method 0xbe9a6555.
+//
+// Solidity: function start() constant returns(uint256)
+func (_NUC *NUCCallerSession) Start() (*big.Int, error) {
+	return _NUC.Contract.Start(&_NUC.CallOpts)
+}
+
+// BuyPoc is a paid mutator transaction binding the contract method 0xadb7839e.
+//
+// Solidity: function BuyPoc() returns()
+func (_NUC *NUCTransactor) BuyPoc(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "BuyPoc")
+}
+
+// BuyPoc is a paid mutator transaction binding the contract method 0xadb7839e.
+//
+// Solidity: function BuyPoc() returns()
+func (_NUC *NUCSession) BuyPoc() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPoc(&_NUC.TransactOpts)
+}
+
+// BuyPoc is a paid mutator transaction binding the contract method 0xadb7839e.
+//
+// Solidity: function BuyPoc() returns()
+func (_NUC *NUCTransactorSession) BuyPoc() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPoc(&_NUC.TransactOpts)
+}
+
+// BuyPool is a paid mutator transaction binding the contract method 0x5e622485.
+//
+// Solidity: function BuyPool() returns()
+func (_NUC *NUCTransactor) BuyPool(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "BuyPool")
+}
+
+// BuyPool is a paid mutator transaction binding the contract method 0x5e622485.
+//
+// Solidity: function BuyPool() returns()
+func (_NUC *NUCSession) BuyPool() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPool(&_NUC.TransactOpts)
+}
+
+// BuyPool is a paid mutator transaction binding the contract method 0x5e622485.
+//
+// Solidity: function BuyPool() returns()
+func (_NUC *NUCTransactorSession) BuyPool() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPool(&_NUC.TransactOpts)
+}
+
+// BuyPow is a paid mutator transaction binding the contract method 0x7ac75202.
+//
+// Solidity: function BuyPow() returns()
+func (_NUC *NUCTransactor) BuyPow(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "BuyPow")
+}
+
+// BuyPow is a paid mutator transaction binding the contract method 0x7ac75202.
+//
+// Solidity: function BuyPow() returns()
+func (_NUC *NUCSession) BuyPow() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPow(&_NUC.TransactOpts)
+}
+
+// BuyPow is a paid mutator transaction binding the contract method 0x7ac75202.
+//
+// Solidity: function BuyPow() returns()
+func (_NUC *NUCTransactorSession) BuyPow() (*types.Transaction, error) {
+	return _NUC.Contract.BuyPow(&_NUC.TransactOpts)
+}
+
+// ChangeOwner is a paid mutator transaction binding the contract method 0xf2853292.
+//
+// Solidity: function ChangeOwner(address userAddr) returns()
+func (_NUC *NUCTransactor) ChangeOwner(opts *bind.TransactOpts, userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "ChangeOwner", userAddr)
+}
+
+// ChangeOwner is a paid mutator transaction binding the contract method 0xf2853292.
+//
+// Solidity: function ChangeOwner(address userAddr) returns()
+func (_NUC *NUCSession) ChangeOwner(userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.ChangeOwner(&_NUC.TransactOpts, userAddr)
+}
+
+// ChangeOwner is a paid mutator transaction binding the contract method 0xf2853292.
+//
+// Solidity: function ChangeOwner(address userAddr) returns()
+func (_NUC *NUCTransactorSession) ChangeOwner(userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.ChangeOwner(&_NUC.TransactOpts, userAddr)
+}
+
+// ChangeOwner1 is a paid mutator transaction binding the contract method 0xfdfb50f7.
+//
+// Solidity: function ChangeOwner1(address userAddr) returns()
+func (_NUC *NUCTransactor) ChangeOwner1(opts *bind.TransactOpts, userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "ChangeOwner1", userAddr)
+}
+
+// ChangeOwner1 is a paid mutator transaction binding the contract method 0xfdfb50f7.
+//
+// Solidity: function ChangeOwner1(address userAddr) returns()
+func (_NUC *NUCSession) ChangeOwner1(userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.ChangeOwner1(&_NUC.TransactOpts, userAddr)
+}
+
+// ChangeOwner1 is a paid mutator transaction binding the contract method 0xfdfb50f7.
+//
+// Solidity: function ChangeOwner1(address userAddr) returns()
+func (_NUC *NUCTransactorSession) ChangeOwner1(userAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.ChangeOwner1(&_NUC.TransactOpts, userAddr)
+}
+
+// InitContract is a paid mutator transaction binding the contract method 0xd385f658.
+//
+// Solidity: function InitContract() returns()
+func (_NUC *NUCTransactor) InitContract(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "InitContract")
+}
+
+// InitContract is a paid mutator transaction binding the contract method 0xd385f658.
+//
+// Solidity: function InitContract() returns()
+func (_NUC *NUCSession) InitContract() (*types.Transaction, error) {
+	return _NUC.Contract.InitContract(&_NUC.TransactOpts)
+}
+
+// InitContract is a paid mutator transaction binding the contract method 0xd385f658.
+//
+// Solidity: function InitContract() returns()
+func (_NUC *NUCTransactorSession) InitContract() (*types.Transaction, error) {
+	return _NUC.Contract.InitContract(&_NUC.TransactOpts)
+}
+
+// InitPoc is a paid mutator transaction binding the contract method 0xefe4c0ed.
+//
+// Solidity: function InitPoc(address userAddr, uint256 times) returns()
+func (_NUC *NUCTransactor) InitPoc(opts *bind.TransactOpts, userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "InitPoc", userAddr, times)
+}
+
+// InitPoc is a paid mutator transaction binding the contract method 0xefe4c0ed.
+//
+// Solidity: function InitPoc(address userAddr, uint256 times) returns()
+func (_NUC *NUCSession) InitPoc(userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.InitPoc(&_NUC.TransactOpts, userAddr, times)
+}
+
+// InitPoc is a paid mutator transaction binding the contract method 0xefe4c0ed.
+//
+// Solidity: function InitPoc(address userAddr, uint256 times) returns()
+func (_NUC *NUCTransactorSession) InitPoc(userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.InitPoc(&_NUC.TransactOpts, userAddr, times)
+}
+
+// InitPow is a paid mutator transaction binding the contract method 0x11d89a4a.
+//
+// Solidity: function InitPow(address userAddr, uint256 times) returns()
+func (_NUC *NUCTransactor) InitPow(opts *bind.TransactOpts, userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "InitPow", userAddr, times)
+}
+
+// InitPow is a paid mutator transaction binding the contract method 0x11d89a4a.
+//
+// Solidity: function InitPow(address userAddr, uint256 times) returns()
+func (_NUC *NUCSession) InitPow(userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.InitPow(&_NUC.TransactOpts, userAddr, times)
+}
+
+// InitPow is a paid mutator transaction binding the contract method 0x11d89a4a.
+//
+// Solidity: function InitPow(address userAddr, uint256 times) returns()
+func (_NUC *NUCTransactorSession) InitPow(userAddr common.Address, times *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.InitPow(&_NUC.TransactOpts, userAddr, times)
+}
+
+// PocBindPoolUser is a paid mutator transaction binding the contract method 0x19b76a92.
+//
+// Solidity: function PocBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCTransactor) PocBindPoolUser(opts *bind.TransactOpts, poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "PocBindPoolUser", poolUserAddr)
+}
+
+// PocBindPoolUser is a paid mutator transaction binding the contract method 0x19b76a92.
+//
+// Solidity: function PocBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCSession) PocBindPoolUser(poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.PocBindPoolUser(&_NUC.TransactOpts, poolUserAddr)
+}
+
+// PocBindPoolUser is a paid mutator transaction binding the contract method 0x19b76a92.
+//
+// Solidity: function PocBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCTransactorSession) PocBindPoolUser(poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.PocBindPoolUser(&_NUC.TransactOpts, poolUserAddr)
+}
+
+// PowBindPoolUser is a paid mutator transaction binding the contract method 0x257216f1.
+//
+// Solidity: function PowBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCTransactor) PowBindPoolUser(opts *bind.TransactOpts, poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "PowBindPoolUser", poolUserAddr)
+}
+
+// PowBindPoolUser is a paid mutator transaction binding the contract method 0x257216f1.
+//
+// Solidity: function PowBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCSession) PowBindPoolUser(poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.PowBindPoolUser(&_NUC.TransactOpts, poolUserAddr)
+}
+
+// PowBindPoolUser is a paid mutator transaction binding the contract method 0x257216f1.
+//
+// Solidity: function PowBindPoolUser(address poolUserAddr) returns()
+func (_NUC *NUCTransactorSession) PowBindPoolUser(poolUserAddr common.Address) (*types.Transaction, error) {
+	return _NUC.Contract.PowBindPoolUser(&_NUC.TransactOpts, poolUserAddr)
+}
+
+// SetFrozenBalance is a paid mutator transaction binding the contract method 0xfc12d193.
+//
+// Solidity: function SetFrozenBalance(address addr, uint256 _start, uint256 _end, uint256 balance) returns()
+func (_NUC *NUCTransactor) SetFrozenBalance(opts *bind.TransactOpts, addr common.Address, _start *big.Int, _end *big.Int, balance *big.Int) (*types.Transaction, error) {
+	return _NUC.contract.Transact(opts, "SetFrozenBalance", addr, _start, _end, balance)
+}
+
+// SetFrozenBalance is a paid mutator transaction binding the contract method 0xfc12d193.
+//
+// Solidity: function SetFrozenBalance(address addr, uint256 _start, uint256 _end, uint256 balance) returns()
+func (_NUC *NUCSession) SetFrozenBalance(addr common.Address, _start *big.Int, _end *big.Int, balance *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.SetFrozenBalance(&_NUC.TransactOpts, addr, _start, _end, balance)
+}
+
+// SetFrozenBalance is a paid mutator transaction binding the contract method 0xfc12d193.
+//
+// Solidity: function SetFrozenBalance(address addr, uint256 _start, uint256 _end, uint256 balance) returns()
+func (_NUC *NUCTransactorSession) SetFrozenBalance(addr common.Address, _start *big.Int, _end *big.Int, balance *big.Int) (*types.Transaction, error) {
+	return _NUC.Contract.SetFrozenBalance(&_NUC.TransactOpts, addr, _start, _end, balance)
 }
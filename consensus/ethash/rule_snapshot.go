@@ -0,0 +1,471 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+//go:generate abigen --abi nuc_token/v1/nuc_token.abi --pkg v1 --type NUC --out nuc_token/v1/nuc_token.go
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	v1 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v1"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	golanglru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// ruleSnapshotCacheSize is the number of rule contract snapshots to keep in memory.
+	ruleSnapshotCacheSize = 32
+
+	// rulePageSize is the number of participants fetched by a single list call.
+	rulePageSize = 1000
+)
+
+var (
+	// errRuleContractReverted is returned if a legacy rule contract call fails in
+	// the EVM. System calls report the reason instead, see core.RevertError.
+	errRuleContractReverted = errors.New("rule contract call reverted")
+
+	// errNoSystemCaller is returned if the rule contract is called through a chain
+	// that can neither run it in the EVM nor answer the calls itself.
+	errNoSystemCaller = errors.New("chain can't call the rule contract")
+)
+
+// ruleSnapshots memoises the rule contract snapshots by block context.
+var ruleSnapshots, _ = golanglru.New(ruleSnapshotCacheSize)
+
+// ruleReader reads the participant lists and the reward ratio out of a version
+// of the rule contract ABI, into the types of the snapshot.
+type ruleReader interface {
+	PocerCount() (*big.Int, error)
+	AllPocers(offset *big.Int, pageSize *big.Int) ([]RulePocer, error)
+	PowerCount() (*big.Int, error)
+	AllPowers(offset *big.Int, pageSize *big.Int) ([]RulePower, error)
+	PoolerCount() (*big.Int, error)
+	AllPoolers(offset *big.Int, pageSize *big.Int) ([]RulePooler, error)
+	GetRewardRatio() (*big.Int, error)
+}
+
+// ruleReaders maps the rule contract ABI versions that can be scheduled in the
+// chain config to the Go callers reading them.
+var ruleReaders = map[uint64]func(caller *ruleContractCaller) ruleReader{
+	params.NUCRuleV1: func(caller *ruleContractCaller) ruleReader { return newV1RuleReader(caller) },
+}
+
+// v1RuleReader maps the participants read through the v1 rule contract binding
+// into the types of the snapshot.
+//
+// The list getters return whatever could be unpacked from a successful call and
+// only fail if the call itself did: the reward algorithms page through the lists
+// until the first error, so this behaviour is part of consensus.
+type v1RuleReader struct {
+	binding *v1.NUCCaller
+	caller  *boundRuleCaller
+}
+
+// newV1RuleReader creates a reader of the v1 rule contract ABI.
+func newV1RuleReader(caller *ruleContractCaller) ruleReader {
+	bound := &boundRuleCaller{caller: caller}
+	binding, err := v1.NewNUCCaller(params.NUCRuleContractAddress, bound)
+	if err != nil {
+		panic(err) // The ABI is generated along with the binding, it always parses
+	}
+	return &v1RuleReader{binding: binding, caller: bound}
+}
+
+// PocerCount implements ruleReader.
+func (r *v1RuleReader) PocerCount() (*big.Int, error) {
+	return r.binding.PocCount(nil)
+}
+
+// PowerCount implements ruleReader.
+func (r *v1RuleReader) PowerCount() (*big.Int, error) {
+	return r.binding.PowCount(nil)
+}
+
+// PoolerCount implements ruleReader.
+func (r *v1RuleReader) PoolerCount() (*big.Int, error) {
+	return r.binding.PoolCount(nil)
+}
+
+// GetRewardRatio implements ruleReader.
+func (r *v1RuleReader) GetRewardRatio() (*big.Int, error) {
+	return r.binding.GetRewardRatio(nil)
+}
+
+// AllPocers implements ruleReader.
+func (r *v1RuleReader) AllPocers(offset *big.Int, pageSize *big.Int) ([]RulePocer, error) {
+	users, _ := r.binding.AllPocers(nil, offset, pageSize)
+	pocers := make([]RulePocer, len(users))
+	for i, u := range users {
+		pocers[i] = RulePocer{
+			Records:        v1RuleRecords(u.Records),
+			UserAddr:       u.UserAddr,
+			GetReward:      u.GetReward,
+			Index:          u.Index,
+			MortageBalance: u.MortageBalance,
+			BindPoolAddr:   u.BindPoolAddr,
+		}
+	}
+	return pocers, r.caller.err
+}
+
+// AllPowers implements ruleReader.
+func (r *v1RuleReader) AllPowers(offset *big.Int, pageSize *big.Int) ([]RulePower, error) {
+	users, _ := r.binding.AllPowers(nil, offset, pageSize)
+	powers := make([]RulePower, len(users))
+	for i, u := range users {
+		powers[i] = RulePower{
+			CreateTime:   u.CreateTime,
+			Index:        u.Index,
+			BuyBalance:   u.BuyBalance,
+			BindPoolAddr: u.BindPoolAddr,
+			PocAddrs:     u.PocAddrs,
+			UserAddr:     u.UserAddr,
+			Records:      v1RuleRecords(u.Records),
+		}
+	}
+	return powers, r.caller.err
+}
+
+// AllPoolers implements ruleReader.
+func (r *v1RuleReader) AllPoolers(offset *big.Int, pageSize *big.Int) ([]RulePooler, error) {
+	users, _ := r.binding.AllPoolers(nil, offset, pageSize)
+	poolers := make([]RulePooler, len(users))
+	for i, u := range users {
+		poolers[i] = RulePooler{
+			CreateTime: u.CreateTime,
+			Index:      u.Index,
+			BuyBalance: u.BuyBalance,
+			PowAddrs:   u.PowAddrs,
+			PocAddrs:   u.PocAddrs,
+			UserAddr:   u.UserAddr,
+		}
+	}
+	return poolers, r.caller.err
+}
+
+// v1RuleRecords maps the mining records of a v1 participant.
+func v1RuleRecords(records []v1.Record) []RuleRecord {
+	mapped := make([]RuleRecord, len(records))
+	for i, r := range records {
+		mapped[i] = RuleRecord{CreateTime: r.CreateTime}
+	}
+	return mapped
+}
+
+// newRuleReader creates the reader of the rule contract ABI in force at the
+// caller's block.
+func newRuleReader(caller *ruleContractCaller) ruleReader {
+	version := caller.chain.Config().NUCRuleVersion(caller.header.Number)
+	create, ok := ruleReaders[version]
+	if !ok {
+		// Unreachable with a valid chain config, see params.NUCRuleLatest
+		log.Error("Unknown NUC rule contract version", "number", caller.header.Number, "version", version)
+		create = ruleReaders[params.NUCRuleV1]
+	}
+	return create(caller)
+}
+
+// RuleContractBackend is implemented by chains answering the rule contract calls
+// themselves instead of running the contract in the EVM, e.g. to simulate the
+// rewards of synthetic participants. Errors are returned to the callers as is.
+type RuleContractBackend interface {
+	CallRuleContract(header *types.Header, input []byte) ([]byte, error)
+}
+
+// applyRuleCall runs a call of the rule contract in the context of the given
+// block and state. The EVM is run by the chain, which must either implement
+// consensus.SystemCaller or answer the calls itself as a RuleContractBackend.
+//
+// From the NUC system call fork on, the call is a side-effect-free system call
+// leaving the state untouched. Before it, the call is a message sent by the
+// contract to itself, bumping its nonce and touching the block's coinbase.
+func applyRuleCall(input []byte, header *types.Header, c consensus.ChainReader, statedb vm.StateDB) ([]byte, error) {
+	if backend, ok := c.(RuleContractBackend); ok {
+		return backend.CallRuleContract(header, input)
+	}
+	caller, ok := c.(consensus.SystemCaller)
+	if !ok {
+		return nil, errNoSystemCaller
+	}
+	if sdb, ok := statedb.(*state.StateDB); ok && c.Config().IsNUCSystemCall(header.Number) {
+		return caller.SystemCall(header, sdb, params.NUCRuleContractAddress, params.NUCRuleContractAddress, input, CallContractGuessGas)
+	}
+	res, failed, err := caller.ApplySystemMessage(header, statedb, params.NUCRuleContractAddress, params.NUCRuleContractAddress, input, CallContractGuessGas)
+	if err == nil && failed {
+		err = errRuleContractReverted
+	}
+	return res, err
+}
+
+// ruleContractCaller runs read-only calls of the rule contract in the context of
+// the given block and state.
+type ruleContractCaller struct {
+	header *types.Header
+	chain  consensus.ChainReader
+	state  vm.StateDB
+}
+
+// newRuleContractCaller creates a rule contract caller on top of a state.
+func newRuleContractCaller(header *types.Header, state vm.StateDB, c consensus.ChainReader) *ruleContractCaller {
+	return &ruleContractCaller{
+		header: header,
+		chain:  c,
+		state:  state,
+	}
+}
+
+// CallContract implements v0.ContractCaller.
+func (rc *ruleContractCaller) CallContract(input []byte) ([]byte, error) {
+	res, err := applyRuleCall(input, rc.header, rc.chain, rc.state)
+	if err != nil {
+		log.Debug("Rule contract call failed", "input", hex.EncodeToString(input), "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// boundRuleCaller serves the calls of an abigen binding of the rule contract
+// through a rule contract caller, remembering the error of the last call.
+type boundRuleCaller struct {
+	caller *ruleContractCaller
+	err    error // Error of the last call, unpacking errors excluded
+}
+
+// CodeAt implements bind.ContractCaller, returning the code in the caller's state.
+func (c *boundRuleCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.caller.state.GetCode(contract), nil
+}
+
+// CallContract implements bind.ContractCaller, running the call in the caller's
+// block context.
+func (c *boundRuleCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	res, err := c.caller.CallContract(call.Data)
+	c.err = err
+	return res, err
+}
+
+// RuleRecord is a mining record of a rule contract participant.
+type RuleRecord struct {
+	CreateTime *big.Int
+}
+
+// RulePocer is a PoC participant of the rule contract.
+type RulePocer struct {
+	Records        []RuleRecord
+	UserAddr       common.Address
+	GetReward      *big.Int
+	Index          *big.Int
+	MortageBalance *big.Int
+	BindPoolAddr   common.Address
+}
+
+// CanGetMaxReward returns the maximum PoC reward the participant may earn, 120%
+// of its mortgage.
+func (p *RulePocer) CanGetMaxReward() *big.Int {
+	reward := new(big.Int).Mul(p.MortageBalance, big.NewInt(120))
+	return reward.Div(reward, big.NewInt(100))
+}
+
+// RulePower is a PoW participant of the rule contract.
+type RulePower struct {
+	CreateTime   *big.Int
+	Index        *big.Int
+	BuyBalance   *big.Int
+	BindPoolAddr common.Address
+	PocAddrs     []common.Address
+	UserAddr     common.Address
+	Records      []RuleRecord
+}
+
+// RulePooler is a pool participant of the rule contract.
+type RulePooler struct {
+	CreateTime *big.Int
+	Index      *big.Int
+	BuyBalance *big.Int
+	PowAddrs   []common.Address
+	PocAddrs   []common.Address
+	UserAddr   common.Address
+}
+
+// RuleParticipants are the participant sets registered in the rule contract,
+// independent of the contract ABI version they were read through.
+type RuleParticipants struct {
+	Pocers  []RulePocer
+	Powers  []RulePower
+	Poolers []RulePooler
+}
+
+// RuleSnapshot is the participant data of the NUC rule contract as of a given
+// state, read in a single pass. Snapshots are shared between all the callers
+// asking for the same block context and must be treated as read-only.
+type RuleSnapshot struct {
+	Root common.Hash // State root the snapshot was taken at
+
+	RuleParticipants
+	RewardRatio *big.Int // Block reward halving exponent, nil if unavailable
+
+	// Message calls needed to read the lists, see chargeRuleCalls
+	pocCalls   uint64
+	powCalls   uint64
+	poolCalls  uint64
+	ratioCalls uint64
+}
+
+// ruleSnapshotKey identifies a rule contract snapshot. Besides the state, the
+// contract can see the block it's called in, so that's part of the key too.
+type ruleSnapshotKey struct {
+	root     common.Hash
+	number   uint64
+	time     uint64
+	coinbase common.Address
+}
+
+// GetRuleSnapshot returns the rule contract snapshot for the block being built
+// on top of the given state, reading the contract if it's not memoised yet. The
+// given state is left untouched. Snapshots served by a RuleContractBackend
+// aren't memoised, as they don't depend on the state alone, and always come from
+// the list getters.
+//
+// From the first NUC rule storage layout on, the participant lists are decoded
+// from the contract storage instead of being paged through the list getters.
+func GetRuleSnapshot(header *types.Header, state *state.StateDB, c consensus.ChainReader) *RuleSnapshot {
+	statedb := state.Copy()
+	key := ruleSnapshotKey{
+		root:     statedb.IntermediateRoot(c.Config().IsEIP158(header.Number)),
+		number:   header.Number.Uint64(),
+		time:     header.Time,
+		coinbase: header.Coinbase,
+	}
+	if _, ok := c.(RuleContractBackend); ok {
+		return readRuleSnapshot(key.root, newRuleReader(newRuleContractCaller(header, statedb, c)))
+	}
+	if snap, ok := ruleSnapshots.Get(key); ok {
+		return snap.(*RuleSnapshot)
+	}
+	var (
+		caller = newRuleReader(newRuleContractCaller(header, statedb, c))
+		snap   *RuleSnapshot
+	)
+	if layout := c.Config().NUCRuleStorageLayout(header.Number); layout != nil {
+		snap = readRuleStorageSnapshot(key.root, statedb, layout, caller)
+	} else {
+		snap = readRuleSnapshot(key.root, caller)
+	}
+	ruleSnapshots.Add(key, snap)
+	return snap
+}
+
+// readRuleSnapshot reads all the participant lists and the reward ratio from the
+// rule contract.
+func readRuleSnapshot(root common.Hash, caller ruleReader) *RuleSnapshot {
+	snap := &RuleSnapshot{Root: root}
+
+	snap.poolCalls = pageRuleList(caller.PoolerCount, func(offset, size *big.Int) error {
+		users, err := caller.AllPoolers(offset, size)
+		snap.Poolers = append(snap.Poolers, users...)
+		return err
+	})
+	snap.pocCalls = pageRuleList(caller.PocerCount, func(offset, size *big.Int) error {
+		users, err := caller.AllPocers(offset, size)
+		snap.Pocers = append(snap.Pocers, users...)
+		return err
+	})
+	snap.powCalls = pageRuleList(caller.PowerCount, func(offset, size *big.Int) error {
+		users, err := caller.AllPowers(offset, size)
+		snap.Powers = append(snap.Powers, users...)
+		return err
+	})
+	snap.ratioCalls = 1
+	if ratio, err := caller.GetRewardRatio(); err == nil {
+		snap.RewardRatio = ratio
+	}
+	return snap
+}
+
+// readRuleStorageSnapshot decodes the participant lists from the rule contract
+// storage, only calling the contract for the reward ratio. If the storage can't
+// be decoded, the lists are left empty.
+func readRuleStorageSnapshot(root common.Hash, statedb *state.StateDB, layout *params.NUCRuleStorageLayout, caller ruleReader) *RuleSnapshot {
+	snap := &RuleSnapshot{Root: root}
+
+	participants, err := ReadRuleParticipants(statedb, layout)
+	if err != nil {
+		log.Warn("Rule contract storage undecodable", "root", root, "err", err)
+	} else {
+		snap.RuleParticipants = *participants
+	}
+	snap.ratioCalls = 1
+	if ratio, err := caller.GetRewardRatio(); err == nil {
+		snap.RewardRatio = ratio
+	}
+	return snap
+}
+
+// pageRuleList pages through a participant list of the rule contract the same
+// way the reward algorithms always did: nothing is read if the count isn't
+// available and reading stops at the first failing page. It returns the number
+// of message calls made.
+func pageRuleList(count func() (*big.Int, error), page func(offset, size *big.Int) error) uint64 {
+	total, err := count()
+	if err != nil {
+		return 1
+	}
+	calls := uint64(1)
+	pages := (total.Uint64() + rulePageSize - 1) / rulePageSize
+	for p := uint64(0); p < pages; p++ {
+		calls++
+		if err := page(new(big.Int).SetUint64(p*rulePageSize), big.NewInt(rulePageSize)); err != nil {
+			break
+		}
+	}
+	return calls
+}
+
+// chargeRuleCalls applies the state changes of reading the rule contract through
+// the given number of message calls. The reward algorithms used to make a call
+// for every lookup, each one bumping the nonce of the contract (calling itself)
+// and touching the block's coinbase. The state root depends on these changes, so
+// they are preserved even though the data now comes from a single snapshot, up
+// to the NUC system call fork which does away with them.
+func chargeRuleCalls(header *types.Header, state *state.StateDB, calls uint64) {
+	if calls == 0 {
+		return
+	}
+	state.SetNonce(params.NUCRuleContractAddress, state.GetNonce(params.NUCRuleContractAddress)+calls)
+	state.AddBalance(header.Coinbase, new(big.Int))
+}
+
+// Participants implements consensus.ParticipantReporter, listing the participants
+// of the rule contract snapshot taken at the given state.
+func (ethash *Ethash) Participants(chain consensus.ChainReader, header *types.Header, state *state.StateDB) []*types.Participant {
+	snap := GetRuleSnapshot(header, state, chain)
+
+	participants := make([]*types.Participant, 0, len(snap.Pocers)+len(snap.Powers)+len(snap.Poolers))
+	for _, u := range snap.Pocers {
+		participants = append(participants, &types.Participant{Role: types.PocParticipant, Address: u.UserAddr, Pool: u.BindPoolAddr, Amount: u.MortageBalance})
+	}
+	for _, u := range snap.Powers {
+		participants = append(participants, &types.Participant{Role: types.PowParticipant, Address: u.UserAddr, Pool: u.BindPoolAddr, Amount: u.BuyBalance})
+	}
+	for _, u := range snap.Poolers {
+		participants = append(participants, &types.Participant{Role: types.PoolParticipant, Address: u.UserAddr, Amount: u.BuyBalance})
+	}
+	return participants
+}
+
+// ParticipantRegistry implements consensus.ParticipantReporter, the participants
+// being registered in the rule contract.
+func (ethash *Ethash) ParticipantRegistry(chain consensus.ChainReader, header *types.Header) common.Address {
+	return params.NUCRuleContractAddress
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that participant lists are paged through with the legacy call pattern.
+func TestPageRuleList(t *testing.T) {
+	errFail := errors.New("fail")
+	tests := []struct {
+		count    int64
+		countErr error
+		failPage int // Page to fail at, -1 for none
+		calls    uint64
+		pages    int
+	}{
+		{0, nil, -1, 1, 0},
+		{1, nil, -1, 2, 1},
+		{1000, nil, -1, 2, 1},
+		{1001, nil, -1, 3, 2},
+		{2500, nil, 1, 3, 1},
+		{2500, nil, 0, 2, 0},
+		{2500, errFail, -1, 1, 0},
+	}
+	for i, tt := range tests {
+		var pages int
+		calls := pageRuleList(func() (*big.Int, error) {
+			if tt.countErr != nil {
+				return nil, tt.countErr
+			}
+			return big.NewInt(tt.count), nil
+		}, func(offset, size *big.Int) error {
+			if offset.Int64() != int64(pages*rulePageSize) || size.Int64() != rulePageSize {
+				t.Errorf("test %d: page mismatch: have %v/%v, want %d/%d", i, offset, size, pages*rulePageSize, rulePageSize)
+			}
+			if pages == tt.failPage {
+				return errFail
+			}
+			pages++
+			return nil
+		})
+		if calls != tt.calls || pages != tt.pages {
+			t.Errorf("test %d: have %d calls/%d pages, want %d calls/%d pages", i, calls, pages, tt.calls, tt.pages)
+		}
+	}
+}
+
+// Tests that rule snapshots are memoised, leave the state untouched and that
+// charging for the calls has the same effect on the state as making them.
+func TestRuleSnapshot(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = (&core.Genesis{Config: params.TestChainConfig}).MustCommit(db)
+	)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	header := &types.Header{
+		ParentHash:    genesis.Hash(),
+		Number:        big.NewInt(1),
+		Coinbase:      common.Address{0x01},
+		Difficulty:    big.NewInt(1),
+		NUCDifficulty: big.NewInt(1),
+		GasLimit:      genesis.GasLimit(),
+		Time:          genesis.Time() + 10,
+	}
+	statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+	root := statedb.IntermediateRoot(true)
+
+	snap := GetRuleSnapshot(header, statedb, chain)
+	if snap.Root != root {
+		t.Fatalf("snapshot root mismatch: have %x, want %x", snap.Root, root)
+	}
+	if have := statedb.IntermediateRoot(true); have != root {
+		t.Fatalf("state modified by snapshot: have %x, want %x", have, root)
+	}
+	if again := GetRuleSnapshot(header, statedb, chain); again != snap {
+		t.Fatalf("snapshot not memoised")
+	}
+	later := types.CopyHeader(header)
+	later.Time++
+	if other := GetRuleSnapshot(later, statedb, chain); other == snap {
+		t.Fatalf("snapshot shared between block contexts")
+	}
+	calls := snap.poolCalls + snap.pocCalls + snap.powCalls + snap.ratioCalls
+	if calls == 0 {
+		t.Fatalf("no rule contract calls recorded")
+	}
+	called := statedb.Copy()
+	caller := newRuleContractCaller(header, called, chain)
+	for i := uint64(0); i < calls; i++ {
+		caller.CallContract(nil)
+	}
+	charged := statedb.Copy()
+	chargeRuleCalls(header, charged, calls)
+
+	if have, want := charged.IntermediateRoot(true), called.IntermediateRoot(true); have != want {
+		t.Fatalf("charged state root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that from the NUC system call fork on, reading the rule contract leaves
+// no trace in the state: the post-Finalize state root only reflects the reward
+// credits, while the legacy reads bump the contract's nonce.
+func TestRuleSystemCalls(t *testing.T) {
+	for _, fork := range []*big.Int{nil, common.Big0} {
+		config := *params.TestChainConfig
+		config.NUC = &params.NUCConfig{SystemCallBlock: fork}
+
+		var (
+			db      = rawdb.NewMemoryDatabase()
+			genesis = (&core.Genesis{Config: &config}).MustCommit(db)
+		)
+		chain, err := core.NewBlockChain(db, nil, &config, NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("fork %v: failed to create chain: %v", fork, err)
+		}
+		header := &types.Header{
+			ParentHash:    genesis.Hash(),
+			Number:        big.NewInt(1),
+			Coinbase:      common.Address{0x01},
+			Difficulty:    big.NewInt(1),
+			NUCDifficulty: big.NewInt(1),
+			GasLimit:      genesis.GasLimit(),
+			Time:          genesis.Time() + 10,
+		}
+		statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+		snap := GetRuleSnapshot(header, statedb, chain)
+		calls := snap.poolCalls + snap.pocCalls + snap.powCalls + 2*snap.ratioCalls
+
+		// Credit the rewards alone, looking the participants up on a throwaway state
+		credited, discarded := statedb.Copy(), statedb.Copy()
+		accumulateNUCRewards(chain, credited, types.CopyHeader(header), nil, nil, func(header *types.Header, _ *state.StateDB, c consensus.ChainReader) *CoinbaseTxs {
+			return NUCReward4(header, discarded, c)
+		})
+		want := credited.IntermediateRoot(true)
+
+		// Legacy lookups must leave the state untouched as well
+		looked := statedb.Copy()
+		root := looked.IntermediateRoot(true)
+		GetCoinbase(header, chain, looked)
+		if have := looked.IntermediateRoot(true); (have == root) != (fork != nil) {
+			t.Errorf("fork %v: coinbase lookup side effects mismatch: root %x, pre-state root %x", fork, have, root)
+		}
+		finalized := statedb.Copy()
+		NewFaker().Finalize(chain, types.CopyHeader(header), finalized, nil, nil)
+		have := finalized.IntermediateRoot(true)
+
+		if fork != nil {
+			if have != want {
+				t.Errorf("fork %v: finalized root mismatch: have %x, want %x", fork, have, want)
+			}
+			if nonce := finalized.GetNonce(params.NUCRuleContractAddress); nonce != 0 {
+				t.Errorf("fork %v: rule contract nonce mismatch: have %d, want %d", fork, nonce, 0)
+			}
+		} else {
+			if have == want {
+				t.Errorf("fork %v: finalized root unaffected by the legacy lookups", fork)
+			}
+			if nonce := finalized.GetNonce(params.NUCRuleContractAddress); nonce != calls {
+				t.Errorf("fork %v: rule contract nonce mismatch: have %d, want %d", fork, nonce, calls)
+			}
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that every rule contract ABI version that can be scheduled has a reader.
+func TestRuleReadersComplete(t *testing.T) {
+	for version := uint64(params.NUCRuleV1); version <= params.NUCRuleLatest; version++ {
+		if _, ok := ruleReaders[version]; !ok {
+			t.Errorf("missing rule reader for version %d", version)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxRuleArrayLength is the maximum length of a dynamic array read from the rule
+// contract storage. Lengths are stored in a full word, so a corrupt one could
+// otherwise have the node allocate or read without bounds.
+const maxRuleArrayLength = 1 << 20
+
+// Member offsets of the participant structs, in storage slots, following the
+// member order of the structs in the contract ABI. The Solidity compiler only
+// packs consecutive members fitting together in a slot, but none do here: every
+// address is followed by a full word or a dynamic array, which always start a
+// new one.
+const (
+	pocerRecords        = 0 // Record[]
+	pocerUserAddr       = 1
+	pocerGetReward      = 2
+	pocerIndex          = 3
+	pocerMortageBalance = 4
+	pocerBindPoolAddr   = 5
+
+	powerCreateTime   = 0
+	powerIndex        = 1
+	powerBuyBalance   = 2
+	powerBindPoolAddr = 3
+	powerPocAddrs     = 4 // address[]
+	powerUserAddr     = 5
+	powerRecords      = 6 // Record[]
+
+	poolerCreateTime = 0
+	poolerIndex      = 1
+	poolerBuyBalance = 2
+	poolerPowAddrs   = 3 // address[]
+	poolerPocAddrs   = 4 // address[]
+	poolerUserAddr   = 5
+
+	recordSize = 1 // Slots taken by a Record{createTime}
+)
+
+// ReadRuleParticipants decodes the participant sets of the rule contract straight
+// from its storage, without executing any code. Unlike the list calls it can't
+// run out of gas, so the result only depends on the state. An error is returned
+// if an array is longer than maxRuleArrayLength.
+func ReadRuleParticipants(state vm.StateDB, layout *params.NUCRuleStorageLayout) (*RuleParticipants, error) {
+	r := &ruleStorageReader{state: state, addr: params.NUCRuleContractAddress}
+
+	pocAddrs := r.addresses(slotOf(layout.PocAddrs))
+	pocers := make([]RulePocer, len(pocAddrs))
+	for i, addr := range pocAddrs {
+		pocers[i] = r.pocer(mappingSlot(slotOf(layout.Pocers), addr))
+	}
+	powAddrs := r.addresses(slotOf(layout.PowAddrs))
+	powers := make([]RulePower, len(powAddrs))
+	for i, addr := range powAddrs {
+		powers[i] = r.power(mappingSlot(slotOf(layout.Powers), addr))
+	}
+	poolAddrs := r.addresses(slotOf(layout.PoolAddrs))
+	poolers := make([]RulePooler, len(poolAddrs))
+	for i, addr := range poolAddrs {
+		poolers[i] = r.pooler(mappingSlot(slotOf(layout.Poolers), addr))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &RuleParticipants{Pocers: pocers, Powers: powers, Poolers: poolers}, nil
+}
+
+// ruleStorageReader decodes Solidity values from the storage of a contract. The
+// first error met is kept, after which arrays read as empty.
+type ruleStorageReader struct {
+	state vm.StateDB
+	addr  common.Address
+	err   error
+}
+
+// length decodes the length of a dynamic array stored in a slot.
+func (r *ruleStorageReader) length(slot *big.Int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	n := r.uint(slot)
+	if !n.IsUint64() || n.Uint64() > maxRuleArrayLength {
+		r.err = fmt.Errorf("rule contract array at slot %#x too long: %v elements", slot, n)
+		return 0
+	}
+	return n.Uint64()
+}
+
+// word returns the raw value of a storage slot.
+func (r *ruleStorageReader) word(slot *big.Int) common.Hash {
+	return r.state.GetState(r.addr, common.BigToHash(slot))
+}
+
+// uint decodes a uint256 stored in a slot.
+func (r *ruleStorageReader) uint(slot *big.Int) *big.Int {
+	return r.word(slot).Big()
+}
+
+// address decodes an address stored in the low order bytes of a slot.
+func (r *ruleStorageReader) address(slot *big.Int) common.Address {
+	return common.BytesToAddress(r.word(slot).Bytes())
+}
+
+// addresses decodes a dynamic address array, whose length is stored in its slot
+// and whose elements are stored one per slot from the slot's hash onwards.
+func (r *ruleStorageReader) addresses(slot *big.Int) []common.Address {
+	n := r.length(slot)
+	addrs := make([]common.Address, n)
+	for i := uint64(0); i < n; i++ {
+		addrs[i] = r.address(arraySlot(slot, i, 1))
+	}
+	return addrs
+}
+
+// records decodes a dynamic array of Record structs.
+func (r *ruleStorageReader) records(slot *big.Int) []RuleRecord {
+	n := r.length(slot)
+	records := make([]RuleRecord, n)
+	for i := uint64(0); i < n; i++ {
+		records[i] = RuleRecord{CreateTime: r.uint(arraySlot(slot, i, recordSize))}
+	}
+	return records
+}
+
+// pocer decodes a Pocer struct starting at the given slot.
+func (r *ruleStorageReader) pocer(base *big.Int) RulePocer {
+	return RulePocer{
+		Records:        r.records(memberSlot(base, pocerRecords)),
+		UserAddr:       r.address(memberSlot(base, pocerUserAddr)),
+		GetReward:      r.uint(memberSlot(base, pocerGetReward)),
+		Index:          r.uint(memberSlot(base, pocerIndex)),
+		MortageBalance: r.uint(memberSlot(base, pocerMortageBalance)),
+		BindPoolAddr:   r.address(memberSlot(base, pocerBindPoolAddr)),
+	}
+}
+
+// power decodes a Power struct starting at the given slot.
+func (r *ruleStorageReader) power(base *big.Int) RulePower {
+	return RulePower{
+		CreateTime:   r.uint(memberSlot(base, powerCreateTime)),
+		Index:        r.uint(memberSlot(base, powerIndex)),
+		BuyBalance:   r.uint(memberSlot(base, powerBuyBalance)),
+		BindPoolAddr: r.address(memberSlot(base, powerBindPoolAddr)),
+		PocAddrs:     r.addresses(memberSlot(base, powerPocAddrs)),
+		UserAddr:     r.address(memberSlot(base, powerUserAddr)),
+		Records:      r.records(memberSlot(base, powerRecords)),
+	}
+}
+
+// pooler decodes a Pooler struct starting at the given slot.
+func (r *ruleStorageReader) pooler(base *big.Int) RulePooler {
+	return RulePooler{
+		CreateTime: r.uint(memberSlot(base, poolerCreateTime)),
+		Index:      r.uint(memberSlot(base, poolerIndex)),
+		BuyBalance: r.uint(memberSlot(base, poolerBuyBalance)),
+		PowAddrs:   r.addresses(memberSlot(base, poolerPowAddrs)),
+		PocAddrs:   r.addresses(memberSlot(base, poolerPocAddrs)),
+		UserAddr:   r.address(memberSlot(base, poolerUserAddr)),
+	}
+}
+
+// slotOf converts the slot of a state variable into a storage key.
+func slotOf(slot uint64) *big.Int {
+	return new(big.Int).SetUint64(slot)
+}
+
+// memberSlot returns the slot of a struct member at the given offset.
+func memberSlot(base *big.Int, offset uint64) *big.Int {
+	return math.U256(new(big.Int).Add(base, new(big.Int).SetUint64(offset)))
+}
+
+// arraySlot returns the slot of the index'th element of a dynamic array stored
+// at the given slot, each element taking size slots.
+func arraySlot(slot *big.Int, index uint64, size uint64) *big.Int {
+	start := crypto.Keccak256Hash(common.BigToHash(slot).Bytes()).Big()
+	offset := new(big.Int).Mul(new(big.Int).SetUint64(index), new(big.Int).SetUint64(size))
+	return math.U256(start.Add(start, offset))
+}
+
+// mappingSlot returns the slot of the value an address maps to in a mapping
+// stored at the given slot.
+func mappingSlot(slot *big.Int, key common.Address) *big.Int {
+	return crypto.Keccak256Hash(common.LeftPadBytes(key.Bytes(), 32), common.BigToHash(slot).Bytes()).Big()
+}
@@ -0,0 +1,439 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	v1 "github.com/ethereum/go-ethereum/consensus/ethash/nuc_token/v1"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// testRuleStorageLayout places the participant state variables of the test
+// contract, the real slots being part of the chain config of a network.
+var testRuleStorageLayout = &params.NUCRuleStorageLayout{
+	Block:     common.Big0,
+	PocAddrs:  3,
+	Pocers:    4,
+	PowAddrs:  5,
+	Powers:    6,
+	PoolAddrs: 7,
+	Poolers:   8,
+}
+
+// ruleStorageWriterCode stores values the way compiled Solidity code does, the
+// slots being derived in the EVM. The call data holds five words: flags, slot,
+// key, member and value. With flag 0x1, the slot is the one of the member of
+// the struct the key maps to in the mapping at the given slot, keccak256(key .
+// slot) + member. With flag 0x2, the value is pushed onto the dynamic array at
+// the slot: its length is stored there and its elements from keccak256(slot)
+// on. Otherwise the value is stored in the slot.
+const ruleStorageWriterCode = `
+	PUSH 32
+	CALLDATALOAD
+	PUSH 1
+	PUSH 0
+	CALLDATALOAD
+	AND
+	ISZERO
+	JUMPI @slot
+
+	PUSH 64
+	CALLDATALOAD
+	PUSH 0
+	MSTORE
+	PUSH 32
+	MSTORE
+	PUSH 64
+	PUSH 0
+	SHA3
+	PUSH 96
+	CALLDATALOAD
+	ADD
+slot:
+	PUSH 2
+	PUSH 0
+	CALLDATALOAD
+	AND
+	JUMPI @push
+
+	PUSH 128
+	CALLDATALOAD
+	SWAP1
+	SSTORE
+	STOP
+push:
+	DUP1
+	SLOAD
+	DUP1
+	PUSH 1
+	ADD
+	DUP3
+	SSTORE
+	SWAP1
+	PUSH 0
+	MSTORE
+	PUSH 32
+	PUSH 0
+	SHA3
+	ADD
+	PUSH 128
+	CALLDATALOAD
+	SWAP1
+	SSTORE
+	STOP
+`
+
+// ruleStorageWriter fills the storage of the rule contract by running
+// ruleStorageWriterCode in the EVM.
+type ruleStorageWriter struct {
+	t   *testing.T
+	cfg *runtime.Config
+}
+
+// newRuleStorageWriter deploys ruleStorageWriterCode as the rule contract.
+func newRuleStorageWriter(t *testing.T, statedb *state.StateDB) *ruleStorageWriter {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(ruleStorageWriterCode), false))
+	code, errs := compiler.Compile()
+	if len(errs) != 0 {
+		t.Fatalf("failed to compile storage writer: %v", errs)
+	}
+	statedb.SetCode(params.NUCRuleContractAddress, common.FromHex(code))
+	return &ruleStorageWriter{t: t, cfg: &runtime.Config{State: statedb}}
+}
+
+func (w *ruleStorageWriter) call(flags uint64, slot uint64, key common.Address, member uint64, value common.Hash) {
+	input := append(common.BigToHash(new(big.Int).SetUint64(flags)).Bytes(), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes()...)
+	input = append(input, key.Hash().Bytes()...)
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(member)).Bytes()...)
+	input = append(input, value.Bytes()...)
+	if _, _, err := runtime.Call(params.NUCRuleContractAddress, input, w.cfg); err != nil {
+		w.t.Fatalf("storage writer failed: %v", err)
+	}
+}
+
+// set stores a member of the struct a key maps to.
+func (w *ruleStorageWriter) set(mapping uint64, key common.Address, member uint64, value common.Hash) {
+	w.call(0x1, mapping, key, member, value)
+}
+
+// push appends a value to a dynamic array state variable.
+func (w *ruleStorageWriter) push(slot uint64, value common.Hash) {
+	w.call(0x2, slot, common.Address{}, 0, value)
+}
+
+// pushMember appends a value to a dynamic array member of the struct a key
+// maps to.
+func (w *ruleStorageWriter) pushMember(mapping uint64, key common.Address, member uint64, value common.Hash) {
+	w.call(0x3, mapping, key, member, value)
+}
+
+// newRuleFixture generates random participant sets.
+func newRuleFixture(rnd *rand.Rand, pocs, pows, pools int) *RuleParticipants {
+	var (
+		addr = func() common.Address {
+			var a common.Address
+			rnd.Read(a[:])
+			return a
+		}
+		addrs = func() []common.Address {
+			list := make([]common.Address, rnd.Intn(4))
+			for i := range list {
+				list[i] = addr()
+			}
+			return list
+		}
+		records = func() []RuleRecord {
+			list := make([]RuleRecord, rnd.Intn(4))
+			for i := range list {
+				list[i] = RuleRecord{CreateTime: big.NewInt(rnd.Int63())}
+			}
+			return list
+		}
+		amount = func() *big.Int {
+			return new(big.Int).Mul(big.NewInt(rnd.Int63()), big.NewInt(rnd.Int63()))
+		}
+		bound = func() common.Address {
+			if rnd.Intn(2) == 0 {
+				return common.Address{}
+			}
+			return addr()
+		}
+	)
+	p := new(RuleParticipants)
+	for i := 0; i < pocs; i++ {
+		p.Pocers = append(p.Pocers, RulePocer{Records: records(), UserAddr: addr(), GetReward: amount(), Index: big.NewInt(int64(i)), MortageBalance: amount(), BindPoolAddr: bound()})
+	}
+	for i := 0; i < pows; i++ {
+		p.Powers = append(p.Powers, RulePower{CreateTime: big.NewInt(rnd.Int63()), Index: big.NewInt(int64(i)), BuyBalance: amount(), BindPoolAddr: bound(), PocAddrs: addrs(), UserAddr: addr(), Records: records()})
+	}
+	for i := 0; i < pools; i++ {
+		p.Poolers = append(p.Poolers, RulePooler{CreateTime: big.NewInt(rnd.Int63()), Index: big.NewInt(int64(i)), BuyBalance: amount(), PowAddrs: addrs(), PocAddrs: addrs(), UserAddr: addr()})
+	}
+	return p
+}
+
+// writeRuleStorage registers the participant sets in the rule contract the way
+// its code does, appending them to the address arrays and filling in the
+// structs they map to. The member numbers follow the declaration order of the
+// Solidity structs:
+//
+//	struct Record { uint createTime; }
+//	struct Pocer  { Record[] records; address userAddr; uint getReward; uint Index; uint mortageBalance; address bindPoolAddr; }
+//	struct Power  { uint createTime; uint Index; uint buyBalance; address bindPoolAddr; address[] pocAddrs; address userAddr; Record[] records; }
+//	struct Pooler { uint createTime; uint Index; uint buyBalance; address[] powAddrs; address[] pocAddrs; address userAddr; }
+func writeRuleStorage(w *ruleStorageWriter, layout *params.NUCRuleStorageLayout, p *RuleParticipants) {
+	for _, u := range p.Pocers {
+		w.push(layout.PocAddrs, u.UserAddr.Hash())
+		for _, record := range u.Records {
+			w.pushMember(layout.Pocers, u.UserAddr, 0, common.BigToHash(record.CreateTime))
+		}
+		w.set(layout.Pocers, u.UserAddr, 1, u.UserAddr.Hash())
+		w.set(layout.Pocers, u.UserAddr, 2, common.BigToHash(u.GetReward))
+		w.set(layout.Pocers, u.UserAddr, 3, common.BigToHash(u.Index))
+		w.set(layout.Pocers, u.UserAddr, 4, common.BigToHash(u.MortageBalance))
+		w.set(layout.Pocers, u.UserAddr, 5, u.BindPoolAddr.Hash())
+	}
+	for _, u := range p.Powers {
+		w.push(layout.PowAddrs, u.UserAddr.Hash())
+		w.set(layout.Powers, u.UserAddr, 0, common.BigToHash(u.CreateTime))
+		w.set(layout.Powers, u.UserAddr, 1, common.BigToHash(u.Index))
+		w.set(layout.Powers, u.UserAddr, 2, common.BigToHash(u.BuyBalance))
+		w.set(layout.Powers, u.UserAddr, 3, u.BindPoolAddr.Hash())
+		for _, addr := range u.PocAddrs {
+			w.pushMember(layout.Powers, u.UserAddr, 4, addr.Hash())
+		}
+		w.set(layout.Powers, u.UserAddr, 5, u.UserAddr.Hash())
+		for _, record := range u.Records {
+			w.pushMember(layout.Powers, u.UserAddr, 6, common.BigToHash(record.CreateTime))
+		}
+	}
+	for _, u := range p.Poolers {
+		w.push(layout.PoolAddrs, u.UserAddr.Hash())
+		w.set(layout.Poolers, u.UserAddr, 0, common.BigToHash(u.CreateTime))
+		w.set(layout.Poolers, u.UserAddr, 1, common.BigToHash(u.Index))
+		w.set(layout.Poolers, u.UserAddr, 2, common.BigToHash(u.BuyBalance))
+		for _, addr := range u.PowAddrs {
+			w.pushMember(layout.Poolers, u.UserAddr, 3, addr.Hash())
+		}
+		for _, addr := range u.PocAddrs {
+			w.pushMember(layout.Poolers, u.UserAddr, 4, addr.Hash())
+		}
+		w.set(layout.Poolers, u.UserAddr, 5, u.UserAddr.Hash())
+	}
+}
+
+// ruleFixtureChain serves the rule contract's ABI from generated participant
+// sets, paging the lists the way the contract does. Calls of the failing method
+// are rejected, as if they ran out of gas.
+type ruleFixtureChain struct {
+	consensus.ChainReader
+
+	abi     abi.ABI
+	p       *RuleParticipants
+	failing string
+}
+
+// CallRuleContract implements RuleContractBackend.
+func (c *ruleFixtureChain) CallRuleContract(header *types.Header, input []byte) ([]byte, error) {
+	method, err := c.abi.MethodById(input)
+	if err != nil {
+		return nil, err
+	}
+	if method.Name == c.failing {
+		return nil, errors.New("out of gas")
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	page := func(n int) (int, int) {
+		start, end := int(args[0].(*big.Int).Int64()), int(args[0].(*big.Int).Int64()+args[1].(*big.Int).Int64())
+		if start > n {
+			start = n
+		}
+		if end > n {
+			end = n
+		}
+		return start, end
+	}
+	switch method.Name {
+	case "PocCount":
+		return method.Outputs.Pack(big.NewInt(int64(len(c.p.Pocers))))
+	case "PowCount":
+		return method.Outputs.Pack(big.NewInt(int64(len(c.p.Powers))))
+	case "PoolCount":
+		return method.Outputs.Pack(big.NewInt(int64(len(c.p.Poolers))))
+	case "AllPocers":
+		start, end := page(len(c.p.Pocers))
+		return method.Outputs.Pack(c.p.Pocers[start:end])
+	case "AllPowers":
+		start, end := page(len(c.p.Powers))
+		return method.Outputs.Pack(c.p.Powers[start:end])
+	case "AllPoolers":
+		start, end := page(len(c.p.Poolers))
+		return method.Outputs.Pack(c.p.Poolers[start:end])
+	case "GetRewardRatio":
+		return method.Outputs.Pack(big.NewInt(1))
+	}
+	return nil, errors.New("unexpected method " + method.Name)
+}
+
+// Tests that decoding the participants from the storage filled in by the EVM
+// yields the generated fixtures, as do the ABI calls.
+func TestRuleStorageMatchesCalls(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(v1.NUCABI))
+	if err != nil {
+		t.Fatalf("failed to parse rule contract ABI: %v", err)
+	}
+	tests := []struct {
+		pocs, pows, pools int
+	}{
+		{0, 0, 0},
+		{1, 1, 1},
+		{17, 5, 3},
+		{rulePageSize + 1, 2*rulePageSize + 7, rulePageSize},
+	}
+	for i, tt := range tests {
+		fixture := newRuleFixture(rand.New(rand.NewSource(int64(i))), tt.pocs, tt.pows, tt.pools)
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		writeRuleStorage(newRuleStorageWriter(t, statedb), testRuleStorageLayout, fixture)
+
+		stored, err := ReadRuleParticipants(statedb, testRuleStorageLayout)
+		if err != nil {
+			t.Fatalf("test %d: failed to read participants: %v", i, err)
+		}
+		chain := &ruleFixtureChain{abi: parsed, p: fixture}
+		called := readRuleSnapshot(common.Hash{}, newV1RuleReader(newRuleContractCaller(new(types.Header), statedb, chain))).RuleParticipants
+
+		want, _ := rlp.EncodeToBytes(fixture)
+		if have, _ := rlp.EncodeToBytes(stored); !bytes.Equal(have, want) {
+			t.Errorf("test %d: stored participants mismatch", i)
+		}
+		if have, _ := rlp.EncodeToBytes(&called); !bytes.Equal(have, want) {
+			t.Errorf("test %d: called participants mismatch", i)
+		}
+	}
+}
+
+// Tests that failing list calls silently drop participants, while reading the
+// storage doesn't depend on gas at all.
+func TestRuleStorageFailingCalls(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(v1.NUCABI))
+	if err != nil {
+		t.Fatalf("failed to parse rule contract ABI: %v", err)
+	}
+	fixture := newRuleFixture(rand.New(rand.NewSource(1)), 10, 10, 10)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	writeRuleStorage(newRuleStorageWriter(t, statedb), testRuleStorageLayout, fixture)
+
+	chain := &ruleFixtureChain{abi: parsed, p: fixture, failing: "AllPowers"}
+	called := readRuleSnapshot(common.Hash{}, newV1RuleReader(newRuleContractCaller(new(types.Header), statedb, chain)))
+	if len(called.Powers) != 0 {
+		t.Fatalf("failing calls returned %d powers", len(called.Powers))
+	}
+	stored, err := ReadRuleParticipants(statedb, testRuleStorageLayout)
+	if err != nil {
+		t.Fatalf("failed to read participants: %v", err)
+	}
+	if len(stored.Powers) != len(fixture.Powers) {
+		t.Fatalf("stored powers mismatch: have %d, want %d", len(stored.Powers), len(fixture.Powers))
+	}
+}
+
+// Tests that arrays longer than maxRuleArrayLength are rejected instead of being
+// allocated, be it a participant list or a participant's member.
+func TestRuleStorageArrayLength(t *testing.T) {
+	fixture := newRuleFixture(rand.New(rand.NewSource(1)), 1, 1, 1)
+	tests := []struct {
+		slot   *big.Int
+		length *big.Int
+		fail   bool
+	}{
+		{slotOf(testRuleStorageLayout.PocAddrs), big.NewInt(1), false},
+		{slotOf(testRuleStorageLayout.PocAddrs), big.NewInt(maxRuleArrayLength + 1), true},
+		{slotOf(testRuleStorageLayout.PowAddrs), new(big.Int).Lsh(common.Big1, 255), true},
+		{memberSlot(mappingSlot(slotOf(testRuleStorageLayout.Pocers), fixture.Pocers[0].UserAddr), pocerRecords), big.NewInt(maxRuleArrayLength + 1), true},
+		{memberSlot(mappingSlot(slotOf(testRuleStorageLayout.Poolers), fixture.Poolers[0].UserAddr), poolerPowAddrs), new(big.Int).Lsh(common.Big1, 64), true},
+	}
+	for i, tt := range tests {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		writeRuleStorage(newRuleStorageWriter(t, statedb), testRuleStorageLayout, fixture)
+		statedb.SetState(params.NUCRuleContractAddress, common.BigToHash(tt.slot), common.BigToHash(tt.length))
+
+		if _, err := ReadRuleParticipants(statedb, testRuleStorageLayout); (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+		}
+	}
+}
+
+// Tests that from the first rule storage layout on, the snapshot participants
+// are decoded from the contract storage, the list getters not being called.
+func TestRuleStorageSnapshot(t *testing.T) {
+	for _, fork := range []*big.Int{common.Big1, common.Big2} {
+		config := *params.TestChainConfig
+		layout := *testRuleStorageLayout
+		layout.Block = fork
+		config.NUC = &params.NUCConfig{RuleStorageLayouts: []params.NUCRuleStorageLayout{layout}}
+
+		var (
+			db      = rawdb.NewMemoryDatabase()
+			genesis = (&core.Genesis{Config: &config}).MustCommit(db)
+		)
+		chain, err := core.NewBlockChain(db, nil, &config, NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("fork %v: failed to create chain: %v", fork, err)
+		}
+		header := &types.Header{
+			ParentHash:    genesis.Hash(),
+			Number:        big.NewInt(1),
+			Coinbase:      common.Address{0x01},
+			Difficulty:    big.NewInt(1),
+			NUCDifficulty: big.NewInt(1),
+			GasLimit:      genesis.GasLimit(),
+			Time:          genesis.Time() + 10,
+		}
+		fixture := newRuleFixture(rand.New(rand.NewSource(1)), 3, 2, 1)
+
+		// Register the participants, then break the contract so every call fails
+		statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+		writeRuleStorage(newRuleStorageWriter(t, statedb), testRuleStorageLayout, fixture)
+		statedb.SetCode(params.NUCRuleContractAddress, []byte{byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.REVERT)})
+
+		ruleSnapshots.Purge() // Same state as the previous fork, drop its snapshot
+		snap := GetRuleSnapshot(header, statedb, chain)
+		if fork.Cmp(header.Number) > 0 {
+			if len(snap.Pocers)+len(snap.Powers)+len(snap.Poolers) != 0 {
+				t.Errorf("fork %v: participants returned by failing calls", fork)
+			}
+		} else {
+			want, _ := rlp.EncodeToBytes(fixture)
+			if have, _ := rlp.EncodeToBytes(&snap.RuleParticipants); !bytes.Equal(have, want) {
+				t.Errorf("fork %v: stored participants mismatch", fork)
+			}
+			if calls := snap.poolCalls + snap.pocCalls + snap.powCalls; calls != 0 {
+				t.Errorf("fork %v: list calls mismatch: have %d, want %d", fork, calls, 0)
+			}
+		}
+		chain.Stop()
+	}
+}
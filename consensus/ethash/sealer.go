@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 const (
@@ -86,7 +87,11 @@ func (ethash *Ethash) Seal(chain consensus.ChainReader, block *types.Block, resu
 	}
 	// Push new work to remote sealer
 	if ethash.workCh != nil {
-		ethash.workCh <- &sealTask{block: block, results: results}
+		task := &sealTask{block: block, results: results}
+		if chain != nil {
+			task.config = chain.Config()
+		}
+		ethash.workCh <- task
 	}
 	var (
 		pend   sync.WaitGroup
@@ -192,9 +197,10 @@ func (ethash *Ethash) remote(notify []string, noverify bool) {
 		works = make(map[common.Hash]*types.Block)
 		rates = make(map[common.Hash]hashrate)
 
-		results      chan<- *types.Block
-		currentBlock *types.Block
-		currentWork  [4]string
+		results        chan<- *types.Block
+		currentBlock   *types.Block
+		currentWork    [4]string
+		currentNUCWork *NUCWork
 
 		notifyTransport = &http.Transport{}
 		notifyClient    = &http.Client{
@@ -206,8 +212,15 @@ func (ethash *Ethash) remote(notify []string, noverify bool) {
 	// notifyWork notifies all the specified mining endpoints of the availability of
 	// new work to be processed.
 	notifyWork := func() {
-		work := currentWork
-		blob, _ := json.Marshal(work)
+		var (
+			work = currentWork
+			blob []byte
+		)
+		if ethash.config.NotifyNUC {
+			blob, _ = json.Marshal(currentNUCWork)
+		} else {
+			blob, _ = json.Marshal(work)
+		}
 
 		for i, url := range notify {
 			// Terminate any previously pending request and create the new work
@@ -236,17 +249,23 @@ func (ethash *Ethash) remote(notify []string, noverify bool) {
 	//   result[1], 32 bytes hex encoded seed hash used for DAG
 	//   result[2], 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 	//   result[3], hex encoded block number
-	makeWork := func(block *types.Block) {
+	makeWork := func(block *types.Block, config *params.ChainConfig) {
 		hash := ethash.SealHash(block.Header())
 
 		currentWork[0] = hash.Hex()
 		currentWork[1] = common.BytesToHash(SeedHash(block.NumberU64())).Hex()
 		currentWork[2] = common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex()
 		currentWork[3] = hexutil.EncodeBig(block.Number())
+		currentNUCWork = newNUCWork(hash, block, config)
 
 		// Trace the seal work fetched by remote sealer.
 		currentBlock = block
 		works[hash] = block
+
+		// Announce the new work to the stratum workers too.
+		if ethash.stratum != nil {
+			ethash.stratum.newJob(hash, block)
+		}
 	}
 	// submitWork verifies the submitted pow solution, returning
 	// whether the solution was accepted or not (not can be both a bad pow as well as
@@ -310,16 +329,19 @@ func (ethash *Ethash) remote(notify []string, noverify bool) {
 			// Note same work can be past twice, happens when changing CPU threads.
 			results = work.results
 
-			makeWork(work.block)
+			makeWork(work.block, work.config)
 
 			// Notify and requested URLs of the new work availability
 			notifyWork()
 
 		case work := <-ethash.fetchWorkCh:
 			// Return current mining work to remote miner.
-			if currentBlock == nil {
+			switch {
+			case currentBlock == nil:
 				work.errc <- errNoMiningWork
-			} else {
+			case work.nuc != nil:
+				work.nuc <- currentNUCWork
+			default:
 				work.res <- currentWork
 			}
 
@@ -363,6 +385,9 @@ func (ethash *Ethash) remote(notify []string, noverify bool) {
 
 		case errc := <-ethash.exitCh:
 			// Exit remote loop if ethash is closed and return relevant error.
+			if ethash.stratum != nil {
+				ethash.stratum.close()
+			}
 			errc <- nil
 			log.Trace("Ethash remote sealer is exiting")
 			return
@@ -0,0 +1,87 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// NUCWork is a work package for external miners carrying the NUC fields of the
+// block being sealed besides the classic ones. Seals are verified against the
+// NUC difficulty, so Target is the boundary solutions actually have to meet.
+type NUCWork struct {
+	SealHash      common.Hash      `json:"sealHash"`      // Header hash without the seal, to submit solutions for
+	SeedHash      common.Hash      `json:"seedHash"`      // Seed hash of the DAG
+	Target        common.Hash      `json:"target"`        // Boundary condition, 2^256 / NUC difficulty
+	Number        hexutil.Uint64   `json:"number"`        // Number of the block being sealed
+	Difficulty    *hexutil.Big     `json:"difficulty"`    // Chain difficulty of the block
+	NUCDifficulty *hexutil.Big     `json:"nucDifficulty"` // Discounted difficulty the seal is verified against
+	Version       hexutil.Uint64   `json:"version"`       // Header version, part of the seal hash
+	Coinbase      common.Address   `json:"coinbase"`      // Beneficiary of the block
+	CoinbaseTxs   hexutil.Bytes    `json:"coinbaseTxs"`   // Encoded rewards the block commits to
+	Rewards       []*NUCWorkReward `json:"rewards"`       // Decoded CoinbaseTxs, nil if undecodable
+}
+
+// NUCWorkReward is the reward the block being sealed credits to an address.
+type NUCWorkReward struct {
+	Address common.Address `json:"address"`
+	Poc     *hexutil.Big   `json:"poc"`
+	Pow     *hexutil.Big   `json:"pow"`
+	Pool    *hexutil.Big   `json:"pool"`
+	Post    *hexutil.Big   `json:"post"`
+}
+
+// nucSealTarget returns the boundary a seal of the header has to meet. Headers
+// without a NUC difficulty are sealed against their chain difficulty.
+func nucSealTarget(header *types.Header) *big.Int {
+	difficulty := header.NUCDifficulty
+	if difficulty == nil || difficulty.Sign() <= 0 {
+		difficulty = header.Difficulty
+	}
+	return new(big.Int).Div(two256, difficulty)
+}
+
+// newNUCWork creates the NUC work package of a block to seal. The rewards are
+// decoded with the encoding the given chain config schedules, and left out if
+// there's no config.
+func newNUCWork(sealhash common.Hash, block *types.Block, config *params.ChainConfig) *NUCWork {
+	header := block.Header()
+	work := &NUCWork{
+		SealHash:    sealhash,
+		SeedHash:    common.BytesToHash(SeedHash(header.Number.Uint64())),
+		Target:      common.BytesToHash(nucSealTarget(header).Bytes()),
+		Number:      hexutil.Uint64(header.Number.Uint64()),
+		Difficulty:  (*hexutil.Big)(header.Difficulty),
+		Version:     hexutil.Uint64(header.Version),
+		Coinbase:    header.Coinbase,
+		CoinbaseTxs: header.CoinbaseTxs,
+	}
+	if header.NUCDifficulty != nil {
+		work.NUCDifficulty = (*hexutil.Big)(header.NUCDifficulty)
+	}
+	if config == nil {
+		return work
+	}
+	entries, err := types.DecodeBlockCoinbaseTxs(config, header.Number, header.CoinbaseTxs)
+	if err != nil {
+		log.Warn("Failed to decode pending rewards", "number", header.Number, "sealhash", sealhash, "err", err)
+		return work
+	}
+	work.Rewards = make([]*NUCWorkReward, 0, len(entries))
+	for _, entry := range entries {
+		work.Rewards = append(work.Rewards, &NUCWorkReward{
+			Address: entry.Address,
+			Poc:     (*hexutil.Big)(entry.PocReward),
+			Pow:     (*hexutil.Big)(entry.PowReward),
+			Pool:    (*hexutil.Big)(entry.PoolReward),
+			Post:    (*hexutil.Big)(entry.PostReward),
+		})
+	}
+	return work
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Tests whether remote HTTP servers are correctly notified of new work.
@@ -225,3 +226,145 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// newNUCWorkTestHeader creates a header carrying NUC sealing fields and rewards,
+// hard enough for the local miner threads not to seal it during a test.
+func newNUCWorkTestHeader(t *testing.T) *types.Header {
+	txs, err := types.EncodeCoinbaseTxs([]types.CoinbaseTx{
+		{Address: common.Address{0x01}, PocReward: big.NewInt(1), PowReward: big.NewInt(2), PoolReward: big.NewInt(3), PostReward: big.NewInt(4)},
+		{Address: common.Address{0x02}, PocReward: big.NewInt(5), PowReward: big.NewInt(0), PoolReward: big.NewInt(0), PostReward: big.NewInt(0)},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode rewards: %v", err)
+	}
+	return &types.Header{
+		Version:       2,
+		Number:        big.NewInt(1),
+		Coinbase:      common.Address{0xcb},
+		Difficulty:    big.NewInt(100000000),
+		NUCDifficulty: big.NewInt(25000000),
+		CoinbaseTxs:   txs,
+	}
+}
+
+// newNUCWorkTestChain creates a chain recording the rewards of its blocks in the
+// RLP encoding, like newNUCWorkTestHeader does.
+func newNUCWorkTestChain() *nucTestChain {
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{CoinbaseTxsBlock: common.Big0}
+	return &nucTestChain{config: &config}
+}
+
+// checkNUCWork verifies a NUC work package against the header it was made of.
+func checkNUCWork(t *testing.T, ethash *Ethash, header *types.Header, work *NUCWork) {
+	t.Helper()
+
+	if want := ethash.SealHash(header); work.SealHash != want {
+		t.Errorf("work hash mismatch: have %x, want %x", work.SealHash, want)
+	}
+	if want := common.BytesToHash(SeedHash(header.Number.Uint64())); work.SeedHash != want {
+		t.Errorf("work seed mismatch: have %x, want %x", work.SeedHash, want)
+	}
+	target := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), header.NUCDifficulty)
+	if want := common.BytesToHash(target.Bytes()); work.Target != want {
+		t.Errorf("work target mismatch: have %x, want %x", work.Target, want)
+	}
+	if uint64(work.Number) != header.Number.Uint64() || uint64(work.Version) != uint64(header.Version) || work.Coinbase != header.Coinbase {
+		t.Errorf("work header mismatch: have #%d v%d %x, want #%d v%d %x", work.Number, work.Version, work.Coinbase, header.Number, header.Version, header.Coinbase)
+	}
+	if work.Difficulty.ToInt().Cmp(header.Difficulty) != 0 || work.NUCDifficulty.ToInt().Cmp(header.NUCDifficulty) != 0 {
+		t.Errorf("work difficulty mismatch: have %v/%v, want %v/%v", work.Difficulty, work.NUCDifficulty, header.Difficulty, header.NUCDifficulty)
+	}
+	entries, _ := types.DecodeCoinbaseTxs(header.CoinbaseTxs)
+	if len(work.Rewards) != len(entries) {
+		t.Fatalf("work reward count mismatch: have %d, want %d", len(work.Rewards), len(entries))
+	}
+	for i, entry := range entries {
+		reward := work.Rewards[i]
+		if reward.Address != entry.Address || reward.Poc.ToInt().Cmp(entry.PocReward) != 0 || reward.Pow.ToInt().Cmp(entry.PowReward) != 0 ||
+			reward.Pool.ToInt().Cmp(entry.PoolReward) != 0 || reward.Post.ToInt().Cmp(entry.PostReward) != 0 {
+			t.Errorf("work reward %d mismatch: have %x %v/%v/%v/%v, want %x %v/%v/%v/%v", i,
+				reward.Address, reward.Poc, reward.Pow, reward.Pool, reward.Post,
+				entry.Address, entry.PocReward, entry.PowReward, entry.PoolReward, entry.PostReward)
+		}
+	}
+}
+
+// Tests that remote HTTP servers are notified of NUC work packages if requested.
+func TestRemoteNotifyNUC(t *testing.T) {
+	sink := make(chan *NUCWork)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			blob, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read miner notification: %v", err)
+			}
+			work := new(NUCWork)
+			if err := json.Unmarshal(blob, work); err != nil {
+				t.Fatalf("failed to unmarshal miner notification: %v", err)
+			}
+			sink <- work
+		}),
+	}
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to open notification server: %v", err)
+	}
+	defer listener.Close()
+
+	go server.Serve(listener)
+
+	ethash := New(Config{PowMode: ModeTest, NotifyNUC: true}, []string{"http://" + listener.Addr().String()}, false)
+	defer ethash.Close()
+
+	header := newNUCWorkTestHeader(t)
+	ethash.Seal(newNUCWorkTestChain(), types.NewBlockWithHeader(header), nil, nil)
+	select {
+	case work := <-sink:
+		checkNUCWork(t, ethash, header, work)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+}
+
+// Tests that NUC work packages are served for the block being sealed, next to
+// the classic ones, and that solutions to them are accepted.
+func TestGetWorkNUC(t *testing.T) {
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	if _, err := api.GetWorkNUC(); err != errNoMiningWork {
+		t.Fatalf("work error mismatch: have %v, want %v", err, errNoMiningWork)
+	}
+	header := newNUCWorkTestHeader(t)
+	results := make(chan *types.Block, 1)
+	ethash.Seal(newNUCWorkTestChain(), types.NewBlockWithHeader(header), results, nil)
+
+	work, err := api.GetWorkNUC()
+	if err != nil {
+		t.Fatalf("failed to get NUC work: %v", err)
+	}
+	checkNUCWork(t, ethash, header, work)
+
+	classic, err := api.GetWork()
+	if err != nil {
+		t.Fatalf("failed to get classic work: %v", err)
+	}
+	if classic[0] != work.SealHash.Hex() {
+		t.Errorf("classic work hash mismatch: have %s, want %s", classic[0], work.SealHash.Hex())
+	}
+	nonce, digest := types.BlockNonce{0x01}, common.HexToHash("deadbeef")
+	if !api.SubmitWork(nonce, work.SealHash, digest) {
+		t.Fatalf("solution to NUC work rejected")
+	}
+	select {
+	case block := <-results:
+		if block.Nonce() != nonce.Uint64() || block.MixDigest() != digest {
+			t.Errorf("sealed block mismatch: have %x/%x, want %x/%x", block.Nonce(), block.MixDigest(), nonce, digest)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("sealed block timed out")
+	}
+}
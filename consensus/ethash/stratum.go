@@ -0,0 +1,565 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"bufio"
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// stratumProtocol is the stratum dialect served, as negotiated on subscription.
+	stratumProtocol = "EthereumStratum/1.0.0"
+
+	// stratumExtranonceSize is the number of leading nonce bytes assigned to each
+	// session, so that the workers never search the same nonces.
+	stratumExtranonceSize = 2
+
+	// stratumMaxRequestSize is the maximum size of a request line.
+	stratumMaxRequestSize = 4096
+
+	// stratumIdleTimeout is how long a worker may stay silent before its session
+	// is dropped.
+	stratumIdleTimeout = 10 * time.Minute
+
+	// stratumWriteTimeout is the time allowed to write a message to a worker.
+	stratumWriteTimeout = 10 * time.Second
+
+	// stratumSendQueue is the number of messages queued for a worker before the
+	// session is dropped as too slow.
+	stratumSendQueue = 64
+
+	// DefaultStratumDifficulty is the share difficulty used if none is configured.
+	DefaultStratumDifficulty = 1.0
+)
+
+var (
+	// two224 is the target of a share of difficulty 1, i.e. 2^32 hashes.
+	two224 = new(big.Int).Lsh(big.NewInt(1), 224)
+
+	errStratumNoExtranonce = errors.New("no extranonce available")
+)
+
+// Stratum error codes, as commonly used by pools.
+var (
+	errStratumOther        = &stratumError{20, "Other/Unknown"}
+	errStratumJobNotFound  = &stratumError{21, "Job not found"}
+	errStratumDuplicate    = &stratumError{22, "Duplicate share"}
+	errStratumLowShare     = &stratumError{23, "Low difficulty share"}
+	errStratumUnauthorized = &stratumError{24, "Unauthorized worker"}
+	errStratumUnsubscribed = &stratumError{25, "Not subscribed"}
+)
+
+// stratumError is an error reported to a stratum worker, encoded as the usual
+// [code, message, traceback] triplet.
+type stratumError struct {
+	code    int
+	message string
+}
+
+// Error implements error.
+func (e *stratumError) Error() string {
+	return fmt.Sprintf("stratum error %d: %s", e.code, e.message)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.code, e.message, nil})
+}
+
+// stratumRequest is a request sent by a stratum worker.
+type stratumRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// stratumResponse answers a stratum request.
+type stratumResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+// stratumNotification is a message pushed to a stratum worker.
+type stratumNotification struct {
+	ID     interface{}   `json:"id"` // Always null
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stratumTarget converts a share difficulty into the boundary the shares must
+// meet, a difficulty of 1 standing for 2^32 hashes.
+func stratumTarget(difficulty float64) *big.Int {
+	target, _ := new(big.Float).Quo(new(big.Float).SetInt(two224), big.NewFloat(difficulty)).Int(nil)
+	if target.Cmp(two256) >= 0 {
+		target.Sub(two256, common.Big1)
+	}
+	if target.Sign() <= 0 {
+		target.SetUint64(1)
+	}
+	return target
+}
+
+// stratumDifficulty converts a boundary into its share difficulty.
+func stratumDifficulty(target *big.Int) float64 {
+	difficulty, _ := new(big.Float).Quo(new(big.Float).SetInt(two224), new(big.Float).SetInt(target)).Float64()
+	return difficulty
+}
+
+// stratumJob is a work package announced to the stratum workers.
+type stratumJob struct {
+	id          string
+	sealhash    common.Hash
+	seedhash    common.Hash
+	number      uint64
+	target      *big.Int // Boundary of a block, 2^256 / NUC difficulty
+	shareTarget *big.Int // Boundary of a share, never harder than the block
+	difficulty  float64  // Share difficulty announced with the job
+
+	shares map[uint64]struct{} // Nonces submitted for the job
+}
+
+// stratumSession is the connection of a stratum worker.
+type stratumSession struct {
+	id         string
+	extranonce string // Hex encoded leading nonce bytes of the session
+	conn       net.Conn
+	send       chan interface{}
+	quit       chan struct{}
+	closeOnce  sync.Once
+
+	// Fields below are guarded by the server lock
+	subscribed bool
+	worker     string  // Name the worker authorized with, empty if unauthorized
+	difficulty float64 // Share difficulty last announced, 0 if none
+	accepted   uint64  // Shares accepted
+	rejected   uint64  // Shares rejected
+	blocks     uint64  // Shares submitted as block solutions
+}
+
+// push queues a message to the worker, dropping the session if it doesn't keep
+// up with its messages. The server lock must be held.
+func (sess *stratumSession) push(msg interface{}) {
+	select {
+	case sess.send <- msg:
+	case <-sess.quit:
+	default:
+		log.Warn("Stratum worker too slow, dropping", "session", sess.id, "worker", sess.worker)
+		sess.close()
+	}
+}
+
+// close terminates the session.
+func (sess *stratumSession) close() {
+	sess.closeOnce.Do(func() {
+		close(sess.quit)
+		sess.conn.Close()
+	})
+}
+
+// stratumServer serves the work of the remote sealer to EthereumStratum/1.0.0
+// workers over TCP. Every session is given its own extranonce, shares are
+// verified against the share difficulty and the ones meeting the NUC target of
+// their block are submitted as solutions, the same way eth_submitWork does.
+type stratumServer struct {
+	ethash     *Ethash
+	listener   net.Listener
+	difficulty float64 // Configured share difficulty
+
+	lock       sync.Mutex
+	sessions   map[*stratumSession]struct{}
+	extranonce map[uint16]struct{} // Extranonces in use
+	lastNonce  uint16              // Last extranonce handed out
+	jobs       map[string]*stratumJob
+	current    *stratumJob
+	lastJob    uint64 // Last job id handed out
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newStratumServer starts serving stratum workers on the given address.
+func newStratumServer(ethash *Ethash, addr string, difficulty float64) (*stratumServer, error) {
+	if difficulty <= 0 {
+		difficulty = DefaultStratumDifficulty
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &stratumServer{
+		ethash:     ethash,
+		listener:   listener,
+		difficulty: difficulty,
+		sessions:   make(map[*stratumSession]struct{}),
+		extranonce: make(map[uint16]struct{}),
+		jobs:       make(map[string]*stratumJob),
+		quit:       make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.loop()
+
+	log.Info("Stratum server started", "addr", listener.Addr(), "difficulty", difficulty)
+	return s, nil
+}
+
+// close stops the server, disconnecting all the workers.
+func (s *stratumServer) close() {
+	close(s.quit)
+	s.listener.Close()
+
+	s.lock.Lock()
+	for sess := range s.sessions {
+		sess.close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+}
+
+// loop accepts the worker connections.
+func (s *stratumServer) loop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			log.Warn("Failed to accept stratum connection", "err", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		sess, err := s.newSession(conn)
+		if err != nil {
+			log.Warn("Rejected stratum connection", "remote", conn.RemoteAddr(), "err", err)
+			conn.Close()
+			continue
+		}
+		s.wg.Add(2)
+		go s.writeLoop(sess)
+		go s.readLoop(sess)
+	}
+}
+
+// newSession registers a worker connection, assigning it an extranonce.
+func (s *stratumServer) newSession(conn net.Conn) (*stratumSession, error) {
+	id := make([]byte, 16)
+	if _, err := crand.Read(id); err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.extranonce) > 0xffff {
+		return nil, errStratumNoExtranonce
+	}
+	nonce := s.lastNonce + 1
+	for ; ; nonce++ {
+		if _, ok := s.extranonce[nonce]; !ok {
+			break
+		}
+	}
+	s.lastNonce = nonce
+	s.extranonce[nonce] = struct{}{}
+
+	prefix := make([]byte, stratumExtranonceSize)
+	binary.BigEndian.PutUint16(prefix, nonce)
+	sess := &stratumSession{
+		id:         hex.EncodeToString(id),
+		extranonce: hex.EncodeToString(prefix),
+		conn:       conn,
+		send:       make(chan interface{}, stratumSendQueue),
+		quit:       make(chan struct{}),
+	}
+	s.sessions[sess] = struct{}{}
+
+	log.Debug("Stratum worker connected", "session", sess.id, "remote", conn.RemoteAddr(), "extranonce", sess.extranonce)
+	return sess, nil
+}
+
+// dropSession unregisters a worker connection.
+func (s *stratumServer) dropSession(sess *stratumSession) {
+	sess.close()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.sessions[sess]; !ok {
+		return
+	}
+	delete(s.sessions, sess)
+	nonce, _ := strconv.ParseUint(sess.extranonce, 16, 16)
+	delete(s.extranonce, uint16(nonce))
+
+	log.Debug("Stratum worker disconnected", "session", sess.id, "worker", sess.worker,
+		"accepted", sess.accepted, "rejected", sess.rejected, "blocks", sess.blocks)
+}
+
+// writeLoop writes the queued messages to a worker.
+func (s *stratumServer) writeLoop(sess *stratumSession) {
+	defer s.wg.Done()
+	defer s.dropSession(sess)
+
+	encoder := json.NewEncoder(sess.conn)
+	for {
+		select {
+		case msg := <-sess.send:
+			sess.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+			if err := encoder.Encode(msg); err != nil {
+				log.Debug("Failed to write to stratum worker", "session", sess.id, "err", err)
+				return
+			}
+		case <-sess.quit:
+			return
+		}
+	}
+}
+
+// readLoop reads and handles the requests of a worker.
+func (s *stratumServer) readLoop(sess *stratumSession) {
+	defer s.wg.Done()
+	defer s.dropSession(sess)
+
+	scanner := bufio.NewScanner(sess.conn)
+	scanner.Buffer(make([]byte, 0, stratumMaxRequestSize), stratumMaxRequestSize)
+	for {
+		sess.conn.SetReadDeadline(time.Now().Add(stratumIdleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				log.Debug("Failed to read from stratum worker", "session", sess.id, "err", err)
+			}
+			return
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var req stratumRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			log.Debug("Invalid stratum request", "session", sess.id, "err", err)
+			return
+		}
+		result, err := s.handle(sess, &req)
+
+		s.lock.Lock()
+		sess.push(&stratumResponse{ID: req.ID, Result: result, Error: err})
+		if req.Method == "mining.authorize" && err == nil && s.current != nil && sess.subscribed {
+			s.sendJob(sess, s.current, true)
+		}
+		s.lock.Unlock()
+	}
+}
+
+// handle serves a worker request.
+func (s *stratumServer) handle(sess *stratumSession, req *stratumRequest) (interface{}, *stratumError) {
+	switch req.Method {
+	case "mining.subscribe":
+		s.lock.Lock()
+		sess.subscribed = true
+		s.lock.Unlock()
+		return []interface{}{[]string{"mining.notify", sess.id, stratumProtocol}, sess.extranonce}, nil
+
+	case "mining.extranonce.subscribe":
+		return true, nil
+
+	case "mining.authorize":
+		var worker string
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &worker) != nil || worker == "" {
+			return false, errStratumUnauthorized
+		}
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if !sess.subscribed {
+			return false, errStratumUnsubscribed
+		}
+		sess.worker = worker
+		return true, nil
+
+	case "mining.submit":
+		var params [3]string
+		if len(req.Params) != len(params) {
+			return false, errStratumOther
+		}
+		for i := range params {
+			if json.Unmarshal(req.Params[i], &params[i]) != nil {
+				return false, errStratumOther
+			}
+		}
+		return s.submit(sess, params[1], params[2])
+	}
+	return nil, errStratumOther
+}
+
+// sendJob announces a job to a worker, along with its share difficulty if that
+// changed. The server lock must be held.
+func (s *stratumServer) sendJob(sess *stratumSession, job *stratumJob, clean bool) {
+	if sess.difficulty != job.difficulty {
+		sess.difficulty = job.difficulty
+		sess.push(&stratumNotification{Method: "mining.set_difficulty", Params: []interface{}{job.difficulty}})
+	}
+	sess.push(&stratumNotification{Method: "mining.notify", Params: []interface{}{
+		job.id, hex.EncodeToString(job.seedhash[:]), hex.EncodeToString(job.sealhash[:]), clean,
+	}})
+}
+
+// newJob announces the work package of a block to all the authorized workers.
+// Jobs of blocks too old for their solutions to be accepted are forgotten.
+func (s *stratumServer) newJob(sealhash common.Hash, block *types.Block) {
+	var (
+		header     = block.Header()
+		target     = nucSealTarget(header)
+		difficulty = s.difficulty
+		share      = stratumTarget(difficulty)
+	)
+	if share.Cmp(target) < 0 {
+		// Shares would be harder than blocks, make every block a share
+		share, difficulty = target, stratumDifficulty(target)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastJob++
+	job := &stratumJob{
+		id:          fmt.Sprintf("%x", s.lastJob),
+		sealhash:    sealhash,
+		seedhash:    common.BytesToHash(SeedHash(header.Number.Uint64())),
+		number:      header.Number.Uint64(),
+		target:      target,
+		shareTarget: share,
+		difficulty:  difficulty,
+		shares:      make(map[uint64]struct{}),
+	}
+	for id, old := range s.jobs {
+		if old.number+staleThreshold <= job.number {
+			delete(s.jobs, id)
+		}
+	}
+	clean := s.current == nil || s.current.number != job.number
+	s.jobs[job.id], s.current = job, job
+
+	for sess := range s.sessions {
+		if sess.subscribed && sess.worker != "" {
+			s.sendJob(sess, job, clean)
+		}
+	}
+}
+
+// submit verifies a share of a worker, submitting it as a block solution if it
+// meets the NUC target of its block.
+func (s *stratumServer) submit(sess *stratumSession, id string, nonceHex string) (bool, *stratumError) {
+	s.lock.Lock()
+	worker := sess.worker
+	if worker == "" {
+		s.lock.Unlock()
+		return false, errStratumUnauthorized
+	}
+	job := s.jobs[id]
+	if job == nil {
+		sess.rejected++
+		s.lock.Unlock()
+		return false, errStratumJobNotFound
+	}
+	s.lock.Unlock()
+
+	// Complete the nonce with the session's extranonce
+	full := sess.extranonce + nonceHex
+	if len(full) != 16 {
+		return false, errStratumOther
+	}
+	nonce, err := strconv.ParseUint(full, 16, 64)
+	if err != nil {
+		return false, errStratumOther
+	}
+	digest, result := s.ethash.hashLight(job.number, job.sealhash, nonce)
+	value := new(big.Int).SetBytes(result)
+
+	s.lock.Lock()
+	if _, ok := job.shares[nonce]; ok {
+		sess.rejected++
+		s.lock.Unlock()
+		return false, errStratumDuplicate
+	}
+	if value.Cmp(job.shareTarget) > 0 {
+		sess.rejected++
+		s.lock.Unlock()
+		return false, errStratumLowShare
+	}
+	job.shares[nonce] = struct{}{}
+	sess.accepted++
+	s.lock.Unlock()
+
+	if value.Cmp(job.target) > 0 {
+		return true, nil
+	}
+	// The share solves the block, hand it to the remote sealer
+	errc := make(chan error, 1)
+	select {
+	case s.ethash.submitWorkCh <- &mineResult{
+		nonce:     types.EncodeNonce(nonce),
+		mixDigest: common.BytesToHash(digest),
+		hash:      job.sealhash,
+		errc:      errc,
+	}:
+	case <-s.quit:
+		return true, nil
+	case <-sess.quit:
+		return true, nil
+	}
+	select {
+	case err := <-errc:
+		if err != nil {
+			log.Warn("Stratum block solution rejected", "worker", worker, "number", job.number, "sealhash", job.sealhash, "err", err)
+			return true, nil
+		}
+	case <-s.quit:
+		return true, nil
+	case <-sess.quit:
+		return true, nil
+	}
+	s.lock.Lock()
+	sess.blocks++
+	s.lock.Unlock()
+
+	log.Info("Stratum worker solved block", "worker", worker, "number", job.number, "sealhash", job.sealhash)
+	return true, nil
+}
+
+// hashLight computes the mix digest and PoW value of a nonce for the given seal
+// hash using the verification cache of its block.
+func (ethash *Ethash) hashLight(number uint64, sealhash common.Hash, nonce uint64) ([]byte, []byte) {
+	if ethash.shared != nil {
+		return ethash.shared.hashLight(number, sealhash, nonce)
+	}
+	cache := ethash.cache(number)
+
+	size := datasetSize(number)
+	if ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	digest, result := hashimotoLight(size, cache.cache, sealhash.Bytes(), nonce)
+
+	// Caches are unmapped in a finalizer. Ensure that the cache stays alive
+	// until after the call to hashimotoLight so it's not unmapped while being used.
+	runtime.KeepAlive(cache)
+	return digest, result
+}
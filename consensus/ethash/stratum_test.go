@@ -0,0 +1,492 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stratumMessage is any message exchanged with the stratum server.
+type stratumMessage struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result"`
+	Error  json.RawMessage   `json:"error"`
+}
+
+// stratumWorker is a simulated stratum worker mining with the light cache.
+type stratumWorker struct {
+	t    *testing.T
+	conn net.Conn
+	id   uint64
+
+	extranonce    string
+	difficulty    float64
+	responses     chan *stratumMessage
+	notifications chan *stratumMessage
+}
+
+// stratumJobNotice is a job announced to a simulated worker.
+type stratumJobNotice struct {
+	id         string
+	seedhash   common.Hash
+	sealhash   common.Hash
+	clean      bool
+	difficulty float64
+}
+
+// newStratumTester creates a test mode ethash serving stratum workers, with the
+// local miner threads disabled.
+func newStratumTester(t *testing.T, difficulty float64) *Ethash {
+	ethash := New(Config{PowMode: ModeTest, StratumAddr: "127.0.0.1:0", StratumDifficulty: difficulty}, nil, false)
+	if ethash.stratum == nil {
+		t.Fatalf("stratum server not started")
+	}
+	ethash.SetThreads(-1)
+	return ethash
+}
+
+// newStratumTestBlock creates a block to seal with the given NUC difficulty.
+func newStratumTestBlock(number int64, coinbase byte, nucDifficulty *big.Int) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{
+		Number:        big.NewInt(number),
+		Coinbase:      common.Address{coinbase},
+		Difficulty:    new(big.Int).Lsh(common.Big1, 64),
+		NUCDifficulty: nucDifficulty,
+	})
+}
+
+// dialStratum connects a simulated worker to the stratum server of ethash.
+func dialStratum(t *testing.T, ethash *Ethash) *stratumWorker {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", ethash.stratum.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial stratum server: %v", err)
+	}
+	w := &stratumWorker{
+		t:             t,
+		conn:          conn,
+		responses:     make(chan *stratumMessage, 16),
+		notifications: make(chan *stratumMessage, 16),
+	}
+	go func() {
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			msg := new(stratumMessage)
+			if err := json.Unmarshal(scanner.Bytes(), msg); err != nil {
+				t.Errorf("invalid stratum message %s: %v", scanner.Bytes(), err)
+				return
+			}
+			if len(msg.ID) == 0 || string(msg.ID) == "null" {
+				w.notifications <- msg
+			} else {
+				w.responses <- msg
+			}
+		}
+	}()
+	return w
+}
+
+// call sends a request to the server, returning its result and error.
+func (w *stratumWorker) call(method string, params ...interface{}) (json.RawMessage, json.RawMessage) {
+	w.t.Helper()
+
+	w.id++
+	if params == nil {
+		params = []interface{}{}
+	}
+	req, _ := json.Marshal(map[string]interface{}{"id": w.id, "method": method, "params": params})
+	if _, err := w.conn.Write(append(req, '\n')); err != nil {
+		w.t.Fatalf("failed to send %s: %v", method, err)
+	}
+	select {
+	case res := <-w.responses:
+		if want := fmt.Sprint(w.id); string(res.ID) != want {
+			w.t.Fatalf("%s response id mismatch: have %s, want %s", method, res.ID, want)
+		}
+		return res.Result, res.Error
+	case <-time.After(5 * time.Second):
+		w.t.Fatalf("%s response timed out", method)
+	}
+	return nil, nil
+}
+
+// subscribe subscribes the worker, saving its extranonce.
+func (w *stratumWorker) subscribe() {
+	w.t.Helper()
+
+	result, err := w.call("mining.subscribe", "sim/1.0", stratumProtocol)
+	if string(err) != "null" {
+		w.t.Fatalf("subscription failed: %s", err)
+	}
+	var reply [2]json.RawMessage
+	if err := json.Unmarshal(result, &reply); err != nil {
+		w.t.Fatalf("invalid subscription %s: %v", result, err)
+	}
+	var notify []string
+	if err := json.Unmarshal(reply[0], &notify); err != nil || len(notify) != 3 || notify[0] != "mining.notify" || notify[2] != stratumProtocol {
+		w.t.Fatalf("invalid subscription details %s", reply[0])
+	}
+	if err := json.Unmarshal(reply[1], &w.extranonce); err != nil || len(w.extranonce) != 2*stratumExtranonceSize {
+		w.t.Fatalf("invalid extranonce %s", reply[1])
+	}
+}
+
+// authorize authorizes the worker with the given name.
+func (w *stratumWorker) authorize(name string) {
+	w.t.Helper()
+
+	if result, err := w.call("mining.authorize", name, "x"); string(result) != "true" {
+		w.t.Fatalf("authorization failed: %s", err)
+	}
+}
+
+// job waits for the next job announced to the worker.
+func (w *stratumWorker) job() *stratumJobNotice {
+	w.t.Helper()
+
+	for {
+		select {
+		case msg := <-w.notifications:
+			switch msg.Method {
+			case "mining.set_difficulty":
+				if len(msg.Params) != 1 || json.Unmarshal(msg.Params[0], &w.difficulty) != nil {
+					w.t.Fatalf("invalid difficulty params %s", msg.Params)
+				}
+			case "mining.notify":
+				var (
+					job  = &stratumJobNotice{difficulty: w.difficulty}
+					seed string
+					head string
+				)
+				if len(msg.Params) != 4 ||
+					json.Unmarshal(msg.Params[0], &job.id) != nil ||
+					json.Unmarshal(msg.Params[1], &seed) != nil ||
+					json.Unmarshal(msg.Params[2], &head) != nil ||
+					json.Unmarshal(msg.Params[3], &job.clean) != nil {
+					w.t.Fatalf("invalid job params %s", msg.Params)
+				}
+				job.seedhash, job.sealhash = common.HexToHash(seed), common.HexToHash(head)
+				return job
+			default:
+				w.t.Fatalf("unexpected notification %s", msg.Method)
+			}
+		case <-time.After(5 * time.Second):
+			w.t.Fatalf("job notification timed out")
+		}
+	}
+}
+
+// noJob ensures no notification is pending for the worker.
+func (w *stratumWorker) noJob() {
+	w.t.Helper()
+
+	select {
+	case msg := <-w.notifications:
+		w.t.Fatalf("unexpected notification %s", msg.Method)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// mine searches the extranonce space of the worker from the given nonce until
+// it finds one meeting the share difficulty of the job, returning the nonce
+// suffix to submit along with the full nonce.
+func (w *stratumWorker) mine(verifier *Ethash, number uint64, job *stratumJobNotice, from uint64) (string, uint64) {
+	prefix, _ := hex.DecodeString(w.extranonce)
+	target := stratumTarget(job.difficulty)
+	for i := from; ; i++ {
+		nonce := uint64(binary.BigEndian.Uint16(prefix))<<48 | i
+		if _, result := verifier.hashLight(number, job.sealhash, nonce); new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			return fmt.Sprintf("%012x", i), nonce
+		}
+	}
+}
+
+// checkStratumError verifies the code of an error reported by the server.
+func checkStratumError(t *testing.T, index int, have json.RawMessage, want *stratumError) {
+	t.Helper()
+
+	var triplet []interface{}
+	if err := json.Unmarshal(have, &triplet); err != nil || len(triplet) != 3 {
+		t.Fatalf("test %d: invalid error %s", index, have)
+	}
+	if code, _ := triplet[0].(float64); int(code) != want.code {
+		t.Errorf("test %d: error code mismatch: have %v, want %v", index, triplet[0], want.code)
+	}
+}
+
+func TestStratumDifficulty(t *testing.T) {
+	tests := []struct {
+		difficulty float64
+		target     *big.Int
+	}{
+		{1, new(big.Int).Lsh(common.Big1, 224)},
+		{4, new(big.Int).Lsh(common.Big1, 222)},
+		{1.0 / (1 << 30), new(big.Int).Lsh(common.Big1, 254)},
+		{1.0 / (1 << 40), new(big.Int).Sub(two256, common.Big1)},
+	}
+	for i, tt := range tests {
+		if target := stratumTarget(tt.difficulty); target.Cmp(tt.target) != 0 {
+			t.Errorf("test %d: target mismatch: have %x, want %x", i, target, tt.target)
+		}
+		if i < 3 {
+			if difficulty := stratumDifficulty(tt.target); difficulty != tt.difficulty {
+				t.Errorf("test %d: difficulty mismatch: have %v, want %v", i, difficulty, tt.difficulty)
+			}
+		}
+	}
+}
+
+// Tests that the stratum session is negotiated as expected, and that invalid
+// requests and shares are rejected.
+func TestStratumSession(t *testing.T) {
+	ethash := newStratumTester(t, 1)
+	defer ethash.Close()
+
+	w := dialStratum(t, ethash)
+	defer w.conn.Close()
+
+	// Requests out of order are refused
+	if result, err := w.call("mining.authorize", "sim.1", "x"); string(result) != "false" {
+		t.Fatalf("unsubscribed authorization accepted")
+	} else {
+		checkStratumError(t, 0, err, errStratumUnsubscribed)
+	}
+	w.subscribe()
+	if result, err := w.call("mining.submit", "sim.1", "1", "000000000000"); string(result) != "false" {
+		t.Fatalf("unauthorized share accepted")
+	} else {
+		checkStratumError(t, 0, err, errStratumUnauthorized)
+	}
+	if result, _ := w.call("mining.extranonce.subscribe"); string(result) != "true" {
+		t.Fatalf("extranonce subscription failed")
+	}
+	w.authorize("sim.1")
+
+	// Block harder than the shares, the share difficulty is announced as is
+	block := newStratumTestBlock(1, 0x01, new(big.Int).Lsh(common.Big1, 40))
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, block, results, nil)
+
+	job := w.job()
+	if job.difficulty != 1 {
+		t.Errorf("share difficulty mismatch: have %v, want %v", job.difficulty, 1)
+	}
+	if want := ethash.SealHash(block.Header()); job.sealhash != want {
+		t.Errorf("job hash mismatch: have %x, want %x", job.sealhash, want)
+	}
+	if want := common.BytesToHash(SeedHash(1)); job.seedhash != want {
+		t.Errorf("job seed mismatch: have %x, want %x", job.seedhash, want)
+	}
+	if !job.clean {
+		t.Errorf("first job not clean")
+	}
+	// Find a nonce failing the share difficulty
+	var low string
+	for i := uint64(0); low == ""; i++ {
+		prefix, _ := hex.DecodeString(w.extranonce)
+		nonce := uint64(binary.BigEndian.Uint16(prefix))<<48 | i
+		if _, result := ethash.hashLight(1, job.sealhash, nonce); new(big.Int).SetBytes(result).Cmp(stratumTarget(1)) > 0 {
+			low = fmt.Sprintf("%012x", i)
+		}
+	}
+	tests := []struct {
+		method string
+		params []interface{}
+		err    *stratumError
+	}{
+		{"mining.submit", []interface{}{"sim.1", "ff", low}, errStratumJobNotFound},
+		{"mining.submit", []interface{}{"sim.1", job.id, "00"}, errStratumOther},
+		{"mining.submit", []interface{}{"sim.1", job.id, "zzzzzzzzzzzz"}, errStratumOther},
+		{"mining.submit", []interface{}{"sim.1", job.id}, errStratumOther},
+		{"mining.submit", []interface{}{"sim.1", job.id, low}, errStratumLowShare},
+		{"mining.hashrate", []interface{}{"0x1", "0x2"}, errStratumOther},
+	}
+	for i, tt := range tests {
+		result, err := w.call(tt.method, tt.params...)
+		if string(result) == "true" {
+			t.Errorf("test %d: request accepted", i)
+		}
+		checkStratumError(t, i, err, tt.err)
+	}
+	select {
+	case block := <-results:
+		t.Fatalf("unexpected block sealed: %x", block.Hash())
+	default:
+	}
+}
+
+// Tests that multiple workers mine distinct shares of the same job, and that
+// shares are only accepted once.
+func TestStratumShares(t *testing.T) {
+	ethash := newStratumTester(t, 1.0/(1<<30))
+	defer ethash.Close()
+
+	workers := make([]*stratumWorker, 3)
+	for i := range workers {
+		workers[i] = dialStratum(t, ethash)
+		defer workers[i].conn.Close()
+
+		workers[i].subscribe()
+		workers[i].authorize(fmt.Sprintf("sim.%d", i))
+	}
+	for i := range workers {
+		for j := 0; j < i; j++ {
+			if workers[i].extranonce == workers[j].extranonce {
+				t.Fatalf("worker %d and %d share extranonce %s", i, j, workers[i].extranonce)
+			}
+		}
+	}
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, newStratumTestBlock(1, 0x01, new(big.Int).Lsh(common.Big1, 64)), results, nil)
+
+	for i, w := range workers {
+		job := w.job()
+		if job.difficulty != 1.0/(1<<30) {
+			t.Errorf("worker %d: share difficulty mismatch: have %v, want %v", i, job.difficulty, 1.0/(1<<30))
+		}
+		var from uint64
+		for j := 0; j < 3; j++ {
+			suffix, nonce := w.mine(ethash, 1, job, from)
+			from = nonce&0xffffffffffff + 1
+
+			if result, err := w.call("mining.submit", fmt.Sprintf("sim.%d", i), job.id, suffix); string(result) != "true" {
+				t.Fatalf("worker %d: share %d rejected: %s", i, j, err)
+			}
+			if j == 0 {
+				result, err := w.call("mining.submit", fmt.Sprintf("sim.%d", i), job.id, suffix)
+				if string(result) == "true" {
+					t.Fatalf("worker %d: duplicate share accepted", i)
+				}
+				checkStratumError(t, i, err, errStratumDuplicate)
+			}
+		}
+	}
+	ethash.stratum.lock.Lock()
+	for sess := range ethash.stratum.sessions {
+		if sess.accepted != 3 || sess.rejected != 1 || sess.blocks != 0 {
+			t.Errorf("worker %s: shares mismatch: have %d/%d/%d, want %d/%d/%d", sess.worker, sess.accepted, sess.rejected, sess.blocks, 3, 1, 0)
+		}
+	}
+	ethash.stratum.lock.Unlock()
+
+	select {
+	case block := <-results:
+		t.Fatalf("unexpected block sealed: %x", block.Hash())
+	default:
+	}
+}
+
+// Tests that a share meeting the NUC target of its block seals the block, even
+// though the classic difficulty isn't met.
+func TestStratumBlock(t *testing.T) {
+	ethash := newStratumTester(t, 1)
+	defer ethash.Close()
+
+	w := dialStratum(t, ethash)
+	defer w.conn.Close()
+
+	w.subscribe()
+	w.authorize("sim.1")
+
+	// Block easier than the shares, the block difficulty is announced instead
+	block := newStratumTestBlock(1, 0x01, big.NewInt(16))
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, block, results, nil)
+
+	job := w.job()
+	if want := stratumDifficulty(nucSealTarget(block.Header())); job.difficulty != want {
+		t.Errorf("share difficulty mismatch: have %v, want %v", job.difficulty, want)
+	}
+	suffix, nonce := w.mine(ethash, 1, job, 0)
+	if result, err := w.call("mining.submit", "sim.1", job.id, suffix); string(result) != "true" {
+		t.Fatalf("block solution rejected: %s", err)
+	}
+	select {
+	case sealed := <-results:
+		if sealed.Nonce() != nonce {
+			t.Errorf("sealed nonce mismatch: have %x, want %x", sealed.Nonce(), nonce)
+		}
+		if err := ethash.verifySeal(nil, sealed.Header(), false); err != nil {
+			t.Errorf("sealed block invalid: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sealed block timed out")
+	}
+	ethash.stratum.lock.Lock()
+	for sess := range ethash.stratum.sessions {
+		if sess.blocks != 1 {
+			t.Errorf("blocks mismatch: have %d, want %d", sess.blocks, 1)
+		}
+	}
+	ethash.stratum.lock.Unlock()
+}
+
+// Tests that new pending blocks are announced to all the authorized workers,
+// flagged clean only when the chain moved on.
+func TestStratumNotify(t *testing.T) {
+	ethash := newStratumTester(t, 1)
+	defer ethash.Close()
+
+	workers := make([]*stratumWorker, 2)
+	for i := range workers {
+		workers[i] = dialStratum(t, ethash)
+		defer workers[i].conn.Close()
+
+		workers[i].subscribe()
+		workers[i].authorize(fmt.Sprintf("sim.%d", i))
+	}
+	// Subscribed but unauthorized workers are not notified
+	idle := dialStratum(t, ethash)
+	defer idle.conn.Close()
+	idle.subscribe()
+
+	tests := []struct {
+		number   int64
+		coinbase byte
+		clean    bool
+	}{
+		{1, 0x01, true},
+		{1, 0x02, false},
+		{2, 0x01, true},
+	}
+	var (
+		results = make(chan *types.Block, 1)
+		current common.Hash
+	)
+	for i, tt := range tests {
+		block := newStratumTestBlock(tt.number, tt.coinbase, new(big.Int).Lsh(common.Big1, 40))
+		ethash.Seal(nil, block, results, nil)
+		current = ethash.SealHash(block.Header())
+
+		for j, w := range workers {
+			job := w.job()
+			if job.sealhash != current {
+				t.Errorf("test %d, worker %d: job hash mismatch: have %x, want %x", i, j, job.sealhash, current)
+			}
+			if job.clean != tt.clean {
+				t.Errorf("test %d, worker %d: clean mismatch: have %v, want %v", i, j, job.clean, tt.clean)
+			}
+		}
+	}
+	idle.noJob()
+
+	// Workers authorizing later are handed the current job
+	idle.authorize("sim.idle")
+	if job := idle.job(); job.sealhash != current || !job.clean {
+		t.Errorf("late job mismatch: have %x/%v, want %x/%v", job.sealhash, job.clean, current, true)
+	}
+}
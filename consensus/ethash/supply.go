@@ -0,0 +1,222 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// errLegacyRewards is returned when the issuance of a block rewarded by
+	// accumulateRewardsold, or recording its rewards in the legacy CoinbaseTxs
+	// encoding, is requested, as its rewards can't be accounted for from the
+	// header.
+	errLegacyRewards = errors.New("legacy block rewards can't be accounted")
+
+	// errIssuanceOverSchedule is returned if the participant rewards of a block
+	// exceed the block reward of the issuance schedule.
+	errIssuanceOverSchedule = errors.New("participant rewards exceed block reward")
+
+	// errFeesOverCollected is returned if a block redistributes more fees than
+	// its transactions and uncles left for the participants and the team.
+	errFeesOverCollected = errors.New("redistributed fees exceed collected fees")
+
+	// errReceiptsMismatch is returned if the rewards of a block are requested
+	// with receipts not matching its transactions.
+	errReceiptsMismatch = errors.New("receipts don't match transactions")
+)
+
+// uncleReward returns the reward of an uncle included by the given header.
+func uncleReward(header, uncle *types.Header, blockReward *big.Int) *big.Int {
+	r := new(big.Int).Add(uncle.Number, big8)
+	r.Sub(r, header.Number)
+	r.Mul(r, blockReward)
+	return r.Div(r, big8)
+}
+
+// includerReward returns the reward minted for including uncles: a 32nd of the
+// block reward per uncle, shared out like the transaction fees.
+func includerReward(uncles []*types.Header, blockReward *big.Int) *big.Int {
+	r := new(big.Int).Div(blockReward, big32)
+	return r.Mul(r, big.NewInt(int64(len(uncles))))
+}
+
+// nucFee returns the fee a block hands out to its participants and the team
+// under the legacy fee split: the includer reward, plus the 70% of the
+// transaction fees not credited to the coinbase. Fees are charged on the gas
+// limit of the transactions, and the 70% is applied again to the running total
+// after every transaction, as the consensus rules always did.
+func nucFee(txs []*types.Transaction, uncles []*types.Header, blockReward *big.Int) *big.Int {
+	fee := includerReward(uncles, blockReward)
+	for _, tx := range txs {
+		fee.Add(fee, new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas())))
+		// 70% fee to pow and pool
+		fee.Mul(fee, big.NewInt(70))
+		fee.Div(fee, big.NewInt(100))
+	}
+	return fee
+}
+
+// splitNUCFee splits a block's fee into the 5% share of the team and the 95%
+// shared out equally between the rewarded participants.
+func splitNUCFee(fee *big.Int) (team *big.Int, participants *big.Int) {
+	team = new(big.Int).Mul(fee, big.NewInt(5))
+	team.Div(team, big.NewInt(100))
+	participants = new(big.Int).Mul(fee, big.NewInt(95))
+	participants.Div(participants, big.NewInt(100))
+	return team, participants
+}
+
+// nucIssuance computes the coins created by accumulateNUCRewards for a block,
+// from the rewards recorded in its header. It fails if the rewards break the
+// issuance schedule or redistribute more fees than collected.
+//
+// Under a fee policy, the transaction fees are only moved around, so the only
+// fees minted are the includer rewards, and the participants' rewards no longer
+// cut into the block reward.
+func nucIssuance(header *types.Header, rewards []types.CoinbaseTx, txs []*types.Transaction, uncles []*types.Header, policy *params.NUCFeePolicy) (*types.BlockIssuance, error) {
+	var (
+		blockReward = FrontierBlockReward
+		issuance    = &types.BlockIssuance{Minted: new(big.Int), Fees: new(big.Int), Team: new(big.Int)}
+		scheduled   = new(big.Int)
+	)
+	for _, uncle := range uncles {
+		issuance.Minted.Add(issuance.Minted, uncleReward(header, uncle, blockReward))
+	}
+	for _, reward := range rewards {
+		scheduled.Add(scheduled, reward.Total())
+	}
+	if scheduled.Cmp(blockReward) > 0 {
+		return nil, fmt.Errorf("%v: have %v, want at most %v", errIssuanceOverSchedule, scheduled, blockReward)
+	}
+	if policy != nil {
+		issuance.Minted.Add(issuance.Minted, blockReward)
+		issuance.Fees.Set(includerReward(uncles, blockReward))
+		return issuance, nil
+	}
+	fee := nucFee(txs, uncles, blockReward)
+	team, share := splitNUCFee(fee)
+	if len(rewards) > 0 {
+		share.Div(share, big.NewInt(int64(len(rewards))))
+		issuance.Fees.Mul(share, big.NewInt(int64(len(rewards))))
+	}
+	if team.Sign() > 0 {
+		issuance.Team.Set(team)
+	}
+	if redistributed := new(big.Int).Add(issuance.Fees, issuance.Team); redistributed.Cmp(fee) > 0 {
+		return nil, fmt.Errorf("%v: have %v, want at most %v", errFeesOverCollected, redistributed, fee)
+	}
+	// Whatever the participants don't get of the block reward goes to the default
+	// coinbase, so the full block reward is always minted.
+	if credited := new(big.Int).Add(scheduled, issuance.Fees); credited.Cmp(blockReward) < 0 {
+		issuance.Minted.Add(issuance.Minted, new(big.Int).Sub(blockReward, issuance.Fees))
+	} else {
+		issuance.Minted.Add(issuance.Minted, scheduled)
+	}
+	return issuance, nil
+}
+
+// checkIssuance verifies that the coins credited while finalizing a block match
+// the issuance accounted from its rewards and that the issuance invariants hold.
+func checkIssuance(header *types.Header, rewards []types.CoinbaseTx, txs []*types.Transaction, uncles []*types.Header, policy *params.NUCFeePolicy, credited *big.Int) error {
+	issuance, err := nucIssuance(header, rewards, txs, uncles, policy)
+	if err != nil {
+		return err
+	}
+	if total := issuance.Total(); total.Cmp(credited) != 0 {
+		return fmt.Errorf("credited %v, accounted %v", credited, total)
+	}
+	return nil
+}
+
+// BlockIssuance implements consensus.SupplyReporter, recomputing the coins the
+// rewards of a block created from its header and body.
+func (ethash *Ethash) BlockIssuance(chain consensus.ChainReader, block *types.Block) (*types.BlockIssuance, error) {
+	rewards, err := blockRewards(chain.Config(), block.Header())
+	if err != nil {
+		return nil, err
+	}
+	return nucIssuance(block.Header(), rewards, block.Transactions(), block.Uncles(), chain.Config().NUCFeePolicy(block.Number()))
+}
+
+// blockRewards decodes the rewards recorded in the header of a block rewarded by
+// accumulateNUCRewards. Blocks whose rewards aren't fully recorded are rejected.
+func blockRewards(config *params.ChainConfig, header *types.Header) ([]types.CoinbaseTx, error) {
+	if config.NUCRewardVersion(header.Number) == params.NUCRewardV1 || !config.IsNUCCoinbaseTxs(header.Number) {
+		return nil, errLegacyRewards
+	}
+	return types.DecodeCoinbaseTxs(header.CoinbaseTxs)
+}
+
+// nucRewards breaks down the rewards credited by accumulateNUCRewards for a
+// block, from the rewards recorded in its header. The receipts are only needed
+// under a fee policy, to recover the fees pooled for the participants by the
+// state transition.
+func nucRewards(rewards []types.CoinbaseTx, txs []*types.Transaction, receipts []*types.Receipt, uncles []*types.Header, policy *params.NUCFeePolicy) (*types.BlockRewards, error) {
+	var (
+		blockReward = FrontierBlockReward
+		result      = &types.BlockRewards{Treasury: DefaultCoinbaseAddr, Leftover: new(big.Int), Coinbase: DefaultCoinbaseAddr}
+		scheduled   = new(big.Int)
+		paid        = new(big.Int)
+		shared      *big.Int
+	)
+	if policy == nil {
+		result.Team, shared = splitNUCFee(nucFee(txs, uncles, blockReward))
+	} else {
+		if len(receipts) != len(txs) {
+			return nil, fmt.Errorf("%v: have %d, want %d", errReceiptsMismatch, len(receipts), len(txs))
+		}
+		shared = includerReward(uncles, blockReward)
+		for i, tx := range txs {
+			fee := new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tx.GasPrice())
+			shared.Add(shared, policy.Split(fee).Participants)
+		}
+		result.Treasury = policy.Treasury
+	}
+	share := new(big.Int).Set(shared)
+	if len(rewards) > 0 {
+		share.Div(share, big.NewInt(int64(len(rewards))))
+	}
+	for _, reward := range rewards {
+		result.Participants = append(result.Participants, &types.ParticipantReward{
+			Address: reward.Address,
+			Poc:     reward.PocReward,
+			Pow:     reward.PowReward,
+			Pool:    reward.PoolReward,
+			Post:    reward.PostReward,
+			Fee:     new(big.Int).Set(share),
+		})
+		scheduled.Add(scheduled, reward.Total())
+		paid.Add(paid, share)
+	}
+	if scheduled.Cmp(blockReward) > 0 {
+		return nil, fmt.Errorf("%v: have %v, want at most %v", errIssuanceOverSchedule, scheduled, blockReward)
+	}
+	// Under a fee policy, the team gets the fees no participant got and the fees
+	// don't cut into the block reward, see accumulateNUCRewards
+	minted := new(big.Int).Add(scheduled, paid)
+	if policy != nil {
+		result.Team = shared.Sub(shared, paid)
+		minted = scheduled
+	}
+	if minted.Cmp(blockReward) < 0 {
+		result.Leftover.Sub(blockReward, minted)
+	}
+	return result, nil
+}
+
+// BlockRewards implements consensus.RewardReporter, breaking down the rewards
+// credited by a block from its header, body and receipts.
+func (ethash *Ethash) BlockRewards(chain consensus.ChainReader, block *types.Block, receipts []*types.Receipt) (*types.BlockRewards, error) {
+	rewards, err := blockRewards(chain.Config(), block.Header())
+	if err != nil {
+		return nil, err
+	}
+	return nucRewards(rewards, block.Transactions(), receipts, block.Uncles(), chain.Config().NUCFeePolicy(block.Number()))
+}
@@ -0,0 +1,269 @@
+// Copyright 2019 The nuc Team
+
+package ethash
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests the issuance accounted for blocks with various rewards, fees and uncles.
+func TestNUCIssuance(t *testing.T) {
+	var (
+		nuc = func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+		tx  = types.NewTransaction(0, common.Address{}, new(big.Int), 100000, big.NewInt(1e9), nil)
+		fee = big.NewInt(100000 * 1e9 * 70 / 100) // Fee of a single tx
+
+		team   = new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(5)), big.NewInt(100))
+		shared = new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(95)), big.NewInt(100))
+		paid   = new(big.Int).Mul(new(big.Int).Div(shared, big.NewInt(2)), big.NewInt(2)) // Shared out between 2 participants
+	)
+	tests := []struct {
+		rewards []*big.Int
+		txs     []*types.Transaction
+		uncles  int
+		minted  *big.Int
+		fees    *big.Int
+		team    *big.Int
+		err     error
+	}{
+		// Empty block, the whole reward goes to the default coinbase
+		{minted: FrontierBlockReward, fees: new(big.Int), team: new(big.Int)},
+		// Partially rewarded block, the leftover is still minted
+		{rewards: []*big.Int{nuc(3), nuc(4)}, minted: FrontierBlockReward, fees: new(big.Int), team: new(big.Int)},
+		// Fees are shared out equally and cut out of the leftover
+		{
+			rewards: []*big.Int{nuc(3), nuc(4)},
+			txs:     []*types.Transaction{tx},
+			minted:  new(big.Int).Sub(FrontierBlockReward, paid),
+			fees:    paid,
+			team:    team,
+		},
+		// Fees aren't redistributed without participants, the team still gets its share
+		{
+			txs:    []*types.Transaction{tx},
+			minted: FrontierBlockReward,
+			fees:   new(big.Int),
+			team:   team,
+		},
+		// Uncles are minted on top of the block reward, a 32nd of which each
+		// includer shares out as fees
+		{
+			uncles: 1,
+			minted: new(big.Int).Add(FrontierBlockReward, new(big.Int).Div(new(big.Int).Mul(FrontierBlockReward, big.NewInt(7)), big8)),
+			fees:   new(big.Int),
+			team:   new(big.Int).Div(new(big.Int).Mul(new(big.Int).Div(FrontierBlockReward, big32), big.NewInt(5)), big.NewInt(100)),
+		},
+		// Fully rewarded block
+		{rewards: []*big.Int{FrontierBlockReward}, minted: FrontierBlockReward, fees: new(big.Int), team: new(big.Int)},
+		// Over rewarded block
+		{rewards: []*big.Int{FrontierBlockReward, big.NewInt(1)}, err: errIssuanceOverSchedule},
+	}
+	for i, tt := range tests {
+		var rewards []types.CoinbaseTx
+		for j, reward := range tt.rewards {
+			rewards = append(rewards, types.CoinbaseTx{Address: common.Address{byte(j + 1)}, PocReward: reward, PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)})
+		}
+		header := &types.Header{Number: big.NewInt(10)}
+		var uncles []*types.Header
+		for j := 0; j < tt.uncles; j++ {
+			uncles = append(uncles, &types.Header{Number: big.NewInt(9)})
+		}
+		issuance, err := nucIssuance(header, rewards, tt.txs, uncles, nil)
+		if tt.err != nil {
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err.Error()) {
+				t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to account issuance: %v", i, err)
+			continue
+		}
+		if issuance.Minted.Cmp(tt.minted) != 0 || issuance.Fees.Cmp(tt.fees) != 0 || issuance.Team.Cmp(tt.team) != 0 {
+			t.Errorf("test %d: issuance mismatch: have %v/%v/%v, want %v/%v/%v", i, issuance.Minted, issuance.Fees, issuance.Team, tt.minted, tt.fees, tt.team)
+		}
+	}
+}
+
+// Tests that the fee of multiple transactions keeps being scaled down by the 70%
+// cut, as the consensus rules always did.
+func TestNUCFeeCompounds(t *testing.T) {
+	var (
+		tx   = types.NewTransaction(0, common.Address{}, new(big.Int), 100, big.NewInt(1), nil)
+		have = nucFee([]*types.Transaction{tx, tx}, nil, FrontierBlockReward)
+		want = big.NewInt((100*70/100 + 100) * 70 / 100)
+	)
+	if have.Cmp(want) != 0 {
+		t.Fatalf("fee mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that coins credited while finalizing must match the accounted issuance.
+func TestCheckIssuance(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1)}
+	if err := checkIssuance(header, nil, nil, nil, nil, FrontierBlockReward); err != nil {
+		t.Fatalf("matching credits rejected: %v", err)
+	}
+	if err := checkIssuance(header, nil, nil, nil, nil, new(big.Int).Add(FrontierBlockReward, common.Big1)); err == nil {
+		t.Fatalf("over credited block accepted")
+	}
+}
+
+// Tests that a block whose participant rewards break the supply invariant is
+// rejected while finalizing from the supply invariant fork on, and accepted
+// before it as the chain always did.
+func TestAccumulateNUCRewardsInvariant(t *testing.T) {
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{CoinbaseTxsBlock: common.Big0, SupplyInvariantBlock: big.NewInt(10)}
+	chain := &nucTestChain{config: &config}
+
+	algorithm := func(header *types.Header, state *state.StateDB, c consensus.ChainReader) *CoinbaseTxs {
+		ctxs := make(CoinbaseTxs)
+		ctxs[common.Address{1}] = &CoinbaseUserReward{new(big.Int), new(big.Int), new(big.Int).Add(FrontierBlockReward, common.Big1), new(big.Int)}
+		return &ctxs
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err := accumulateNUCRewards(chain, statedb, &types.Header{Number: big.NewInt(9)}, nil, nil, algorithm); err != nil {
+		t.Fatalf("pre-fork over scheduled block rejected: %v", err)
+	}
+	statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err := accumulateNUCRewards(chain, statedb, &types.Header{Number: big.NewInt(10)}, nil, nil, algorithm); err == nil {
+		t.Fatalf("over scheduled block accepted")
+	}
+}
+
+// Tests that blocks are rejected when the chain config schedules an unknown
+// reward version, instead of being finalized without rewards.
+func TestAccumulateRewardsUnknownVersion(t *testing.T) {
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{RewardRules: []params.NUCRewardRule{{Block: common.Big0, Version: params.NUCRewardLatest + 1}}}
+	chain := &nucTestChain{config: &config}
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err := accumulateRewards(chain, statedb, &types.Header{Number: big.NewInt(1)}, nil, nil); err != errUnknownRewardVersion {
+		t.Fatalf("error mismatch: have %v, want %v", err, errUnknownRewardVersion)
+	}
+}
+
+// Tests that under a fee policy only the block, uncle and includer rewards are
+// minted, whatever the participants and transactions of the block.
+func TestNUCIssuancePolicy(t *testing.T) {
+	var (
+		policy = &params.NUCFeePolicy{Block: common.Big0, Coinbase: 20, Participants: 60, Team: 10, Burn: 10, Treasury: common.Address{0xee}}
+		tx     = types.NewTransaction(0, common.Address{}, new(big.Int), 100000, big.NewInt(1e9), nil)
+		uncle  = &types.Header{Number: big.NewInt(9)}
+	)
+	tests := []struct {
+		rewards []*big.Int
+		uncles  []*types.Header
+		minted  *big.Int
+		fees    *big.Int
+	}{
+		{minted: FrontierBlockReward, fees: new(big.Int)},
+		{rewards: []*big.Int{big.NewInt(3), big.NewInt(4)}, minted: FrontierBlockReward, fees: new(big.Int)},
+		{
+			rewards: []*big.Int{FrontierBlockReward},
+			uncles:  []*types.Header{uncle},
+			minted:  new(big.Int).Add(FrontierBlockReward, uncleReward(&types.Header{Number: big.NewInt(10)}, uncle, FrontierBlockReward)),
+			fees:    new(big.Int).Div(FrontierBlockReward, big32),
+		},
+	}
+	for i, tt := range tests {
+		var rewards []types.CoinbaseTx
+		for j, reward := range tt.rewards {
+			rewards = append(rewards, types.CoinbaseTx{Address: common.Address{byte(j + 1)}, PocReward: reward, PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)})
+		}
+		header := &types.Header{Number: big.NewInt(10)}
+		issuance, err := nucIssuance(header, rewards, []*types.Transaction{tx}, tt.uncles, policy)
+		if err != nil {
+			t.Errorf("test %d: failed to account issuance: %v", i, err)
+			continue
+		}
+		if issuance.Minted.Cmp(tt.minted) != 0 || issuance.Fees.Cmp(tt.fees) != 0 || issuance.Team.Sign() != 0 {
+			t.Errorf("test %d: issuance mismatch: have %v/%v/%v, want %v/%v/0", i, issuance.Minted, issuance.Fees, issuance.Team, tt.minted, tt.fees)
+		}
+	}
+}
+
+// Tests that the rewards broken down for a block match the balances credited
+// when finalizing it, with and without a fee policy.
+func TestNUCRewards(t *testing.T) {
+	var (
+		policy = params.NUCFeePolicy{Block: common.Big0, Coinbase: 20, Participants: 60, Team: 10, Burn: 10, Treasury: common.Address{0xee}}
+		tx     = types.NewTransaction(0, common.Address{}, new(big.Int), 100000, big.NewInt(1e9), nil)
+		uncle  = &types.Header{Number: big.NewInt(9), Coinbase: common.Address{0xdd}}
+	)
+	tests := []struct {
+		policies []params.NUCFeePolicy
+		rewards  []*big.Int
+		uncles   []*types.Header
+	}{
+		{},
+		{rewards: []*big.Int{big.NewInt(3e18), big.NewInt(4e18), big.NewInt(5)}},
+		{rewards: []*big.Int{FrontierBlockReward}, uncles: []*types.Header{uncle}},
+		{policies: []params.NUCFeePolicy{policy}},
+		{policies: []params.NUCFeePolicy{policy}, rewards: []*big.Int{big.NewInt(3e18), big.NewInt(4e18), big.NewInt(5)}},
+		{policies: []params.NUCFeePolicy{policy}, rewards: []*big.Int{FrontierBlockReward}, uncles: []*types.Header{uncle}},
+	}
+	for i, tt := range tests {
+		config := *params.TestChainConfig
+		config.NUC = &params.NUCConfig{CoinbaseTxsBlock: common.Big0, FeePolicies: tt.policies}
+		chain := &nucTestChain{config: &config}
+
+		header := &types.Header{Number: big.NewInt(10)}
+		receipts := []*types.Receipt{{GasUsed: 60000}}
+		algorithm := func(header *types.Header, state *state.StateDB, c consensus.ChainReader) *CoinbaseTxs {
+			ctxs := make(CoinbaseTxs)
+			for j, reward := range tt.rewards {
+				ctxs[common.Address{byte(j + 1)}] = &CoinbaseUserReward{new(big.Int), new(big.Int), reward, new(big.Int)}
+			}
+			return &ctxs
+		}
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		if len(tt.policies) > 0 {
+			// Pool the participants' share of the fee, as the state transition does
+			fee := new(big.Int).Mul(big.NewInt(int64(receipts[0].GasUsed)), tx.GasPrice())
+			statedb.AddBalance(params.NUCFeePoolAddress, policy.Split(fee).Participants)
+		}
+		if err := accumulateNUCRewards(chain, statedb, header, tt.uncles, []*types.Transaction{tx}, algorithm); err != nil {
+			t.Fatalf("test %d: failed to credit rewards: %v", i, err)
+		}
+
+		block := types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, tt.uncles)
+		rewards, err := new(Ethash).BlockRewards(chain, block, receipts)
+		if err != nil {
+			t.Errorf("test %d: failed to break down rewards: %v", i, err)
+			continue
+		}
+		if len(rewards.Participants) != len(tt.rewards) {
+			t.Errorf("test %d: participant count mismatch: have %d, want %d", i, len(rewards.Participants), len(tt.rewards))
+			continue
+		}
+		for _, reward := range rewards.Participants {
+			if have := statedb.GetBalance(reward.Address); have.Cmp(reward.Total()) != 0 {
+				t.Errorf("test %d: %x: credited %v, broken down %v", i, reward.Address, have, reward.Total())
+			}
+		}
+		// Without a policy, the team fees go to the default coinbase as well
+		treasury, coinbase := new(big.Int).Set(rewards.Team), new(big.Int).Set(rewards.Leftover)
+		if rewards.Treasury == rewards.Coinbase {
+			treasury.Add(treasury, coinbase)
+			coinbase = treasury
+		}
+		if have := statedb.GetBalance(rewards.Treasury); have.Cmp(treasury) != 0 {
+			t.Errorf("test %d: treasury credited %v, broken down %v", i, have, treasury)
+		}
+		if have := statedb.GetBalance(rewards.Coinbase); have.Cmp(coinbase) != 0 {
+			t.Errorf("test %d: default coinbase credited %v, broken down %v", i, have, coinbase)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 The nuc Team
+
+package misc
+
+import (
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ApplyNUCRuleUpgrade modifies the state database according to a governed rule
+// contract upgrade, replacing the code of the contract and overwriting the
+// given storage slots. Balance and nonce are kept, so is any storage slot the
+// upgrade doesn't mention.
+func ApplyNUCRuleUpgrade(statedb *state.StateDB, upgrade *params.NUCRuleUpgrade) {
+	if !statedb.Exist(params.NUCRuleContractAddress) {
+		statedb.CreateAccount(params.NUCRuleContractAddress)
+	}
+	if upgrade.Code != nil {
+		statedb.SetCode(params.NUCRuleContractAddress, upgrade.Code)
+	}
+	for key, value := range upgrade.Storage {
+		statedb.SetState(params.NUCRuleContractAddress, key, value)
+	}
+}
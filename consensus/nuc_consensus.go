@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"math/big"
 )
 
@@ -13,6 +14,21 @@ const (
 	BlockVersion = 1
 )
 
+// nucUnit is the number of wei in a NUC.
+var nucUnit = big.NewInt(1000000000000000000)
+
+// NUCDifficultyDiscount returns the difficulty after applying a NUC difficulty
+// discount, which divides it.
+func NUCDifficultyDiscount(diff *big.Int, divisor uint64) *big.Int {
+	return new(big.Int).Div(diff, new(big.Int).SetUint64(divisor))
+}
+
+// nucDifficultyRules returns the NUC difficulty discount parameters in force at
+// the child of the given parent block.
+func nucDifficultyRules(chain ChainReader, parent uint64) *params.NUCDifficultyRules {
+	return chain.Config().NUCDifficultyRules(new(big.Int).SetUint64(parent + 1))
+}
+
 func CheckNUCVersion(version uint32) bool {
 	if version == BlockVersion {
 		return true
@@ -26,16 +42,16 @@ func CheckNUCVersion(version uint32) bool {
 func GetNUCDifficultyByTxCount(node_diff big.Int, chain ChainReader, headerHash common.Hash, number uint64, minerAddr common.Address, minerTxCount uint64) (nucdiff *big.Int, minerRecentTxCount uint64) {
 	currentDiff := &big.Int{}
 	currentDiff.Set(&node_diff)
+	rules := nucDifficultyRules(chain, number)
 	//need calculate the block count
-	needReduceDiffTxCount := uint64(10)
+	needReduceDiffTxCount := rules.TxCount
 	minerRecentTxCount = minerTxCount
 	if minerRecentTxCount <= 0 {
 		//if minerTxCount is header verify
 		minerRecentTxCount = GetMinerRecentTxCount(chain, headerHash, number, minerAddr)
 	}
 	if minerRecentTxCount >= needReduceDiffTxCount {
-		diff := currentDiff.Div(currentDiff, big.NewInt(2))
-		return diff, minerRecentTxCount
+		return NUCDifficultyDiscount(currentDiff, rules.TxDivisor), minerRecentTxCount
 	}
 	return currentDiff, minerRecentTxCount
 }
@@ -44,9 +60,10 @@ func GetNUCDifficultyByTxCount(node_diff big.Int, chain ChainReader, headerHash
 // currentCount > 0 is block verify
 // currentCount <= 0 is mining new block
 func GetMinerRecentTxCount(chain ChainReader, headerHash common.Hash, number uint64, minerAddr common.Address) uint64 {
+	rules := nucDifficultyRules(chain, number)
 	//need calculate the block count
-	needReduceDiffTxCount := uint64(10)
-	needCalcBlocksCount := 5
+	needReduceDiffTxCount := rules.TxCount
+	needCalcBlocksCount := int(rules.TxBlocks) - 1
 	minerRecentTxCount := uint64(0)
 	i := 0
 	for {
@@ -64,6 +81,9 @@ func GetMinerRecentTxCount(chain ChainReader, headerHash common.Hash, number uin
 			break
 		}
 		minerRecentTxCount += uint64(types.MinerTxCount(chain.ChainConfig(), minerAddr, b.Transactions()))
+		if number == 0 {
+			break
+		}
 		// find parent
 		headerHash = b.Header().ParentHash
 		number = b.Header().Number.Uint64() - 1
@@ -73,8 +93,8 @@ func GetMinerRecentTxCount(chain ChainReader, headerHash common.Hash, number uin
 }
 
 // get NUCDifficulty by miner money
-// if balance more than 1000 NUC
-// nucdifficulty = difficulty / 2
+// if balance more than the balance of a tier (1000 NUC by default)
+// nucdifficulty = difficulty / the divisor of the highest such tier
 func GetNUCDifficultyByMinerAccount(node_diff big.Int, minerAddr common.Address, chain ChainReader, parentHash common.Hash, number uint64) *big.Int {
 	currentDiff := &big.Int{}
 	currentDiff.Set(&node_diff)
@@ -87,13 +107,15 @@ func GetNUCDifficultyByMinerAccount(node_diff big.Int, minerAddr common.Address,
 	if err != nil {
 		return currentDiff
 	}
-	needReduceDiffBalance := uint64(1000)
-	oneNUC := big.NewInt(1000000000000000000)
-	balance := stateDb.GetBalance(minerAddr)
-	balance = balance.Div(balance, oneNUC)
-	if balance.Uint64() > needReduceDiffBalance {
-		diff := currentDiff.Div(currentDiff, big.NewInt(2))
-		return diff
+	if tier := nucBalanceTier(nucDifficultyRules(chain, number), stateDb.GetBalance(minerAddr)); tier != nil {
+		return NUCDifficultyDiscount(currentDiff, tier.Divisor)
 	}
 	return currentDiff
 }
+
+// nucBalanceTier returns the balance discount tier reached by a balance in wei,
+// or nil if none is.
+func nucBalanceTier(rules *params.NUCDifficultyRules, balance *big.Int) *params.NUCBalanceTier {
+	whole := new(big.Int).Div(balance, nucUnit)
+	return rules.BalanceTier(whole.Uint64())
+}
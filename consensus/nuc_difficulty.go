@@ -0,0 +1,114 @@
+// Copyright 2019 The nuc Team
+
+package consensus
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NUC difficulty discount rules, as named in a NUCDifficultyBreakdown.
+const (
+	NUCTxCountRule = "txCount"
+	NUCBalanceRule = "balance"
+)
+
+// NUCDifficultyFactor is a NUC difficulty discount rule as evaluated for a block.
+type NUCDifficultyFactor struct {
+	Rule      string   // NUCTxCountRule or NUCBalanceRule
+	Enforced  bool     // Whether the chain config enforces the rule at the block
+	Qualified bool     // Whether the coinbase qualifies for the discount
+	Applied   bool     // Whether the discount is part of the NUC difficulty
+	Value     *big.Int // Recent tx count, or balance at the parent in wei
+	Threshold *big.Int // Lowest value qualifying for the discount
+	Divisor   uint64   // Divisor of the discount
+	Blocks    uint64   // Blocks the transactions are counted in, tx count rule only
+}
+
+// NUCDifficultyBreakdown explains how the NUC difficulty of a block derives from
+// its difficulty, applying the tx count rule followed by the balance rule.
+type NUCDifficultyBreakdown struct {
+	Difficulty    *big.Int
+	Factors       []*NUCDifficultyFactor
+	NUCDifficulty *big.Int
+}
+
+// ExplainNUCDifficulty evaluates the NUC difficulty discount rules for the given
+// header, parentState being the post state of its parent.
+//
+// If the header has a NUC difficulty, the discounts are those making up for it,
+// preferring the ones the coinbase qualifies for when a rule isn't enforced yet.
+// Otherwise the NUC difficulty is projected, every qualifying discount applying
+// as when mining. The recent transactions are only counted up to the threshold.
+//
+// The balance rule is reported with the highest tier reached, or the lowest one
+// if none is. A chain config without balance tiers reports a divisor of 1.
+func ExplainNUCDifficulty(chain ChainReader, header *types.Header, parentState *state.StateDB) *NUCDifficultyBreakdown {
+	var (
+		config  = chain.Config()
+		number  = header.Number.Uint64()
+		rules   = config.NUCDifficultyRules(header.Number)
+		balance = parentState.GetBalance(header.Coinbase)
+	)
+	txCount := GetMinerRecentTxCount(chain, header.ParentHash, number-1, header.Coinbase)
+	txFactor := &NUCDifficultyFactor{
+		Rule:      NUCTxCountRule,
+		Enforced:  config.IsNUCTxCountDiscount(header.Number),
+		Qualified: txCount >= rules.TxCount,
+		Value:     new(big.Int).SetUint64(txCount),
+		Threshold: new(big.Int).SetUint64(rules.TxCount),
+		Divisor:   rules.TxDivisor,
+		Blocks:    rules.TxBlocks,
+	}
+	balanceFactor := &NUCDifficultyFactor{
+		Rule:     NUCBalanceRule,
+		Enforced: config.IsNUCBalanceDiscount(header.Number),
+		Value:    new(big.Int).Set(balance),
+		Divisor:  1,
+	}
+	tier := nucBalanceTier(rules, balance)
+	if tier == nil && len(rules.BalanceTiers) > 0 {
+		tier = &rules.BalanceTiers[0]
+	} else if tier != nil {
+		balanceFactor.Qualified = true
+	}
+	if tier != nil {
+		// Exceeding the tier's whole NUC takes one more NUC
+		balanceFactor.Threshold = new(big.Int).Mul(new(big.Int).SetUint64(tier.Balance+1), nucUnit)
+		balanceFactor.Divisor = tier.Divisor
+	} else {
+		balanceFactor.Threshold = new(big.Int)
+	}
+	factors := []*NUCDifficultyFactor{txFactor, balanceFactor}
+	breakdown := &NUCDifficultyBreakdown{Difficulty: new(big.Int).Set(header.Difficulty), Factors: factors}
+
+	// Try the qualifying discounts first, then the ones of the rules not enforced
+	// yet in any combination
+	for mask := 0; mask < 1<<uint(len(factors)); mask++ {
+		diff, valid := new(big.Int).Set(header.Difficulty), true
+		for i, factor := range factors {
+			factor.Applied = factor.Qualified != (mask&(1<<uint(i)) != 0)
+			if factor.Applied != factor.Qualified && factor.Enforced {
+				valid = false
+			}
+			if factor.Applied {
+				diff = NUCDifficultyDiscount(diff, factor.Divisor)
+			}
+		}
+		if !valid {
+			continue
+		}
+		if header.NUCDifficulty == nil || diff.Cmp(header.NUCDifficulty) == 0 {
+			breakdown.NUCDifficulty = diff
+			return breakdown
+		}
+	}
+	// No combination matches, report the header as is with the discounts due
+	for _, factor := range factors {
+		factor.Applied = factor.Qualified
+	}
+	breakdown.NUCDifficulty = new(big.Int).Set(header.NUCDifficulty)
+	return breakdown
+}
@@ -70,6 +70,12 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		return consensus.ErrPrunedAncestor
 	}
+	// The NUC difficulty rules need the parent state, which is available now
+	if verifier, ok := v.engine.(consensus.NUCDifficultyVerifier); ok {
+		if err := verifier.VerifyNUCDifficulty(v.bc, header); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1582,19 +1582,6 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if !consensus.CheckNUCVersion(block.Header().Version) {
 			return it.index, events, coalescedLogs, ErrInvalidVersion
 		}
-		//check minerTxCount is correct or not
-		_, minerRecentTxCount := consensus.GetNUCDifficultyByTxCount(*block.Header().Difficulty, bc, block.Header().ParentHash, block.Header().Number.Uint64()-1, block.Header().Coinbase, 0)
-		// Verify the block's NUCdifficulty based in its difficulty
-		if minerRecentTxCount <= 0 {
-			//header verify
-			minerRecentTxCount = 1
-		}
-		// expectedNUCDiff, _ := consensus.GetNUCDifficultyByTxCount(*block.Header().Difficulty, bc, block.Header().ParentHash, block.Header().Number.Uint64()-1, block.Header().Coinbase, minerRecentTxCount)
-		// fmt.Println("# =================expectedNUCDiff===============", expectedNUCDiff)
-		// expectedNUCDiff = consensus.GetNUCDifficultyByMinerAccount(*expectedNUCDiff, block.Header().Coinbase, bc, block.Header().ParentHash, block.Header().Number.Uint64()-1)
-		// if expectedNUCDiff.Cmp(block.Header().NUCDifficulty) != 0 {
-		// 	return it.index, events, coalescedLogs, errors.New(fmt.Sprintf("# invalid NUCDifficulty: have %v, want %v", block.Header().NUCDifficulty, expectedNUCDiff))
-		// }
 		// If the block is known (in the middle of the chain), it's a special case for
 		// Clique blocks where they can share state among each other, so importing an
 		// older block might complete the state of the subsequent one. In this case,
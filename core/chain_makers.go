@@ -209,6 +209,9 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(b.header.Number) == 0 {
 			misc.ApplyDAOHardFork(statedb)
 		}
+		if upgrade := config.NUCRuleUpgrade(b.header.Number); upgrade != nil {
+			misc.ApplyNUCRuleUpgrade(statedb, upgrade)
+		}
 		// Execute any user modifications to the block
 		if gen != nil {
 			gen(i, b)
@@ -251,6 +254,7 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 	}
 
 	return &types.Header{
+		Version:    consensus.BlockVersion,
 		Root:       state.IntermediateRoot(chain.Config().IsEIP158(parent.Number())),
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
@@ -308,3 +312,17 @@ func (cr *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.Hea
 func (cr *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
 func (cr *fakeChainReader) StateAt(root common.Hash) (*state.StateDB, error)   { return nil,nil }
 func (bc *fakeChainReader) ChainConfig() *params.ChainConfig { return bc.config }
+
+// Engine implements ChainContext. System calls name the block's coinbase as the
+// author, so no engine is ever needed.
+func (cr *fakeChainReader) Engine() consensus.Engine { return nil }
+
+// SystemCall implements consensus.SystemCaller.
+func (cr *fakeChainReader) SystemCall(header *types.Header, statedb *state.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, error) {
+	return SystemCall(cr.config, cr, header, statedb, vm.Config{}, from, to, input, gas)
+}
+
+// ApplySystemMessage implements consensus.SystemCaller.
+func (cr *fakeChainReader) ApplySystemMessage(header *types.Header, statedb vm.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, bool, error) {
+	return ApplySystemMessage(cr.config, cr, header, statedb, vm.Config{}, from, to, input, gas)
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"fmt"
 	"math/big"
+	"testing"
 
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -98,3 +99,33 @@ func ExampleGenerateChain() {
 	// balance of addr2: 10000
 	// balance of addr3: 19687500000000001000
 }
+
+// Tests that chains can be generated under every NUC reward algorithm, which
+// need to call the rule contract without a full blockchain at hand, and that a
+// blockchain importing them arrives at the same state.
+func TestGenerateNUCChain(t *testing.T) {
+	for _, version := range []uint64{params.NUCRewardV1, params.NUCRewardV2, params.NUCRewardV3, params.NUCRewardV4} {
+		config := *params.TestChainConfig
+		config.NUC = &params.NUCConfig{
+			RewardRules: []params.NUCRewardRule{{Block: big.NewInt(0), Version: version}},
+		}
+		var (
+			db      = rawdb.NewMemoryDatabase()
+			gspec   = &Genesis{Config: &config}
+			genesis = gspec.MustCommit(db)
+		)
+		blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), db, 3, nil)
+
+		chain, err := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("v%d: failed to create chain: %v", version, err)
+		}
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("v%d: failed to insert generated chain: %v", version, err)
+		}
+		if head := chain.CurrentBlock(); head.Hash() != blocks[len(blocks)-1].Hash() {
+			t.Errorf("v%d: head mismatch: have %x, want %x", version, head.Hash(), blocks[len(blocks)-1].Hash())
+		}
+		chain.Stop()
+	}
+}
@@ -1,15 +1,30 @@
 package core
 
 import (
-	`bytes`
-	`github.com/ethereum/go-ethereum/common`
-	`github.com/ethereum/go-ethereum/core/vm`
-	`math/big`
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 var (
-	ContractTypeDefault = []byte{0x0,0x0}
-	ContractTypeIPFS = []byte{0x0,0x1}
+	ContractTypeDefault = []byte{0x0, 0x0}
+	ContractTypeIPFS    = []byte{0x0, 0x1}
+)
+
+var (
+	// ErrUnknownContractKind is returned if the creation data of a contract
+	// doesn't start with the prefix of any registered contract kind.
+	ErrUnknownContractKind = errors.New("unknown contract kind")
+
+	// ErrInactiveContractKind is returned if the creation data of a contract
+	// selects a contract kind not active yet at the current block.
+	ErrInactiveContractKind = errors.New("contract kind not active")
 )
 
 type ContractObject interface {
@@ -21,32 +36,101 @@ type ContractBase struct {
 	Evm *vm.EVM
 }
 
-func (this *ContractBase)SetEVM(evm *vm.EVM) {
+func (this *ContractBase) SetEVM(evm *vm.EVM) {
 	this.Evm = evm
 }
 
 type ContractDefault struct {
 	ContractBase
 }
+
 //default contract
-func (this *ContractDefault)Create(caller vm.ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
-	return this.Evm.Create(caller,code,gas,value)
+func (this *ContractDefault) Create(caller vm.ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	return this.Evm.Create(caller, code, gas, value)
 }
 
-
 type ContractIPFS struct {
 	ContractBase
 }
+
 //ipfs contract
-func (this *ContractIPFS)Create(caller vm.ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
-	return this.Evm.Create3(caller,code,gas,value)
+func (this *ContractIPFS) Create(caller vm.ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	return this.Evm.Create3(caller, code, gas, value)
+}
+
+// ContractKind is a kind of contract deployment, selected by the leading bytes
+// of the contract creation data.
+type ContractKind struct {
+	Name   string
+	Prefix []byte // Leading bytes of the creation data selecting the kind
+
+	// Active reports whether the kind can be deployed at the given block, nil
+	// if it always could.
+	Active func(config *params.ChainConfig, num *big.Int) bool
+
+	// Gas returns the gas charged for deploying the given creation data on top
+	// of the intrinsic gas, nil if there's none.
+	Gas func(data []byte) (uint64, error)
+
+	// New creates the handler deploying the contract.
+	New func() ContractObject
 }
 
-func GetContractInstance( data []byte ) (obj ContractObject) {
-	if bytes.HasPrefix(data, ContractTypeIPFS){
-		return &ContractIPFS{}
-	} else if bytes.HasPrefix(data, ContractTypeDefault){
-		return &ContractDefault{}
+var (
+	contractKinds     []*ContractKind
+	contractKindsLock sync.RWMutex
+)
+
+func init() {
+	RegisterContractKind(&ContractKind{
+		Name:   "default",
+		Prefix: ContractTypeDefault,
+		New:    func() ContractObject { return new(ContractDefault) },
+	})
+	RegisterContractKind(&ContractKind{
+		Name:   "ipfs",
+		Prefix: ContractTypeIPFS,
+		New:    func() ContractObject { return new(ContractIPFS) },
+	})
+}
+
+// RegisterContractKind adds a kind of contract deployment to the registry. The
+// prefix of the kind may neither be a prefix of a registered one, nor the other
+// way around, so the creation data always selects a single kind. Kinds must be
+// registered before any block is processed, as they are part of consensus.
+func RegisterContractKind(kind *ContractKind) error {
+	if len(kind.Prefix) == 0 {
+		return fmt.Errorf("contract kind %q has no prefix", kind.Name)
+	}
+	if kind.New == nil {
+		return fmt.Errorf("contract kind %q has no creation handler", kind.Name)
 	}
+	contractKindsLock.Lock()
+	defer contractKindsLock.Unlock()
+
+	for _, registered := range contractKinds {
+		if bytes.HasPrefix(kind.Prefix, registered.Prefix) || bytes.HasPrefix(registered.Prefix, kind.Prefix) {
+			return fmt.Errorf("contract kind %q prefix %x conflicts with %q prefix %x", kind.Name, kind.Prefix, registered.Name, registered.Prefix)
+		}
+	}
+	contractKinds = append(contractKinds, kind)
 	return nil
-}
\ No newline at end of file
+}
+
+// LookupContractKind returns the kind of contract deployment selected by the
+// given creation data at the given block.
+func LookupContractKind(config *params.ChainConfig, num *big.Int, data []byte) (*ContractKind, error) {
+	contractKindsLock.RLock()
+	defer contractKindsLock.RUnlock()
+
+	for _, kind := range contractKinds {
+		if !bytes.HasPrefix(data, kind.Prefix) {
+			continue
+		}
+		if kind.Active != nil && !kind.Active(config, num) {
+			return nil, ErrInactiveContractKind
+		}
+		return kind, nil
+	}
+	return nil, ErrUnknownContractKind
+}
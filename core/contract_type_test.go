@@ -0,0 +1,179 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// registerTestContractKind registers a contract kind for a test, returning the
+// function removing it again.
+func registerTestContractKind(t *testing.T, kind *ContractKind) func() {
+	t.Helper()
+
+	if err := RegisterContractKind(kind); err != nil {
+		t.Fatalf("failed to register contract kind: %v", err)
+	}
+	return func() {
+		contractKindsLock.Lock()
+		defer contractKindsLock.Unlock()
+
+		for i, registered := range contractKinds {
+			if registered == kind {
+				contractKinds = append(contractKinds[:i], contractKinds[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Tests that creation data selects the contract kind of its prefix, if active.
+func TestLookupContractKind(t *testing.T) {
+	config := &params.ChainConfig{NUC: &params.NUCConfig{ContractKindFailureBlock: big.NewInt(10)}}
+	defer registerTestContractKind(t, &ContractKind{
+		Name:   "test",
+		Prefix: []byte{0x0, 0x7f},
+		Active: func(config *params.ChainConfig, num *big.Int) bool { return config.IsNUCContractKindFailure(num) },
+		New:    func() ContractObject { return new(ContractDefault) },
+	})()
+	tests := []struct {
+		data   []byte
+		number int64
+		kind   string
+		err    error
+	}{
+		{append(ContractTypeDefault, 0x60), 0, "default", nil},
+		{append(ContractTypeIPFS, 0x60), 0, "ipfs", nil},
+		{[]byte{0x0, 0x7f, 0x60}, 9, "", ErrInactiveContractKind},
+		{[]byte{0x0, 0x7f, 0x60}, 10, "test", nil},
+		{[]byte{0x0, 0x02, 0x60}, 0, "", ErrUnknownContractKind},
+		{[]byte{0x0}, 0, "", ErrUnknownContractKind},
+		{nil, 0, "", ErrUnknownContractKind},
+	}
+	for i, tt := range tests {
+		kind, err := LookupContractKind(config, big.NewInt(tt.number), tt.data)
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err == nil && kind.Name != tt.kind {
+			t.Errorf("test %d: kind mismatch: have %s, want %s", i, kind.Name, tt.kind)
+		}
+	}
+}
+
+// Tests that contract kinds with conflicting or missing prefixes are rejected.
+func TestRegisterContractKind(t *testing.T) {
+	create := func() ContractObject { return new(ContractDefault) }
+	tests := []*ContractKind{
+		{Name: "empty", New: create},
+		{Name: "nohandler", Prefix: []byte{0x0, 0x7f}},
+		{Name: "duplicate", Prefix: ContractTypeIPFS, New: create},
+		{Name: "shorter", Prefix: []byte{0x0}, New: create},
+		{Name: "longer", Prefix: []byte{0x0, 0x1, 0x1}, New: create},
+	}
+	for _, kind := range tests {
+		if err := RegisterContractKind(kind); err == nil {
+			t.Errorf("contract kind %q accepted", kind.Name)
+		}
+	}
+}
+
+// Tests that creations of rejected contract kinds are invalid transactions until
+// the failure fork, and failed ones consuming all their gas afterwards. The gas
+// of a contract kind is charged on top of the intrinsic gas.
+func TestContractKindTransition(t *testing.T) {
+	var (
+		sender   = common.Address{0x01}
+		coinbase = common.Address{0x02}
+		config   = *params.TestChainConfig
+	)
+	config.NUC = &params.NUCConfig{ContractKindFailureBlock: big.NewInt(10)}
+
+	defer registerTestContractKind(t, &ContractKind{
+		Name:   "expensive",
+		Prefix: []byte{0x0, 0x7e},
+		Gas:    func(data []byte) (uint64, error) { return 100000, nil },
+		New:    func() ContractObject { return new(ContractDefault) },
+	})()
+	apply := func(number int64, data []byte, gas uint64) (*state.StateDB, uint64, bool, error) {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.SetBalance(sender, big.NewInt(params.Ether))
+
+		header := &types.Header{Number: big.NewInt(number), GasLimit: 10000000, Difficulty: big.NewInt(1), NUCDifficulty: big.NewInt(1), Time: 1}
+		msg := types.NewMessage(sender, nil, 0, new(big.Int), gas, big.NewInt(1), data, true)
+		evm := vm.NewEVM(NewEVMContext(msg, header, nil, &coinbase), statedb, &config, vm.Config{})
+
+		_, used, failed, err := ApplyMessage(evm, msg, new(GasPool).AddGas(header.GasLimit))
+		return statedb, used, failed, err
+	}
+	unknown := []byte{0x0, 0x02, 0x60}
+
+	// Before the fork, rejected creations invalidate the transaction
+	if _, _, _, err := apply(9, unknown, 1000000); err != ErrUnknownContractKind {
+		t.Fatalf("pre-fork error mismatch: have %v, want %v", err, ErrUnknownContractKind)
+	}
+	// After the fork, they fail consuming all the gas and bumping the nonce
+	statedb, used, failed, err := apply(10, unknown, 1000000)
+	if err != nil {
+		t.Fatalf("post-fork creation rejected: %v", err)
+	}
+	if !failed || used != 1000000 {
+		t.Errorf("post-fork outcome mismatch: have failed %v used %d, want failed %v used %d", failed, used, true, 1000000)
+	}
+	if nonce := statedb.GetNonce(sender); nonce != 1 {
+		t.Errorf("nonce mismatch: have %d, want %d", nonce, 1)
+	}
+	// The gas of the contract kind is charged on top of the intrinsic gas
+	data := []byte{0x0, 0x7e, 0x00}
+	intrinsic, _ := IntrinsicGas(data, true, true, true)
+	if _, used, _, err = apply(0, data, 1000000); err != nil {
+		t.Fatalf("failed to create expensive contract: %v", err)
+	}
+	if want := intrinsic + 100000; used != want {
+		t.Errorf("gas used mismatch: have %d, want %d", used, want)
+	}
+	// Running out of the contract kind gas is rejected like any other kind error
+	if _, _, _, err := apply(0, data, intrinsic+99999); err != vm.ErrOutOfGas {
+		t.Fatalf("out of gas error mismatch: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+}
+
+// Tests that the transaction pool rejects creations of unknown or inactive
+// contract kinds with the reason, as mined ones may only fail.
+func TestContractKindTxPool(t *testing.T) {
+	defer registerTestContractKind(t, &ContractKind{
+		Name:   "future",
+		Prefix: []byte{0x0, 0x7d},
+		Active: func(config *params.ChainConfig, num *big.Int) bool { return num.Cmp(big.NewInt(10)) >= 0 },
+		New:    func() ContractObject { return new(ContractDefault) },
+	})()
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	tests := []struct {
+		data []byte
+		err  error
+	}{
+		{[]byte{0x0, 0x02, 0x60}, ErrUnknownContractKind},
+		{[]byte{0x0, 0x7d, 0x60}, ErrInactiveContractKind},
+		{append(ContractTypeDefault, 0x60), nil},
+	}
+	for i, tt := range tests {
+		tx, _ := types.SignTx(types.NewContractCreation(0, new(big.Int), 100000, big.NewInt(1), tt.data), types.HomesteadSigner{}, key)
+		if err := pool.AddRemote(tx); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// dataContractIndexHeadChanSize is the size of channel listening to ChainHeadEvent.
+const dataContractIndexHeadChanSize = 10
+
+// DataContractIndex indexes the IPFS data contracts created by the canonical
+// chain into the chain database, by address, by the multihash of their payload
+// and by creator. Like the reward ledger, the index follows the chain head across
+// reorgs and rewinds.
+type DataContractIndex struct {
+	db    ethdb.Database
+	chain *BlockChain
+
+	headCh  chan ChainHeadEvent
+	headSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDataContractIndex creates a data contract index on top of the chain's
+// database and starts indexing from the current head.
+func NewDataContractIndex(db ethdb.Database, chain *BlockChain) *DataContractIndex {
+	index := &DataContractIndex{
+		db:     db,
+		chain:  chain,
+		headCh: make(chan ChainHeadEvent, dataContractIndexHeadChanSize),
+		quit:   make(chan struct{}),
+	}
+	index.headSub = chain.SubscribeChainHeadEvent(index.headCh)
+
+	index.wg.Add(1)
+	go index.loop()
+	return index
+}
+
+// Stop terminates the indexing and waits for the pending writes to finish.
+func (i *DataContractIndex) Stop() {
+	i.headSub.Unsubscribe()
+	close(i.quit)
+	i.wg.Wait()
+}
+
+// loop keeps the index in sync with the chain until stopped.
+func (i *DataContractIndex) loop() {
+	defer i.wg.Done()
+
+	i.indexHead(i.chain.CurrentBlock().Header())
+	for {
+		select {
+		case ev := <-i.headCh:
+			i.indexHead(ev.Block.Header())
+
+		case <-i.headSub.Err():
+			return
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// indexHead makes the index match the chain ending in head. Blocks above the head
+// left over from a reorged out or rewound chain are unindexed, and the blocks
+// since the last common ancestor are (re)indexed in ascending order. All stale
+// blocks are unindexed first, as the same contract address may be created by a
+// different block on either chain. Indexing stops at the first block whose
+// receipts aren't available yet.
+func (i *DataContractIndex) indexHead(head *types.Header) {
+	var (
+		batch  = i.db.NewBatch()
+		number = head.Number.Uint64()
+	)
+	for n := number + 1; ; n++ {
+		entry := rawdb.ReadCanonicalDataContracts(i.db, n)
+		if entry == nil {
+			break
+		}
+		i.unindexBlock(batch, entry, n)
+	}
+	// Find the first block whose indexed contracts don't match the canonical chain
+	first := number + 1
+	for n := number; ; n-- {
+		if entry := rawdb.ReadCanonicalDataContracts(i.db, n); entry != nil && entry.BlockHash == rawdb.ReadCanonicalHash(i.db, n) {
+			break
+		}
+		first = n
+		if n == 0 {
+			break
+		}
+	}
+	for n := first; n <= number; n++ {
+		if entry := rawdb.ReadCanonicalDataContracts(i.db, n); entry != nil {
+			i.unindexBlock(batch, entry, n)
+		}
+	}
+	indexed := first
+	for n := first; n <= number; n++ {
+		hash := head.Hash()
+		if n != number {
+			hash = rawdb.ReadCanonicalHash(i.db, n)
+		}
+		block := rawdb.ReadBlock(i.db, hash, n)
+		if block == nil {
+			log.Warn("Missing block for data contract index", "number", n)
+			break
+		}
+		receipts := rawdb.ReadReceipts(i.db, hash, n, i.chain.Config())
+		if len(receipts) != len(block.Transactions()) {
+			log.Debug("Data contracts not indexable yet", "number", n, "hash", hash)
+			break
+		}
+		i.indexBlock(batch, block, receipts)
+		indexed = n + 1
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to write data contract index", "err", err)
+			}
+			batch.Reset()
+
+			select {
+			case <-i.quit:
+				return
+			default:
+			}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write data contract index", "err", err)
+	}
+	if first < indexed {
+		log.Debug("Indexed data contracts", "from", first, "to", indexed-1)
+	}
+}
+
+// indexBlock stores the data contracts successfully created by a canonical block.
+func (i *DataContractIndex) indexBlock(batch ethdb.Batch, block *types.Block, receipts types.Receipts) {
+	var (
+		entry  = &rawdb.CanonicalDataContracts{BlockHash: block.Hash()}
+		signer = types.MakeSigner(i.chain.Config(), block.Number())
+	)
+	for j, tx := range block.Transactions() {
+		if tx.To() != nil || receipts[j].Status != types.ReceiptStatusSuccessful || !bytes.HasPrefix(tx.Data(), ContractTypeIPFS) {
+			continue
+		}
+		creator, err := types.Sender(signer, tx)
+		if err != nil {
+			log.Warn("Invalid data contract creator", "number", block.NumberU64(), "tx", tx.Hash(), "err", err)
+			continue
+		}
+		payload := tx.Data()[len(ContractTypeIPFS):]
+		rawdb.WriteDataContract(batch, &rawdb.DataContract{
+			Address:     receipts[j].ContractAddress,
+			Creator:     creator,
+			TxHash:      tx.Hash(),
+			BlockHash:   block.Hash(),
+			BlockNumber: block.NumberU64(),
+			Size:        uint64(len(payload)),
+			Multihash:   types.DataMultihash(payload),
+		})
+		entry.Contracts = append(entry.Contracts, receipts[j].ContractAddress)
+	}
+	rawdb.WriteCanonicalDataContracts(batch, block.NumberU64(), entry)
+}
+
+// unindexBlock drops the data contracts of a block that's no longer canonical.
+func (i *DataContractIndex) unindexBlock(batch ethdb.Batch, entry *rawdb.CanonicalDataContracts, number uint64) {
+	for _, address := range entry.Contracts {
+		if contract := rawdb.ReadDataContract(i.db, address); contract != nil && contract.BlockHash == entry.BlockHash {
+			rawdb.DeleteDataContract(batch, contract)
+		}
+	}
+	rawdb.DeleteCanonicalDataContracts(batch, number)
+}
+
+// DataContractPayload returns the payload stored by an IPFS data contract, given
+// the code of its account, or nil if the code isn't a data contract's.
+func DataContractPayload(code []byte) []byte {
+	if !bytes.HasPrefix(code, ContractTypeIPFS) {
+		return nil
+	}
+	return code[len(ContractTypeIPFS):]
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// checkDataContractIndex verifies that the index holds exactly the given data
+// contracts, mapped to their payloads, and that every canonical block up to the
+// head is indexed with nothing above it.
+func checkDataContractIndex(t *testing.T, db ethdb.Database, chain *BlockChain, creator common.Address, want map[common.Address][]byte) {
+	t.Helper()
+
+	head := chain.CurrentBlock().NumberU64()
+	for n := uint64(0); n <= head; n++ {
+		entry := rawdb.ReadCanonicalDataContracts(db, n)
+		if entry == nil || entry.BlockHash != rawdb.ReadCanonicalHash(db, n) {
+			t.Fatalf("block %d: canonical data contracts mismatch: have %+v", n, entry)
+		}
+	}
+	if entry := rawdb.ReadCanonicalDataContracts(db, head+1); entry != nil {
+		t.Fatalf("stale data contracts above head: %+v", entry)
+	}
+	var listed []common.Address
+	for address, payload := range want {
+		contract := rawdb.ReadDataContract(db, address)
+		if contract == nil {
+			t.Fatalf("%x: data contract not indexed", address)
+		}
+		if contract.BlockHash != rawdb.ReadCanonicalHash(db, contract.BlockNumber) {
+			t.Fatalf("%x: data contract of non canonical block %d", address, contract.BlockNumber)
+		}
+		if contract.Creator != creator || contract.Size != uint64(len(payload)) || !bytes.Equal(contract.Multihash, types.DataMultihash(payload)) {
+			t.Fatalf("%x: data contract mismatch: have %+v", address, contract)
+		}
+		found := false
+		for _, indexed := range rawdb.ReadDataContractsByCID(db, contract.Multihash, 0) {
+			found = found || indexed == address
+		}
+		if !found {
+			t.Fatalf("%x: data contract not indexed by CID", address)
+		}
+		listed = append(listed, address)
+	}
+	if have := rawdb.ReadCreatorDataContracts(db, creator, 0); len(have) != len(listed) {
+		t.Fatalf("creator index mismatch: have %x, want %x", have, listed)
+	}
+}
+
+// Tests that the data contract index follows the canonical chain across reorgs
+// and rewinds, skipping failed creations.
+func TestDataContractIndex(t *testing.T) {
+	// Data contracts are only stored and charged for from the data contract gas fork
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{DataContractGasBlock: new(big.Int)}
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{Config: &config, Alloc: GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.HomesteadSigner{}
+	)
+	// store creates a data contract with the given payload, failing it by running
+	// short of the storage gas if requested
+	store := func(b *BlockGen, payload []byte, fail bool) common.Address {
+		data := append(append([]byte{}, ContractTypeIPFS...), payload...)
+		gas, _ := IntrinsicGas(data, true, true, true)
+		gas += uint64(len(data)) * params.CreateDataGas
+		if fail {
+			gas--
+		}
+		nonce := b.TxNonce(address)
+		tx, _ := types.SignTx(types.NewContractCreation(nonce, new(big.Int), gas, big.NewInt(1), data), signer, key)
+		b.AddTx(tx)
+		return crypto.CreateAddress(address, nonce)
+	}
+	var (
+		alpha = []byte("alpha")
+		beta  = []byte("beta")
+		gamma = []byte("gamma")
+
+		first, second, replaced common.Address
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 4, func(i int, b *BlockGen) {
+		switch i {
+		case 0:
+			first = store(b, alpha, false)
+		case 1:
+			second = store(b, beta, false)
+		case 2:
+			store(b, gamma, true)
+		case 3:
+			store(b, alpha, false)
+		}
+	})
+	// The fork creates a different contract at the address of beta
+	fork, _ := GenerateChain(gspec.Config, blocks[0], ethash.NewFaker(), db, 5, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0x02})
+		if i == 1 {
+			replaced = store(b, gamma, false)
+		}
+	})
+	if replaced != second {
+		t.Fatalf("fork doesn't reuse the address of beta: have %x, want %x", replaced, second)
+	}
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	index := &DataContractIndex{db: db, chain: chain, quit: make(chan struct{})}
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	index.indexHead(chain.CurrentBlock().Header())
+	checkDataContractIndex(t, db, chain, address, map[common.Address][]byte{
+		first:                            alpha,
+		second:                           beta,
+		crypto.CreateAddress(address, 3): alpha,
+	})
+	// Both alpha contracts share a CID, the oldest is listed first
+	if have := rawdb.ReadDataContractsByCID(db, types.DataMultihash(alpha), 0); len(have) != 2 || have[0] != first {
+		t.Fatalf("alpha CID index mismatch: have %x", have)
+	}
+	// Reorg to the fork, dropping beta in favour of gamma at the same address
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("fork didn't become canonical")
+	}
+	index.indexHead(chain.CurrentBlock().Header())
+	checkDataContractIndex(t, db, chain, address, map[common.Address][]byte{first: alpha, replaced: gamma})
+
+	if have := rawdb.ReadDataContractsByCID(db, types.DataMultihash(beta), 0); len(have) != 0 {
+		t.Fatalf("reorged out contract still indexed: %x", have)
+	}
+	// Rewinding the chain must drop the contracts above the new head
+	chain.SetHead(1)
+	index.indexHead(chain.CurrentBlock().Header())
+	checkDataContractIndex(t, db, chain, address, map[common.Address][]byte{first: alpha})
+}
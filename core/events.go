@@ -32,6 +32,13 @@ type PendingLogsEvent struct {
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
+// PendingRewardsEvent is posted when the miner commits a new block for sealing,
+// with the rewards the block would credit.
+type PendingRewardsEvent struct {
+	Block   *types.Block
+	Rewards *types.BlockRewards
+}
+
 // RemovedLogsEvent is posted when a reorg happens
 type RemovedLogsEvent struct{ Logs []*types.Log }
 
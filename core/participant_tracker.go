@@ -0,0 +1,302 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// participantTrackerHeadChanSize is the size of channel listening to ChainHeadEvent.
+	participantTrackerHeadChanSize = 10
+
+	// participantTrackerMaxBlocks is the maximum number of blocks diffed on a head
+	// change. Larger jumps (e.g. while syncing) only move the baseline.
+	participantTrackerMaxBlocks = 128
+)
+
+// ParticipantChange is the kind of a change in the participants of the rule
+// contract.
+type ParticipantChange uint8
+
+const (
+	ParticipantJoined        ParticipantChange = iota // Registered, e.g. BuyPoc or BuyPow
+	ParticipantLeft                                   // Deregistered, e.g. a cancelled mortgage
+	ParticipantBound                                  // Bound to a pool
+	ParticipantUnbound                                // Unbound from a pool
+	ParticipantAmountChanged                          // Mortgaged or ticket balance changed
+)
+
+// String implements fmt.Stringer.
+func (c ParticipantChange) String() string {
+	switch c {
+	case ParticipantJoined:
+		return "joined"
+	case ParticipantLeft:
+		return "left"
+	case ParticipantBound:
+		return "bound"
+	case ParticipantUnbound:
+		return "unbound"
+	case ParticipantAmountChanged:
+		return "amount"
+	default:
+		return fmt.Sprintf("change(%d)", uint8(c))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c ParticipantChange) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// ParticipantChangeEvent is posted when the participants registered in the rule
+// contract change with a new canonical block. Changes undone by a reorg are
+// posted as the opposite change in the first block of the new chain.
+type ParticipantChangeEvent struct {
+	Kind      ParticipantChange
+	Role      types.ParticipantRole
+	Address   common.Address
+	Pool      common.Address // Pool bound to, or unbound from, zero if none
+	Amount    *big.Int       // Mortgaged or ticket balance, the last one if left
+	Block     uint64
+	BlockHash common.Hash
+}
+
+// participantRegistry fingerprints the account holding the participants, see
+// consensus.ParticipantReporter.
+type participantRegistry struct {
+	address common.Address
+	storage common.Hash // Storage root of the account, zero if missing
+	code    common.Hash // Code hash of the account, zero if missing
+}
+
+// participantKey identifies a participant: an account may register for several
+// roles.
+type participantKey struct {
+	role    types.ParticipantRole
+	address common.Address
+}
+
+// ParticipantTracker follows the participants of the rule contract along the
+// chain head, posting their changes block by block. The rule contract emits no
+// logs, so the changes are found by diffing the participants read from the post
+// state of the new canonical blocks, whose state is needed.
+//
+// Reading the participants is a full pass over the rule contract, linear in the
+// number of participants. It's only done for the blocks changing the storage or
+// the code of the registry account, which is a lookup of the account otherwise,
+// and for at most participantTrackerMaxBlocks blocks per head change.
+type ParticipantTracker struct {
+	chain    *BlockChain
+	reporter consensus.ParticipantReporter
+
+	head         *types.Header        // Block the participants were last read at
+	participants []*types.Participant // Participants as of the head
+	registry     participantRegistry  // Registry account the participants were read from
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+
+	headCh  chan ChainHeadEvent
+	headSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewParticipantTracker creates a participant tracker starting at the current
+// head. Nil is returned if the consensus engine has no participants.
+func NewParticipantTracker(chain *BlockChain) *ParticipantTracker {
+	reporter, ok := chain.Engine().(consensus.ParticipantReporter)
+	if !ok {
+		return nil
+	}
+	tracker := &ParticipantTracker{
+		chain:    chain,
+		reporter: reporter,
+		headCh:   make(chan ChainHeadEvent, participantTrackerHeadChanSize),
+		quit:     make(chan struct{}),
+	}
+	tracker.headSub = chain.SubscribeChainHeadEvent(tracker.headCh)
+
+	tracker.wg.Add(1)
+	go tracker.loop()
+	return tracker
+}
+
+// Stop terminates the tracking and closes all subscriptions.
+func (t *ParticipantTracker) Stop() {
+	t.headSub.Unsubscribe()
+	close(t.quit)
+	t.wg.Wait()
+	t.scope.Close()
+}
+
+// SubscribeParticipantChangeEvent registers a subscription of ParticipantChangeEvent.
+func (t *ParticipantTracker) SubscribeParticipantChangeEvent(ch chan<- ParticipantChangeEvent) event.Subscription {
+	return t.scope.Track(t.feed.Subscribe(ch))
+}
+
+// loop keeps tracking the chain head until stopped.
+func (t *ParticipantTracker) loop() {
+	defer t.wg.Done()
+
+	t.update(t.chain.CurrentBlock().Header())
+	for {
+		select {
+		case ev := <-t.headCh:
+			t.update(ev.Block.Header())
+
+		case <-t.headSub.Err():
+			return
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// update moves the tracker to a new head, posting the participant changes of
+// every block since the last common ancestor with the previous head.
+func (t *ParticipantTracker) update(head *types.Header) {
+	if t.head == nil {
+		t.rebase(head)
+		return
+	}
+	// Collect the new blocks back to the common ancestor of the previous head
+	var (
+		oldh    = t.head
+		newh    = head
+		headers []*types.Header
+	)
+	for oldh.Hash() != newh.Hash() {
+		if len(headers) > participantTrackerMaxBlocks {
+			log.Debug("Participant tracker skipping blocks", "from", t.head.Number, "to", head.Number)
+			t.rebase(head)
+			return
+		}
+		if oldh.Number.Uint64() >= newh.Number.Uint64() {
+			if oldh = t.chain.GetHeader(oldh.ParentHash, oldh.Number.Uint64()-1); oldh == nil {
+				t.rebase(head)
+				return
+			}
+		}
+		if oldh.Number.Uint64() < newh.Number.Uint64() {
+			headers = append(headers, newh)
+			if newh = t.chain.GetHeader(newh.ParentHash, newh.Number.Uint64()-1); newh == nil {
+				t.rebase(head)
+				return
+			}
+		}
+	}
+	for i := len(headers) - 1; i >= 0; i-- {
+		statedb, registry, err := t.registryAt(headers[i])
+		if err != nil {
+			log.Debug("Participants unavailable", "number", headers[i].Number, "hash", headers[i].Hash(), "err", err)
+			continue
+		}
+		if registry == t.registry {
+			continue // Nothing (de)registered, skip reading the contract
+		}
+		participants := t.reporter.Participants(t.chain, headers[i], statedb)
+		for _, ev := range diffParticipants(t.participants, participants, headers[i]) {
+			select {
+			case <-t.quit:
+				return
+			default:
+			}
+			t.feed.Send(ev)
+		}
+		t.participants, t.registry = participants, registry
+	}
+	t.head = head
+}
+
+// rebase makes the given block the baseline of the next changes, without
+// posting anything. If its participants can't be read, the next head becomes
+// the baseline instead.
+func (t *ParticipantTracker) rebase(head *types.Header) {
+	statedb, registry, err := t.registryAt(head)
+	if err != nil {
+		log.Debug("Participants unavailable", "number", head.Number, "hash", head.Hash(), "err", err)
+		t.head, t.participants, t.registry = nil, nil, participantRegistry{}
+		return
+	}
+	t.head, t.participants, t.registry = head, t.reporter.Participants(t.chain, head, statedb), registry
+}
+
+// registryAt opens the post state of a block, fingerprinting the account holding
+// the participants in it.
+func (t *ParticipantTracker) registryAt(header *types.Header) (*state.StateDB, participantRegistry, error) {
+	statedb, err := t.chain.StateAt(header.Root)
+	if err != nil {
+		return nil, participantRegistry{}, err
+	}
+	registry := participantRegistry{address: t.reporter.ParticipantRegistry(t.chain, header)}
+	if trie := statedb.StorageTrie(registry.address); trie != nil {
+		registry.storage = trie.Hash()
+		registry.code = statedb.GetCodeHash(registry.address)
+	}
+	return statedb, registry, nil
+}
+
+// diffParticipants returns the changes turning the old participants into the
+// new ones, posted for the given block. Rebinding a participant to another pool
+// results in an unbind from the old one followed by a bind to the new one.
+func diffParticipants(prevSet, nextSet []*types.Participant, header *types.Header) []ParticipantChangeEvent {
+	var (
+		events []ParticipantChangeEvent
+		known  = make(map[participantKey]*types.Participant, len(prevSet))
+		kept   = make(map[participantKey]bool, len(nextSet))
+	)
+	change := func(kind ParticipantChange, p *types.Participant, pool common.Address) {
+		events = append(events, ParticipantChangeEvent{
+			Kind:      kind,
+			Role:      p.Role,
+			Address:   p.Address,
+			Pool:      pool,
+			Amount:    new(big.Int).Set(p.Amount),
+			Block:     header.Number.Uint64(),
+			BlockHash: header.Hash(),
+		})
+	}
+	for _, p := range prevSet {
+		known[participantKey{p.Role, p.Address}] = p
+	}
+	for _, p := range nextSet {
+		key := participantKey{p.Role, p.Address}
+		kept[key] = true
+
+		prev, ok := known[key]
+		if !ok {
+			change(ParticipantJoined, p, p.Pool)
+			continue
+		}
+		if prev.Pool != p.Pool {
+			if prev.Pool != (common.Address{}) {
+				change(ParticipantUnbound, p, prev.Pool)
+			}
+			if p.Pool != (common.Address{}) {
+				change(ParticipantBound, p, p.Pool)
+			}
+		}
+		if prev.Amount.Cmp(p.Amount) != 0 {
+			change(ParticipantAmountChanged, p, p.Pool)
+		}
+	}
+	for _, p := range prevSet {
+		if !kept[participantKey{p.Role, p.Address}] {
+			change(ParticipantLeft, p, p.Pool)
+		}
+	}
+	return events
+}
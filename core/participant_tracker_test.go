@@ -0,0 +1,176 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// participantTestContract is the account whose storage holds the participants
+// of participantEngine.
+var participantTestContract = common.Address{0xff}
+
+// participantEngine is a fake consensus engine registering participants in the
+// storage of participantTestContract, as scripted per block at finalisation.
+type participantEngine struct {
+	consensus.Engine
+
+	candidates []participantKey                                 // Participants that may be registered
+	script     func(header *types.Header, state *state.StateDB) // Registrations done by a block
+	reads      int                                              // Number of times the participants were read
+}
+
+func (e *participantEngine) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) error {
+	e.script(header, state)
+	return e.Engine.Finalize(chain, header, state, txs, uncles)
+}
+
+func (e *participantEngine) FinalizeAndAssemble(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	e.script(header, state)
+	return e.Engine.FinalizeAndAssemble(chain, header, state, txs, uncles, receipts)
+}
+
+func (e *participantEngine) ParticipantRegistry(chain consensus.ChainReader, header *types.Header) common.Address {
+	return participantTestContract
+}
+
+func (e *participantEngine) Participants(chain consensus.ChainReader, header *types.Header, state *state.StateDB) []*types.Participant {
+	e.reads++
+
+	var participants []*types.Participant
+	for _, key := range e.candidates {
+		amountSlot, poolSlot := participantTestSlots(key.role, key.address)
+		if amount := state.GetState(participantTestContract, amountSlot).Big(); amount.Sign() > 0 {
+			pool := common.BytesToAddress(state.GetState(participantTestContract, poolSlot).Bytes())
+			participants = append(participants, &types.Participant{Role: key.role, Address: key.address, Pool: pool, Amount: amount})
+		}
+	}
+	return participants
+}
+
+// participantTestSlots returns the storage slots of a participant's amount and pool.
+func participantTestSlots(role types.ParticipantRole, address common.Address) (common.Hash, common.Hash) {
+	return crypto.Keccak256Hash([]byte{byte(role), 0}, address.Bytes()), crypto.Keccak256Hash([]byte{byte(role), 1}, address.Bytes())
+}
+
+// setTestParticipant registers a participant of participantEngine, or removes it
+// if the amount is zero.
+func setTestParticipant(state *state.StateDB, role types.ParticipantRole, address, pool common.Address, amount int64) {
+	amountSlot, poolSlot := participantTestSlots(role, address)
+	state.SetState(participantTestContract, amountSlot, common.BigToHash(big.NewInt(amount)))
+	state.SetState(participantTestContract, poolSlot, pool.Hash())
+}
+
+// Tests that the participant tracker posts the changes of every new canonical
+// block, undoing those of the blocks reorged out.
+func TestParticipantTracker(t *testing.T) {
+	var (
+		miner  = common.Address{0x01}
+		worker = common.Address{0x02}
+		pool   = common.Address{0x03}
+		forked = common.Address{0x04} // Coinbase of the fork
+	)
+	engine := &participantEngine{
+		Engine:     ethash.NewFaker(),
+		candidates: []participantKey{{types.PocParticipant, miner}, {types.PowParticipant, worker}},
+		script: func(header *types.Header, state *state.StateDB) {
+			switch number := header.Number.Uint64(); {
+			case number == 1:
+				setTestParticipant(state, types.PocParticipant, miner, common.Address{}, 100)
+				setTestParticipant(state, types.PowParticipant, worker, common.Address{}, 10)
+			case number == 2:
+				setTestParticipant(state, types.PowParticipant, worker, pool, 10)
+			case number == 3 && header.Coinbase == forked:
+				setTestParticipant(state, types.PowParticipant, worker, common.Address{}, 10)
+			case number == 3:
+				setTestParticipant(state, types.PocParticipant, miner, common.Address{}, 200)
+			case number == 4 && header.Coinbase != forked:
+				setTestParticipant(state, types.PocParticipant, miner, common.Address{}, 0)
+			}
+		},
+	}
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{participantTestContract: {Balance: new(big.Int), Nonce: 1}}, // Not empty, so EIP158 keeps its storage
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine, db, 4, func(i int, b *BlockGen) {})
+	fork, _ := GenerateChain(gspec.Config, blocks[1], engine, db, 3, func(i int, b *BlockGen) {
+		b.SetCoinbase(forked)
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	tracker := &ParticipantTracker{chain: chain, reporter: engine, quit: make(chan struct{})}
+	events := make(chan ParticipantChangeEvent, 16)
+	sub := tracker.SubscribeParticipantChangeEvent(events)
+	defer sub.Unsubscribe()
+
+	tracker.update(chain.CurrentBlock().Header())
+
+	check := func(want []ParticipantChangeEvent) {
+		t.Helper()
+
+		tracker.update(chain.CurrentBlock().Header())
+		for i, w := range want {
+			var have ParticipantChangeEvent
+			select {
+			case have = <-events:
+			default:
+				t.Fatalf("change %d: missing, want %+v", i, w)
+			}
+			w.BlockHash = chain.GetHeaderByNumber(w.Block).Hash()
+			if have.Kind != w.Kind || have.Role != w.Role || have.Address != w.Address || have.Pool != w.Pool ||
+				have.Amount.Cmp(w.Amount) != 0 || have.Block != w.Block || have.BlockHash != w.BlockHash {
+				t.Errorf("change %d: mismatch: have %+v, want %+v", i, have, w)
+			}
+		}
+		select {
+		case ev := <-events:
+			t.Fatalf("unexpected change: %+v", ev)
+		default:
+		}
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	check([]ParticipantChangeEvent{
+		{Kind: ParticipantJoined, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(100), Block: 1},
+		{Kind: ParticipantJoined, Role: types.PowParticipant, Address: worker, Amount: big.NewInt(10), Block: 1},
+		{Kind: ParticipantBound, Role: types.PowParticipant, Address: worker, Pool: pool, Amount: big.NewInt(10), Block: 2},
+		{Kind: ParticipantAmountChanged, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(200), Block: 3},
+		{Kind: ParticipantLeft, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(200), Block: 4},
+	})
+	// Reorg to the fork, where the miner never left and the worker unbinds
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("fork didn't become canonical")
+	}
+	check([]ParticipantChangeEvent{
+		{Kind: ParticipantJoined, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(100), Block: 3},
+		{Kind: ParticipantUnbound, Role: types.PowParticipant, Address: worker, Pool: pool, Amount: big.NewInt(10), Block: 3},
+	})
+	// The genesis, the 4 blocks and the first fork block changed the registry
+	if engine.reads != 6 {
+		t.Errorf("participant reads mismatch: have %d, want %d", engine.reads, 6)
+	}
+}
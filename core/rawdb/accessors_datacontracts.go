@@ -0,0 +1,145 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DataContract is the metadata of a canonical IPFS data contract, as stored in
+// the data contract index.
+type DataContract struct {
+	Address     common.Address `rlp:"-"` // Derived from the database key
+	Creator     common.Address
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Size        uint64 // Length of the payload, without the contract type prefix
+	Multihash   []byte // sha2-256 multihash of the payload
+}
+
+// CanonicalDataContracts is the list of data contracts created by a canonical
+// block, along with the hash of the block they were indexed for.
+type CanonicalDataContracts struct {
+	BlockHash common.Hash
+	Contracts []common.Address
+}
+
+// ReadDataContract retrieves the metadata of a canonical data contract, or nil if
+// the address isn't indexed.
+func ReadDataContract(db ethdb.Reader, address common.Address) *DataContract {
+	data, _ := db.Get(dataContractKey(address))
+	if len(data) == 0 {
+		return nil
+	}
+	contract := new(DataContract)
+	if err := rlp.DecodeBytes(data, contract); err != nil {
+		log.Error("Invalid data contract RLP", "address", address, "err", err)
+		return nil
+	}
+	contract.Address = address
+	return contract
+}
+
+// WriteDataContract stores the metadata of a canonical data contract, indexing it
+// by its payload's multihash and by its creator.
+func WriteDataContract(db ethdb.KeyValueWriter, contract *DataContract) {
+	data, err := rlp.EncodeToBytes(contract)
+	if err != nil {
+		log.Crit("Failed to RLP encode data contract", "err", err)
+	}
+	if err := db.Put(dataContractKey(contract.Address), data); err != nil {
+		log.Crit("Failed to store data contract", "err", err)
+	}
+	if err := db.Put(dataContractCIDKey(contract.Multihash, contract.BlockNumber, contract.Address), nil); err != nil {
+		log.Crit("Failed to store data contract CID index", "err", err)
+	}
+	if err := db.Put(dataContractCreatorKey(contract.Creator, contract.BlockNumber, contract.Address), nil); err != nil {
+		log.Crit("Failed to store data contract creator index", "err", err)
+	}
+}
+
+// DeleteDataContract removes the metadata of a data contract and its indexes.
+func DeleteDataContract(db ethdb.KeyValueWriter, contract *DataContract) {
+	if err := db.Delete(dataContractKey(contract.Address)); err != nil {
+		log.Crit("Failed to delete data contract", "err", err)
+	}
+	if err := db.Delete(dataContractCIDKey(contract.Multihash, contract.BlockNumber, contract.Address)); err != nil {
+		log.Crit("Failed to delete data contract CID index", "err", err)
+	}
+	if err := db.Delete(dataContractCreatorKey(contract.Creator, contract.BlockNumber, contract.Address)); err != nil {
+		log.Crit("Failed to delete data contract creator index", "err", err)
+	}
+}
+
+// readDataContractIndex iterates the addresses stored under a prefix followed by
+// a block number, in ascending block order. At most limit addresses are returned
+// if limit is positive.
+func readDataContractIndex(db ethdb.Iteratee, prefix []byte, limit int) []common.Address {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	var addresses []common.Address
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8+common.AddressLength {
+			continue
+		}
+		addresses = append(addresses, common.BytesToAddress(key[len(prefix)+8:]))
+		if limit > 0 && len(addresses) >= limit {
+			break
+		}
+	}
+	return addresses
+}
+
+// ReadDataContractsByCID retrieves the addresses of the canonical data contracts
+// storing the payload with the given multihash, oldest first. At most limit
+// addresses are returned if limit is positive.
+func ReadDataContractsByCID(db ethdb.Iteratee, multihash []byte, limit int) []common.Address {
+	return readDataContractIndex(db, append(append([]byte{}, dataContractCIDPrefix...), multihash...), limit)
+}
+
+// ReadCreatorDataContracts retrieves the addresses of the canonical data contracts
+// created by an account, oldest first. At most limit addresses are returned if
+// limit is positive.
+func ReadCreatorDataContracts(db ethdb.Iteratee, creator common.Address, limit int) []common.Address {
+	return readDataContractIndex(db, append(append([]byte{}, dataContractCreatorPrefix...), creator.Bytes()...), limit)
+}
+
+// ReadCanonicalDataContracts retrieves the data contracts indexed for the canonical
+// block at the given number, or nil if the number isn't indexed.
+func ReadCanonicalDataContracts(db ethdb.Reader, number uint64) *CanonicalDataContracts {
+	data, _ := db.Get(canonicalDataContractKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	entry := new(CanonicalDataContracts)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		log.Error("Invalid canonical data contracts RLP", "number", number, "err", err)
+		return nil
+	}
+	return entry
+}
+
+// WriteCanonicalDataContracts stores the data contracts indexed for the canonical
+// block at the given number.
+func WriteCanonicalDataContracts(db ethdb.KeyValueWriter, number uint64, entry *CanonicalDataContracts) {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Crit("Failed to RLP encode canonical data contracts", "err", err)
+	}
+	if err := db.Put(canonicalDataContractKey(number), data); err != nil {
+		log.Crit("Failed to store canonical data contracts", "err", err)
+	}
+}
+
+// DeleteCanonicalDataContracts removes the data contracts indexed for a number.
+func DeleteCanonicalDataContracts(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Delete(canonicalDataContractKey(number)); err != nil {
+		log.Crit("Failed to delete canonical data contracts", "err", err)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests data contract storage, retrieval and index operations.
+func TestDataContractStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		creator   = common.Address{0xc0}
+		multihash = types.DataMultihash([]byte("payload"))
+	)
+	contracts := []*DataContract{
+		{Address: common.Address{0x01}, Creator: creator, TxHash: common.Hash{0x11}, BlockHash: common.Hash{0x21}, BlockNumber: 7, Size: 7, Multihash: multihash},
+		{Address: common.Address{0x02}, Creator: common.Address{0xc1}, TxHash: common.Hash{0x12}, BlockHash: common.Hash{0x22}, BlockNumber: 3, Size: 7, Multihash: multihash},
+		{Address: common.Address{0x03}, Creator: creator, TxHash: common.Hash{0x13}, BlockHash: common.Hash{0x23}, BlockNumber: 5, Size: 5, Multihash: types.DataMultihash([]byte("other"))},
+	}
+	if entry := ReadDataContract(db, contracts[0].Address); entry != nil {
+		t.Fatalf("non existent data contract returned: %+v", entry)
+	}
+	for _, contract := range contracts {
+		WriteDataContract(db, contract)
+	}
+	for i, contract := range contracts {
+		if entry := ReadDataContract(db, contract.Address); !reflect.DeepEqual(entry, contract) {
+			t.Fatalf("contract %d: mismatch: have %+v, want %+v", i, entry, contract)
+		}
+	}
+	// Contracts sharing a payload are listed oldest first
+	if have, want := ReadDataContractsByCID(db, multihash, 0), []common.Address{{0x02}, {0x01}}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("CID index mismatch: have %x, want %x", have, want)
+	}
+	if have, want := ReadDataContractsByCID(db, multihash, 1), []common.Address{{0x02}}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("limited CID index mismatch: have %x, want %x", have, want)
+	}
+	if have, want := ReadCreatorDataContracts(db, creator, 0), []common.Address{{0x03}, {0x01}}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("creator index mismatch: have %x, want %x", have, want)
+	}
+	DeleteDataContract(db, contracts[1])
+	if entry := ReadDataContract(db, contracts[1].Address); entry != nil {
+		t.Fatalf("deleted data contract returned: %+v", entry)
+	}
+	if have, want := ReadDataContractsByCID(db, multihash, 0), []common.Address{{0x01}}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("CID index mismatch after deletion: have %x, want %x", have, want)
+	}
+	if have := ReadCreatorDataContracts(db, common.Address{0xc1}, 0); len(have) != 0 {
+		t.Fatalf("creator index not cleared: %x", have)
+	}
+	// Canonical block entries
+	entry := &CanonicalDataContracts{BlockHash: common.Hash{0x21}, Contracts: []common.Address{{0x01}}}
+	WriteCanonicalDataContracts(db, 7, entry)
+	if have := ReadCanonicalDataContracts(db, 7); !reflect.DeepEqual(have, entry) {
+		t.Fatalf("canonical data contracts mismatch: have %+v, want %+v", have, entry)
+	}
+	DeleteCanonicalDataContracts(db, 7)
+	if have := ReadCanonicalDataContracts(db, 7); have != nil {
+		t.Fatalf("deleted canonical data contracts returned: %+v", have)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AddressReward is the reward credited to an address by a canonical block, as
+// stored in the address reward index.
+type AddressReward struct {
+	BlockNumber uint64 `rlp:"-"` // Derived from the database key
+	BlockHash   common.Hash
+	PocReward   *big.Int
+	PowReward   *big.Int
+	PoolReward  *big.Int
+	PostReward  *big.Int
+}
+
+// AddressRewardSummary accumulates all the canonical rewards of an address.
+type AddressRewardSummary struct {
+	Blocks     uint64 // Number of canonical blocks rewarding the address
+	PocReward  *big.Int
+	PowReward  *big.Int
+	PoolReward *big.Int
+	PostReward *big.Int
+}
+
+// ReadRewardLedgerHead retrieves the number of the last block indexed by the
+// reward ledger, or nil if nothing was indexed yet.
+func ReadRewardLedgerHead(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(rewardLedgerHeadKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteRewardLedgerHead stores the number of the last block indexed by the
+// reward ledger.
+func WriteRewardLedgerHead(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(rewardLedgerHeadKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store reward ledger head", "err", err)
+	}
+}
+
+// ReadBlockRewards retrieves the encoded mining rewards (the header's CoinbaseTxs)
+// indexed for the block with the given hash, or nil if the block isn't indexed.
+// Frozen blocks have their rewards dropped from the key-value store, as their
+// headers carry them into the ancient database.
+func ReadBlockRewards(db ethdb.Reader, hash common.Hash, number uint64) []byte {
+	if data, err := db.Get(blockRewardsKey(number, hash)); err == nil {
+		return data
+	}
+	if !isAncientBlock(db, hash, number) {
+		return nil
+	}
+	header := ReadHeader(db, hash, number)
+	if header == nil {
+		return nil
+	}
+	return common.CopyBytes(header.CoinbaseTxs)
+}
+
+// HasBlockRewards verifies the existence of the mining rewards of a block, which
+// may legitimately be empty.
+func HasBlockRewards(db ethdb.Reader, hash common.Hash, number uint64) bool {
+	if isAncientBlock(db, hash, number) {
+		return true
+	}
+	if has, err := db.Has(blockRewardsKey(number, hash)); !has || err != nil {
+		return false
+	}
+	return true
+}
+
+// isAncientBlock reports whether the block with the given hash was moved into
+// the ancient database.
+func isAncientBlock(db ethdb.AncientReader, hash common.Hash, number uint64) bool {
+	data, err := db.Ancient(freezerHashTable, number)
+	return err == nil && common.BytesToHash(data) == hash
+}
+
+// WriteBlockRewards stores the encoded mining rewards of a block.
+func WriteBlockRewards(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rewards []byte) {
+	if err := db.Put(blockRewardsKey(number, hash), rewards); err != nil {
+		log.Crit("Failed to store block rewards", "err", err)
+	}
+}
+
+// DeleteBlockRewards removes the mining rewards of a block.
+func DeleteBlockRewards(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockRewardsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block rewards", "err", err)
+	}
+}
+
+// ReadCanonicalRewardsHash retrieves the hash of the block whose rewards are
+// indexed as canonical at the given number. Frozen blocks have their mapping
+// dropped from the key-value store, the ancient database holding it instead.
+func ReadCanonicalRewardsHash(db ethdb.Reader, number uint64) common.Hash {
+	data, _ := db.Get(canonicalRewardsKey(number))
+	if len(data) == 0 {
+		data, _ = db.Ancient(freezerHashTable, number)
+		if len(data) == 0 {
+			return common.Hash{}
+		}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteCanonicalRewardsHash marks the rewards of the given block as canonical
+// at its number.
+func WriteCanonicalRewardsHash(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Put(canonicalRewardsKey(number), hash.Bytes()); err != nil {
+		log.Crit("Failed to store canonical rewards hash", "err", err)
+	}
+}
+
+// DeleteCanonicalRewardsHash removes the canonical rewards mapping of a number.
+func DeleteCanonicalRewardsHash(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Delete(canonicalRewardsKey(number)); err != nil {
+		log.Crit("Failed to delete canonical rewards hash", "err", err)
+	}
+}
+
+// ReadCanonicalBlockRewards retrieves the mining rewards indexed as canonical at
+// the given number, along with the hash of the block they belong to.
+func ReadCanonicalBlockRewards(db ethdb.Reader, number uint64) ([]byte, common.Hash) {
+	hash := ReadCanonicalRewardsHash(db, number)
+	if hash == (common.Hash{}) {
+		return nil, common.Hash{}
+	}
+	return ReadBlockRewards(db, hash, number), hash
+}
+
+// ReadAddressRewards retrieves the canonical rewards of an address within the
+// inclusive block range, in ascending block order. At most limit entries are
+// returned if limit is positive.
+func ReadAddressRewards(db ethdb.Iteratee, address common.Address, from, to uint64, limit int) []*AddressReward {
+	prefix := append(addressRewardPrefix, address.Bytes()...)
+	it := db.NewIteratorWithStart(addressRewardKey(address, from))
+	defer it.Release()
+
+	var rewards []*AddressReward
+	for it.Next() {
+		key := it.Key()
+		if !bytes.HasPrefix(key, prefix) || len(key) != len(prefix)+8 {
+			break
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		reward := new(AddressReward)
+		if err := rlp.DecodeBytes(it.Value(), reward); err != nil {
+			log.Error("Invalid address reward RLP", "address", address, "number", number, "err", err)
+			continue
+		}
+		reward.BlockNumber = number
+		rewards = append(rewards, reward)
+		if limit > 0 && len(rewards) >= limit {
+			break
+		}
+	}
+	return rewards
+}
+
+// WriteAddressReward stores the reward credited to an address by a canonical block.
+func WriteAddressReward(db ethdb.KeyValueWriter, address common.Address, number uint64, reward *AddressReward) {
+	data, err := rlp.EncodeToBytes(reward)
+	if err != nil {
+		log.Crit("Failed to RLP encode address reward", "err", err)
+	}
+	if err := db.Put(addressRewardKey(address, number), data); err != nil {
+		log.Crit("Failed to store address reward", "err", err)
+	}
+}
+
+// DeleteAddressReward removes the reward credited to an address by a block.
+func DeleteAddressReward(db ethdb.KeyValueWriter, address common.Address, number uint64) {
+	if err := db.Delete(addressRewardKey(address, number)); err != nil {
+		log.Crit("Failed to delete address reward", "err", err)
+	}
+}
+
+// ReadAddressRewardSummary retrieves the canonical reward totals of an address,
+// or nil if the address was never rewarded.
+func ReadAddressRewardSummary(db ethdb.Reader, address common.Address) *AddressRewardSummary {
+	data, _ := db.Get(addressSummaryKey(address))
+	if len(data) == 0 {
+		return nil
+	}
+	summary := new(AddressRewardSummary)
+	if err := rlp.DecodeBytes(data, summary); err != nil {
+		log.Error("Invalid address reward summary RLP", "address", address, "err", err)
+		return nil
+	}
+	return summary
+}
+
+// WriteAddressRewardSummary stores the canonical reward totals of an address.
+func WriteAddressRewardSummary(db ethdb.KeyValueWriter, address common.Address, summary *AddressRewardSummary) {
+	data, err := rlp.EncodeToBytes(summary)
+	if err != nil {
+		log.Crit("Failed to RLP encode address reward summary", "err", err)
+	}
+	if err := db.Put(addressSummaryKey(address), data); err != nil {
+		log.Crit("Failed to store address reward summary", "err", err)
+	}
+}
+
+// DeleteAddressRewardSummary removes the canonical reward totals of an address.
+func DeleteAddressRewardSummary(db ethdb.KeyValueWriter, address common.Address) {
+	if err := db.Delete(addressSummaryKey(address)); err != nil {
+		log.Crit("Failed to delete address reward summary", "err", err)
+	}
+}
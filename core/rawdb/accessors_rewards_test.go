@@ -0,0 +1,159 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests block rewards storage and canonical mapping operations.
+func TestBlockRewardsStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		hash    = common.Hash{0x01}
+		side    = common.Hash{0x02}
+		rewards = []byte{0x01, 0xc0}
+	)
+	if HasBlockRewards(db, hash, 1) {
+		t.Fatalf("non existent block rewards returned")
+	}
+	WriteBlockRewards(db, hash, 1, rewards)
+	WriteBlockRewards(db, side, 1, nil)
+	if entry := ReadBlockRewards(db, hash, 1); !bytes.Equal(entry, rewards) {
+		t.Fatalf("rewards mismatch: have %x, want %x", entry, rewards)
+	}
+	if !HasBlockRewards(db, side, 1) {
+		t.Fatalf("empty block rewards not found")
+	}
+	if entry, h := ReadCanonicalBlockRewards(db, 1); entry != nil || h != (common.Hash{}) {
+		t.Fatalf("unmapped canonical rewards returned: %x %x", entry, h)
+	}
+	WriteCanonicalRewardsHash(db, hash, 1)
+	if entry, h := ReadCanonicalBlockRewards(db, 1); !bytes.Equal(entry, rewards) || h != hash {
+		t.Fatalf("canonical rewards mismatch: have %x %x, want %x %x", entry, h, rewards, hash)
+	}
+	DeleteCanonicalRewardsHash(db, 1)
+	if h := ReadCanonicalRewardsHash(db, 1); h != (common.Hash{}) {
+		t.Fatalf("deleted canonical rewards hash returned: %x", h)
+	}
+	DeleteBlockRewards(db, hash, 1)
+	if HasBlockRewards(db, hash, 1) {
+		t.Fatalf("deleted block rewards returned")
+	}
+	if !HasBlockRewards(db, side, 1) {
+		t.Fatalf("side block rewards deleted")
+	}
+}
+
+// Tests that the rewards of frozen blocks are served from their headers in the
+// ancient store, the key-value store no longer holding them.
+func TestFrozenBlockRewards(t *testing.T) {
+	frdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temp freezer dir: %v", err)
+	}
+	defer os.RemoveAll(frdir)
+
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), frdir, "")
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend")
+	}
+	defer db.Close()
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:      big.NewInt(0),
+		UncleHash:   types.EmptyUncleHash,
+		TxHash:      types.EmptyRootHash,
+		ReceiptHash: types.EmptyRootHash,
+		CoinbaseTxs: []byte{0x01, 0xc0},
+	})
+	hash, number := block.Hash(), block.NumberU64()
+	side := common.Hash{0x01}
+
+	if HasBlockRewards(db, hash, number) || ReadCanonicalRewardsHash(db, number) != (common.Hash{}) {
+		t.Fatalf("non existent block rewards returned")
+	}
+	WriteAncientBlock(db, block, nil, big.NewInt(1))
+
+	if !HasBlockRewards(db, hash, number) {
+		t.Fatalf("frozen block rewards not found")
+	}
+	if entry, h := ReadCanonicalBlockRewards(db, number); !bytes.Equal(entry, block.Header().CoinbaseTxs) || h != hash {
+		t.Fatalf("frozen rewards mismatch: have %x %x, want %x %x", entry, h, block.Header().CoinbaseTxs, hash)
+	}
+	if HasBlockRewards(db, side, number) || ReadBlockRewards(db, side, number) != nil {
+		t.Fatalf("rewards of an unknown block returned")
+	}
+	// Side chain rewards at a frozen number remain in the key-value store
+	WriteBlockRewards(db, side, number, []byte{0x01})
+	if entry := ReadBlockRewards(db, side, number); !bytes.Equal(entry, []byte{0x01}) {
+		t.Fatalf("side rewards mismatch: have %x, want %x", entry, []byte{0x01})
+	}
+}
+
+// Tests the address reward index range queries.
+func TestAddressRewardsStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		addr  = common.Address{0x01}
+		other = common.Address{0x02}
+	)
+	for _, n := range []uint64{1, 2, 5, 9} {
+		WriteAddressReward(db, addr, n, &AddressReward{BlockHash: common.Hash{byte(n)}, PocReward: big.NewInt(int64(n)), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)})
+	}
+	WriteAddressReward(db, other, 3, &AddressReward{PocReward: new(big.Int), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)})
+
+	tests := []struct {
+		from, to uint64
+		limit    int
+		want     []uint64
+	}{
+		{0, 100, 0, []uint64{1, 2, 5, 9}},
+		{2, 5, 0, []uint64{2, 5}},
+		{3, 4, 0, nil},
+		{0, 100, 2, []uint64{1, 2}},
+		{9, 9, 0, []uint64{9}},
+	}
+	for i, tt := range tests {
+		rewards := ReadAddressRewards(db, addr, tt.from, tt.to, tt.limit)
+		if len(rewards) != len(tt.want) {
+			t.Fatalf("test %d: reward count mismatch: have %d, want %d", i, len(rewards), len(tt.want))
+		}
+		for j, reward := range rewards {
+			if reward.BlockNumber != tt.want[j] || reward.BlockHash != (common.Hash{byte(tt.want[j])}) || reward.PocReward.Uint64() != tt.want[j] {
+				t.Errorf("test %d, reward %d: have %d/%x/%v, want %d", i, j, reward.BlockNumber, reward.BlockHash, reward.PocReward, tt.want[j])
+			}
+		}
+	}
+	DeleteAddressReward(db, addr, 5)
+	if rewards := ReadAddressRewards(db, addr, 5, 5, 0); len(rewards) != 0 {
+		t.Fatalf("deleted address reward returned")
+	}
+	if summary := ReadAddressRewardSummary(db, addr); summary != nil {
+		t.Fatalf("non existent summary returned: %v", summary)
+	}
+	WriteAddressRewardSummary(db, addr, &AddressRewardSummary{Blocks: 3, PocReward: big.NewInt(12), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)})
+	if summary := ReadAddressRewardSummary(db, addr); summary == nil || summary.Blocks != 3 || summary.PocReward.Uint64() != 12 {
+		t.Fatalf("summary mismatch: have %v", summary)
+	}
+	DeleteAddressRewardSummary(db, addr)
+	if summary := ReadAddressRewardSummary(db, addr); summary != nil {
+		t.Fatalf("deleted summary returned: %v", summary)
+	}
+	if head := ReadRewardLedgerHead(db); head != nil {
+		t.Fatalf("non existent ledger head returned: %d", *head)
+	}
+	WriteRewardLedgerHead(db, 42)
+	if head := ReadRewardLedgerHead(db); head == nil || *head != 42 {
+		t.Fatalf("ledger head mismatch: have %v, want %d", head, 42)
+	}
+}
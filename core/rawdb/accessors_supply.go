@@ -0,0 +1,110 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// BlockSupply is the coin supply accounting of a block: the coins it created and
+// destroyed, along with the totals of the chain ending in it. The genesis
+// allocation counts as minted by the genesis block.
+type BlockSupply struct {
+	Minted *big.Int // Block and uncle rewards created by the issuance schedule
+	Fees   *big.Int // Fees minted for the rewarded participants (includer rewards only under a fee policy)
+	Team   *big.Int // Share of the legacy fees minted for the team
+	Burnt  *big.Int // Transaction fees destroyed by the state transition
+
+	TotalMinted *big.Int
+	TotalFees   *big.Int
+	TotalTeam   *big.Int
+	TotalBurnt  *big.Int
+}
+
+// Supply returns the total amount of coins in existence after the block.
+func (s *BlockSupply) Supply() *big.Int {
+	supply := new(big.Int).Add(s.TotalMinted, s.TotalFees)
+	supply.Add(supply, s.TotalTeam)
+	return supply.Sub(supply, s.TotalBurnt)
+}
+
+// ReadBlockSupply retrieves the supply accounting of the block with the given
+// hash, or nil if the block isn't accounted yet.
+func ReadBlockSupply(db ethdb.Reader, hash common.Hash, number uint64) *BlockSupply {
+	data, _ := db.Get(blockSupplyKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	supply := new(BlockSupply)
+	if err := rlp.DecodeBytes(data, supply); err != nil {
+		log.Error("Invalid block supply RLP", "number", number, "hash", hash, "err", err)
+		return nil
+	}
+	return supply
+}
+
+// WriteBlockSupply stores the supply accounting of a block.
+func WriteBlockSupply(db ethdb.KeyValueWriter, hash common.Hash, number uint64, supply *BlockSupply) {
+	data, err := rlp.EncodeToBytes(supply)
+	if err != nil {
+		log.Crit("Failed to RLP encode block supply", "err", err)
+	}
+	if err := db.Put(blockSupplyKey(number, hash), data); err != nil {
+		log.Crit("Failed to store block supply", "err", err)
+	}
+}
+
+// DeleteBlockSupply removes the supply accounting of a block.
+func DeleteBlockSupply(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockSupplyKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block supply", "err", err)
+	}
+}
+
+// BlockFeeSplit is how the transaction fees of a block were split. Under the
+// legacy split the participants' and team's fees are minted on top of the
+// transaction fees instead of taken out of them.
+type BlockFeeSplit struct {
+	Coinbase     *big.Int // Fees credited to the block's coinbase
+	Participants *big.Int // Fees shared out between the rewarded participants
+	Team         *big.Int // Fees credited to the team treasury
+	Burnt        *big.Int // Fees destroyed
+}
+
+// ReadBlockFeeSplit retrieves the fee split of the block with the given hash, or
+// nil if the block isn't accounted yet.
+func ReadBlockFeeSplit(db ethdb.Reader, hash common.Hash, number uint64) *BlockFeeSplit {
+	data, _ := db.Get(blockFeeSplitKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	split := new(BlockFeeSplit)
+	if err := rlp.DecodeBytes(data, split); err != nil {
+		log.Error("Invalid block fee split RLP", "number", number, "hash", hash, "err", err)
+		return nil
+	}
+	return split
+}
+
+// WriteBlockFeeSplit stores the fee split of a block.
+func WriteBlockFeeSplit(db ethdb.KeyValueWriter, hash common.Hash, number uint64, split *BlockFeeSplit) {
+	data, err := rlp.EncodeToBytes(split)
+	if err != nil {
+		log.Crit("Failed to RLP encode block fee split", "err", err)
+	}
+	if err := db.Put(blockFeeSplitKey(number, hash), data); err != nil {
+		log.Crit("Failed to store block fee split", "err", err)
+	}
+}
+
+// DeleteBlockFeeSplit removes the fee split of a block.
+func DeleteBlockFeeSplit(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockFeeSplitKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block fee split", "err", err)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 The nuc Team
+
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests block supply storage and retrieval operations.
+func TestBlockSupplyStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash := common.Hash{0x01}
+	supply := &BlockSupply{
+		Minted:      big.NewInt(12),
+		Fees:        big.NewInt(3),
+		Team:        big.NewInt(1),
+		Burnt:       big.NewInt(5),
+		TotalMinted: big.NewInt(112),
+		TotalFees:   big.NewInt(30),
+		TotalTeam:   big.NewInt(10),
+		TotalBurnt:  big.NewInt(50),
+	}
+	if entry := ReadBlockSupply(db, hash, 1); entry != nil {
+		t.Fatalf("non existent block supply returned: %+v", entry)
+	}
+	WriteBlockSupply(db, hash, 1, supply)
+	if entry := ReadBlockSupply(db, hash, 1); !reflect.DeepEqual(entry, supply) {
+		t.Fatalf("supply mismatch: have %+v, want %+v", entry, supply)
+	}
+	if entry := ReadBlockSupply(db, hash, 2); entry != nil {
+		t.Fatalf("supply returned for wrong number: %+v", entry)
+	}
+	if total := supply.Supply(); total.Cmp(big.NewInt(102)) != 0 {
+		t.Fatalf("total supply mismatch: have %v, want %v", total, 102)
+	}
+	DeleteBlockSupply(db, hash, 1)
+	if entry := ReadBlockSupply(db, hash, 1); entry != nil {
+		t.Fatalf("deleted block supply returned: %+v", entry)
+	}
+}
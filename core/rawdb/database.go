@@ -243,6 +243,11 @@ func InspectDatabase(db ethdb.Database) error {
 		preimageSize    common.StorageSize
 		bloomBitsSize   common.StorageSize
 		cliqueSnapsSize common.StorageSize
+		rewardsSize     common.StorageSize
+		rewardsIndex    common.StorageSize
+		addrRewardsSize common.StorageSize
+		supplySize      common.StorageSize
+		dataIndexSize   common.StorageSize
 
 		// Ancient store statistics
 		ancientHeaders  common.StorageSize
@@ -285,6 +290,26 @@ func InspectDatabase(db ethdb.Database) error {
 			preimageSize += size
 		case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
 			bloomBitsSize += size
+		case bytes.HasPrefix(key, blockRewardsPrefix) && len(key) == (len(blockRewardsPrefix)+8+common.HashLength):
+			rewardsSize += size
+		case bytes.HasPrefix(key, canonicalRewardsPrefix) && len(key) == (len(canonicalRewardsPrefix)+8):
+			rewardsIndex += size
+		case bytes.HasPrefix(key, addressRewardPrefix) && len(key) == (len(addressRewardPrefix)+common.AddressLength+8):
+			addrRewardsSize += size
+		case bytes.HasPrefix(key, addressSummaryPrefix) && len(key) == (len(addressSummaryPrefix)+common.AddressLength):
+			addrRewardsSize += size
+		case bytes.HasPrefix(key, blockSupplyPrefix) && len(key) == (len(blockSupplyPrefix)+8+common.HashLength):
+			supplySize += size
+		case bytes.HasPrefix(key, blockFeeSplitPrefix) && len(key) == (len(blockFeeSplitPrefix)+8+common.HashLength):
+			supplySize += size
+		case bytes.HasPrefix(key, dataContractPrefix) && len(key) == (len(dataContractPrefix)+common.AddressLength):
+			dataIndexSize += size
+		case bytes.HasPrefix(key, dataContractCIDPrefix) && len(key) > (len(dataContractCIDPrefix)+8+common.AddressLength):
+			dataIndexSize += size
+		case bytes.HasPrefix(key, dataContractCreatorPrefix) && len(key) == (len(dataContractCreatorPrefix)+2*common.AddressLength+8):
+			dataIndexSize += size
+		case bytes.HasPrefix(key, canonicalDataContractPrefix) && len(key) == (len(canonicalDataContractPrefix)+8):
+			dataIndexSize += size
 		case bytes.HasPrefix(key, []byte("clique-")) && len(key) == 7+common.HashLength:
 			cliqueSnapsSize += size
 		case bytes.HasPrefix(key, []byte("cht-")) && len(key) == 4+common.HashLength:
@@ -295,7 +320,7 @@ func InspectDatabase(db ethdb.Database) error {
 			trieSize += size
 		default:
 			var accounted bool
-			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey} {
+			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, rewardLedgerHeadKey} {
 				if bytes.Equal(key, meta) {
 					metadata += size
 					accounted = true
@@ -333,6 +358,11 @@ func InspectDatabase(db ethdb.Database) error {
 		{"Key-Value store", "Trie nodes", trieSize.String()},
 		{"Key-Value store", "Trie preimages", preimageSize.String()},
 		{"Key-Value store", "Clique snapshots", cliqueSnapsSize.String()},
+		{"Key-Value store", "Block rewards", rewardsSize.String()},
+		{"Key-Value store", "Block rewards index", rewardsIndex.String()},
+		{"Key-Value store", "Address rewards index", addrRewardsSize.String()},
+		{"Key-Value store", "Block supply", supplySize.String()},
+		{"Key-Value store", "Data contract index", dataIndexSize.String()},
 		{"Key-Value store", "Singleton metadata", metadata.String()},
 		{"Ancient store", "Headers", ancientHeaders.String()},
 		{"Ancient store", "Bodies", ancientBodies.String()},
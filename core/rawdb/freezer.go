@@ -333,25 +333,32 @@ func (f *freezer) freeze(db ethdb.KeyValueStore) {
 		if err := f.Sync(); err != nil {
 			log.Crit("Failed to flush frozen tables", "err", err)
 		}
-		// Wipe out all data from the active database
+		// Wipe out all data from the active database. The rewards of the canonical
+		// blocks go too, their frozen headers carrying them, while their supply is
+		// retained as the freezer doesn't track it
 		batch := db.NewBatch()
 		for i := 0; i < len(ancients); i++ {
 			// Always keep the genesis block in active database
 			if first+uint64(i) != 0 {
 				DeleteBlockWithoutNumber(batch, ancients[i], first+uint64(i))
 				DeleteCanonicalHash(batch, first+uint64(i))
+				DeleteBlockRewards(batch, ancients[i], first+uint64(i))
+				DeleteCanonicalRewardsHash(batch, first+uint64(i))
 			}
 		}
 		if err := batch.Write(); err != nil {
 			log.Crit("Failed to delete frozen canonical blocks", "err", err)
 		}
 		batch.Reset()
-		// Wipe out side chain also.
+		// Wipe out side chain also, including their rewards and supply.
 		for number := first; number < f.frozen; number++ {
 			// Always keep the genesis block in active database
 			if number != 0 {
 				for _, hash := range ReadAllHashes(db, number) {
 					DeleteBlock(batch, hash, number)
+					DeleteBlockRewards(batch, hash, number)
+					DeleteBlockSupply(batch, hash, number)
+					DeleteBlockFeeSplit(batch, hash, number)
 				}
 			}
 		}
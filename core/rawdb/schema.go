@@ -41,6 +41,9 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// rewardLedgerHeadKey tracks the number of the last block indexed by the reward ledger.
+	rewardLedgerHeadKey = []byte("RewardLedgerHead")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -53,6 +56,18 @@ var (
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
+	blockRewardsPrefix     = []byte("w") // blockRewardsPrefix + num (uint64 big endian) + hash -> block rewards
+	canonicalRewardsPrefix = []byte("W") // canonicalRewardsPrefix + num (uint64 big endian) -> hash of the indexed canonical rewards
+	addressRewardPrefix    = []byte("a") // addressRewardPrefix + address + num (uint64 big endian) -> canonical reward of the address
+	addressSummaryPrefix   = []byte("A") // addressSummaryPrefix + address -> canonical reward totals of the address
+	blockSupplyPrefix      = []byte("S") // blockSupplyPrefix + num (uint64 big endian) + hash -> block supply accounting
+	blockFeeSplitPrefix    = []byte("F") // blockFeeSplitPrefix + num (uint64 big endian) + hash -> block fee split
+
+	dataContractPrefix          = []byte("C") // dataContractPrefix + address -> canonical data contract metadata
+	dataContractCIDPrefix       = []byte("I") // dataContractCIDPrefix + multihash + num (uint64 big endian) + address -> empty
+	dataContractCreatorPrefix   = []byte("O") // dataContractCreatorPrefix + creator + num (uint64 big endian) + address -> empty
+	canonicalDataContractPrefix = []byte("N") // canonicalDataContractPrefix + num (uint64 big endian) -> indexed block hash and data contracts
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
@@ -140,6 +155,56 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// blockRewardsKey = blockRewardsPrefix + num (uint64 big endian) + hash
+func blockRewardsKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockRewardsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// canonicalRewardsKey = canonicalRewardsPrefix + num (uint64 big endian)
+func canonicalRewardsKey(number uint64) []byte {
+	return append(canonicalRewardsPrefix, encodeBlockNumber(number)...)
+}
+
+// addressRewardKey = addressRewardPrefix + address + num (uint64 big endian)
+func addressRewardKey(address common.Address, number uint64) []byte {
+	return append(append(addressRewardPrefix, address.Bytes()...), encodeBlockNumber(number)...)
+}
+
+// addressSummaryKey = addressSummaryPrefix + address
+func addressSummaryKey(address common.Address) []byte {
+	return append(addressSummaryPrefix, address.Bytes()...)
+}
+
+// blockSupplyKey = blockSupplyPrefix + num (uint64 big endian) + hash
+func blockSupplyKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockSupplyPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// blockFeeSplitKey = blockFeeSplitPrefix + num (uint64 big endian) + hash
+func blockFeeSplitKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockFeeSplitPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// dataContractKey = dataContractPrefix + address
+func dataContractKey(address common.Address) []byte {
+	return append(dataContractPrefix, address.Bytes()...)
+}
+
+// dataContractCIDKey = dataContractCIDPrefix + multihash + num (uint64 big endian) + address
+func dataContractCIDKey(multihash []byte, number uint64, address common.Address) []byte {
+	return append(append(append(dataContractCIDPrefix, multihash...), encodeBlockNumber(number)...), address.Bytes()...)
+}
+
+// dataContractCreatorKey = dataContractCreatorPrefix + creator + num (uint64 big endian) + address
+func dataContractCreatorKey(creator common.Address, number uint64, address common.Address) []byte {
+	return append(append(append(dataContractCreatorPrefix, creator.Bytes()...), encodeBlockNumber(number)...), address.Bytes()...)
+}
+
+// canonicalDataContractKey = canonicalDataContractPrefix + num (uint64 big endian)
+func canonicalDataContractKey(number uint64) []byte {
+	return append(canonicalDataContractPrefix, encodeBlockNumber(number)...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -0,0 +1,298 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// rewardLedgerHeadChanSize is the size of channel listening to ChainHeadEvent.
+	rewardLedgerHeadChanSize = 10
+
+	// rewardLedgerSideChanSize is the size of channel listening to ChainSideEvent.
+	rewardLedgerSideChanSize = 10
+
+	// rewardLedgerStepBlocks is the maximum number of blocks indexed in one go,
+	// before the ledger gets back to the chain events.
+	rewardLedgerStepBlocks = 2048
+)
+
+// RewardLedger indexes the mining rewards committed to by the block headers
+// (CoinbaseTxs) into the chain database. Rewards are stored per block hash, so
+// side chain blocks can be looked up too, while the canonical number to hash
+// mapping and the per address index follow the chain head across reorgs and
+// rewinds.
+type RewardLedger struct {
+	db     ethdb.Database
+	chain  *BlockChain
+	config *params.ChainConfig // Chain config scheduling the encoding of the rewards
+
+	headCh  chan ChainHeadEvent
+	sideCh  chan ChainSideEvent
+	headSub event.Subscription
+	sideSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRewardLedger creates a reward ledger on top of the chain's database and
+// starts indexing from the current head.
+func NewRewardLedger(db ethdb.Database, chain *BlockChain) *RewardLedger {
+	ledger := &RewardLedger{
+		db:     db,
+		chain:  chain,
+		config: chain.Config(),
+		headCh: make(chan ChainHeadEvent, rewardLedgerHeadChanSize),
+		sideCh: make(chan ChainSideEvent, rewardLedgerSideChanSize),
+		quit:   make(chan struct{}),
+	}
+	ledger.headSub = chain.SubscribeChainHeadEvent(ledger.headCh)
+	ledger.sideSub = chain.SubscribeChainSideEvent(ledger.sideCh)
+
+	ledger.wg.Add(1)
+	go ledger.loop()
+	return ledger
+}
+
+// Stop terminates the indexing and waits for the pending writes to finish.
+func (l *RewardLedger) Stop() {
+	l.headSub.Unsubscribe()
+	l.sideSub.Unsubscribe()
+	close(l.quit)
+	l.wg.Wait()
+}
+
+// loop keeps the ledger in sync with the chain until stopped. The chain is
+// indexed in bounded steps, so catching up with an existing chain happens in the
+// background of the chain events instead of holding them up.
+func (l *RewardLedger) loop() {
+	defer l.wg.Done()
+
+	var (
+		head   = l.chain.CurrentBlock().Header()
+		synced = l.indexHead(head)
+		resume = make(chan struct{})
+	)
+	close(resume)
+
+	for {
+		var backfill <-chan struct{}
+		if !synced {
+			backfill = resume
+		}
+		select {
+		case ev := <-l.headCh:
+			head = ev.Block.Header()
+			synced = l.indexHead(head)
+
+		case ev := <-l.sideCh:
+			l.indexSide(ev.Block.Header())
+
+		case <-backfill:
+			synced = l.indexHead(head)
+
+		case <-l.headSub.Err():
+			return
+		case <-l.sideSub.Err():
+			return
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// indexSide stores the rewards of a block that didn't become canonical.
+func (l *RewardLedger) indexSide(header *types.Header) {
+	rawdb.WriteBlockRewards(l.db, header.Hash(), header.Number.Uint64(), header.CoinbaseTxs)
+}
+
+// indexHead makes the canonical rewards mapping and the address index match the
+// chain ending in head. Mappings above the head, left over from a reorged out or
+// rewound chain, are dropped and the blocks since the last common ancestor are
+// (re)indexed in ascending order, so an interrupted run never leaves gaps behind.
+//
+// At most rewardLedgerStepBlocks blocks are indexed per call, the progress being
+// recorded as the reward ledger head. False is returned if there are more blocks
+// left to index up to head.
+func (l *RewardLedger) indexHead(head *types.Header) bool {
+	var (
+		batch     = l.db.NewBatch()
+		number    = head.Number.Uint64()
+		summaries = make(map[common.Address]*rawdb.AddressRewardSummary)
+	)
+	for n := number + 1; ; n++ {
+		hash := rawdb.ReadCanonicalRewardsHash(l.db, n)
+		if hash == (common.Hash{}) {
+			break
+		}
+		l.unindexBlock(batch, summaries, hash, n)
+	}
+	// Find the first block whose indexed rewards don't match the canonical chain,
+	// searching back from the last indexed one
+	var first uint64
+	if last := rawdb.ReadRewardLedgerHead(l.db); last != nil {
+		first = *last + 1
+		if first > number+1 {
+			first = number + 1
+		}
+		for ; first > 0; first-- {
+			if hash := rawdb.ReadCanonicalRewardsHash(l.db, first-1); hash != (common.Hash{}) && hash == rawdb.ReadCanonicalHash(l.db, first-1) {
+				break
+			}
+		}
+	}
+	last, synced := number, true
+	if first <= number && number-first >= rewardLedgerStepBlocks {
+		last, synced = first+rewardLedgerStepBlocks-1, false
+	}
+	// Blocks already frozen have their rewards and mapping in the ancient store
+	frozen, _ := l.db.Ancients()
+
+	next := first
+	for n := first; n <= last; n++ {
+		var header *types.Header
+		if n == number {
+			header = head
+		} else {
+			header = rawdb.ReadHeader(l.db, rawdb.ReadCanonicalHash(l.db, n), n)
+		}
+		if header == nil {
+			log.Warn("Missing header for reward ledger", "number", n)
+			synced = true // Retry with the next head
+			break
+		}
+		if hash := rawdb.ReadCanonicalRewardsHash(l.db, n); hash != (common.Hash{}) && n >= frozen {
+			l.unindexBlock(batch, summaries, hash, n)
+		}
+		l.indexBlock(batch, summaries, header, n < frozen && n > 0)
+		next = n + 1
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			writeRewardSummaries(batch, summaries)
+			rawdb.WriteRewardLedgerHead(batch, n)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to write reward ledger", "err", err)
+			}
+			batch.Reset()
+
+			select {
+			case <-l.quit:
+				return true
+			default:
+			}
+		}
+	}
+	writeRewardSummaries(batch, summaries)
+	if next > 0 {
+		rawdb.WriteRewardLedgerHead(batch, next-1)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write reward ledger", "err", err)
+	}
+	if first < next {
+		log.Debug("Indexed block rewards", "from", first, "to", next-1, "head", number, "hash", head.Hash())
+	}
+	return synced
+}
+
+// indexBlock stores the rewards of a canonical block, mapping them to its number
+// and crediting every rewarded address. The rewards and mapping of a frozen block
+// are left to the ancient store.
+func (l *RewardLedger) indexBlock(batch ethdb.Batch, summaries map[common.Address]*rawdb.AddressRewardSummary, header *types.Header, frozen bool) {
+	var (
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+	)
+	if !frozen {
+		rawdb.WriteBlockRewards(batch, hash, number, header.CoinbaseTxs)
+		rawdb.WriteCanonicalRewardsHash(batch, hash, number)
+	}
+
+	rewards, err := types.DecodeBlockCoinbaseTxs(l.config, header.Number, header.CoinbaseTxs)
+	if err != nil {
+		log.Warn("Invalid block rewards", "number", number, "hash", hash, "err", err)
+		return
+	}
+	for _, reward := range rewards {
+		rawdb.WriteAddressReward(batch, reward.Address, number, &rawdb.AddressReward{
+			BlockHash:  hash,
+			PocReward:  reward.PocReward,
+			PowReward:  reward.PowReward,
+			PoolReward: reward.PoolReward,
+			PostReward: reward.PostReward,
+		})
+		summary := l.summary(summaries, reward.Address)
+		summary.Blocks++
+		summary.PocReward.Add(summary.PocReward, reward.PocReward)
+		summary.PowReward.Add(summary.PowReward, reward.PowReward)
+		summary.PoolReward.Add(summary.PoolReward, reward.PoolReward)
+		summary.PostReward.Add(summary.PostReward, reward.PostReward)
+	}
+}
+
+// unindexBlock drops the canonical mapping of a block that's no longer part of
+// the chain and reverts its credits. The rewards themselves are kept for lookups
+// by hash unless the block was deleted altogether (e.g. by a rewind).
+func (l *RewardLedger) unindexBlock(batch ethdb.Batch, summaries map[common.Address]*rawdb.AddressRewardSummary, hash common.Hash, number uint64) {
+	rawdb.DeleteCanonicalRewardsHash(batch, number)
+
+	rewards, err := types.DecodeBlockCoinbaseTxs(l.config, new(big.Int).SetUint64(number), rawdb.ReadBlockRewards(l.db, hash, number))
+	if err != nil {
+		log.Warn("Invalid block rewards", "number", number, "hash", hash, "err", err)
+	}
+	for _, reward := range rewards {
+		rawdb.DeleteAddressReward(batch, reward.Address, number)
+
+		summary := l.summary(summaries, reward.Address)
+		summary.Blocks--
+		summary.PocReward.Sub(summary.PocReward, reward.PocReward)
+		summary.PowReward.Sub(summary.PowReward, reward.PowReward)
+		summary.PoolReward.Sub(summary.PoolReward, reward.PoolReward)
+		summary.PostReward.Sub(summary.PostReward, reward.PostReward)
+	}
+	if !rawdb.HasHeader(l.db, hash, number) {
+		rawdb.DeleteBlockRewards(batch, hash, number)
+	}
+}
+
+// summary returns the reward totals of an address being updated, loading them
+// from the database on first access.
+func (l *RewardLedger) summary(summaries map[common.Address]*rawdb.AddressRewardSummary, address common.Address) *rawdb.AddressRewardSummary {
+	if summary, ok := summaries[address]; ok {
+		return summary
+	}
+	summary := rawdb.ReadAddressRewardSummary(l.db, address)
+	if summary == nil {
+		summary = &rawdb.AddressRewardSummary{
+			PocReward:  new(big.Int),
+			PowReward:  new(big.Int),
+			PoolReward: new(big.Int),
+			PostReward: new(big.Int),
+		}
+	}
+	summaries[address] = summary
+	return summary
+}
+
+// writeRewardSummaries flushes the updated reward totals into the batch, dropping
+// the addresses left without any canonical reward.
+func writeRewardSummaries(batch ethdb.Batch, summaries map[common.Address]*rawdb.AddressRewardSummary) {
+	for address, summary := range summaries {
+		if summary.Blocks == 0 {
+			rawdb.DeleteAddressRewardSummary(batch, address)
+		} else {
+			rawdb.WriteAddressRewardSummary(batch, address, summary)
+		}
+	}
+}
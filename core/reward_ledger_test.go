@@ -0,0 +1,275 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// checkRewardLedger verifies that the canonical rewards mapping follows the
+// chain up to its head and that nothing is mapped above it.
+func checkRewardLedger(t *testing.T, db ethdb.Database, chain *BlockChain) {
+	t.Helper()
+
+	head := chain.CurrentBlock().NumberU64()
+	for n := uint64(0); n <= head; n++ {
+		header := chain.GetHeaderByNumber(n)
+		rewards, hash := rawdb.ReadCanonicalBlockRewards(db, n)
+		if hash != header.Hash() {
+			t.Fatalf("block %d: canonical rewards hash mismatch: have %x, want %x", n, hash, header.Hash())
+		}
+		if !bytes.Equal(rewards, header.CoinbaseTxs) {
+			t.Fatalf("block %d: rewards mismatch: have %x, want %x", n, rewards, header.CoinbaseTxs)
+		}
+	}
+	if hash := rawdb.ReadCanonicalRewardsHash(db, head+1); hash != (common.Hash{}) {
+		t.Fatalf("stale canonical rewards above head: %x", hash)
+	}
+}
+
+// Tests that the reward ledger follows the canonical chain across reorgs and
+// rewinds, while keeping the rewards of side chain blocks addressable by hash.
+func TestRewardLedgerReorg(t *testing.T) {
+	db, chain, err := newCanonical(ethash.NewFaker(), 0, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer chain.Stop()
+
+	ledger := &RewardLedger{db: db, chain: chain, config: chain.Config(), quit: make(chan struct{})}
+
+	// Index an easy chain first, then reorg to a longer and heavier one
+	easy, _ := GenerateChain(params.TestChainConfig, chain.CurrentBlock(), ethash.NewFaker(), db, 4, func(i int, b *BlockGen) {
+		b.OffsetTime(60)
+	})
+	heavy, _ := GenerateChain(params.TestChainConfig, chain.CurrentBlock(), ethash.NewFaker(), db, 6, func(i int, b *BlockGen) {
+		b.OffsetTime(-9)
+		b.SetCoinbase(common.Address{0x01})
+	})
+	if _, err := chain.InsertChain(easy); err != nil {
+		t.Fatalf("failed to insert easy chain: %v", err)
+	}
+	ledger.indexHead(chain.CurrentBlock().Header())
+	checkRewardLedger(t, db, chain)
+
+	if _, err := chain.InsertChain(heavy); err != nil {
+		t.Fatalf("failed to insert heavy chain: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != heavy[len(heavy)-1].Hash() {
+		t.Fatalf("heavy chain didn't become canonical")
+	}
+	ledger.indexHead(chain.CurrentBlock().Header())
+	checkRewardLedger(t, db, chain)
+
+	// Rewards of the reorged out blocks must remain available by hash
+	for _, block := range easy {
+		if !rawdb.HasBlockRewards(db, block.Hash(), block.NumberU64()) {
+			t.Fatalf("block %d: side chain rewards missing", block.NumberU64())
+		}
+	}
+	// Side chain blocks that never became canonical are indexed by hash only
+	side := types.NewBlockWithHeader(&types.Header{Number: heavy[2].Number(), ParentHash: heavy[1].Hash(), CoinbaseTxs: []byte{0x01, 0xc0}})
+	ledger.indexSide(side.Header())
+	if rewards := rawdb.ReadBlockRewards(db, side.Hash(), side.NumberU64()); !bytes.Equal(rewards, side.Header().CoinbaseTxs) {
+		t.Fatalf("side block rewards mismatch: have %x, want %x", rewards, side.Header().CoinbaseTxs)
+	}
+	checkRewardLedger(t, db, chain)
+
+	// Rewinding the chain must drop the mappings above the new head
+	chain.SetHead(3)
+	ledger.indexHead(chain.CurrentBlock().Header())
+	checkRewardLedger(t, db, chain)
+}
+
+// rewardLedgerHeaders writes a canonical header chain on top of parent, every
+// header rewarding the given addresses with its number in each category.
+func rewardLedgerHeaders(t *testing.T, db ethdb.Database, parent *types.Header, n int, seed byte, addrs ...common.Address) []*types.Header {
+	var headers []*types.Header
+	for i := 0; i < n; i++ {
+		number := new(big.Int).Add(parent.Number, common.Big1)
+
+		var rewards []types.CoinbaseTx
+		for _, addr := range addrs {
+			rewards = append(rewards, types.CoinbaseTx{Address: addr, PocReward: number, PowReward: number, PoolReward: number, PostReward: number})
+		}
+		blob, err := types.EncodeCoinbaseTxs(rewards)
+		if err != nil {
+			t.Fatalf("failed to encode rewards: %v", err)
+		}
+		header := &types.Header{ParentHash: parent.Hash(), Number: number, Extra: []byte{seed}, CoinbaseTxs: blob}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), number.Uint64())
+
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+// checkAddressRewards verifies the address index and totals of addr against
+// the rewards expected at the given canonical block numbers.
+func checkAddressRewards(t *testing.T, db ethdb.Database, addr common.Address, numbers []uint64) {
+	t.Helper()
+
+	rewards := rawdb.ReadAddressRewards(db, addr, 0, math.MaxUint64, 0)
+	if len(rewards) != len(numbers) {
+		t.Fatalf("%x: reward count mismatch: have %d, want %d", addr, len(rewards), len(numbers))
+	}
+	total := new(big.Int)
+	for i, reward := range rewards {
+		if reward.BlockNumber != numbers[i] || reward.PocReward.Uint64() != numbers[i] {
+			t.Fatalf("%x: reward %d mismatch: have block %d poc %v, want %d", addr, i, reward.BlockNumber, reward.PocReward, numbers[i])
+		}
+		if hash := rawdb.ReadCanonicalHash(db, numbers[i]); reward.BlockHash != hash {
+			t.Fatalf("%x: reward %d hash mismatch: have %x, want %x", addr, i, reward.BlockHash, hash)
+		}
+		total.Add(total, reward.PocReward)
+	}
+	summary := rawdb.ReadAddressRewardSummary(db, addr)
+	if len(numbers) == 0 {
+		if summary != nil {
+			t.Fatalf("%x: stale summary: %+v", addr, summary)
+		}
+		return
+	}
+	if summary == nil || summary.Blocks != uint64(len(numbers)) {
+		t.Fatalf("%x: summary block count mismatch: have %+v, want %d", addr, summary, len(numbers))
+	}
+	for _, amount := range []*big.Int{summary.PocReward, summary.PowReward, summary.PoolReward, summary.PostReward} {
+		if amount.Cmp(total) != 0 {
+			t.Fatalf("%x: summary total mismatch: have %v, want %v", addr, amount, total)
+		}
+	}
+}
+
+// Tests that the address reward index and totals are credited and reverted as
+// the canonical chain reorgs and rewinds.
+func TestRewardLedgerAddressIndex(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = &types.Header{Number: common.Big0}
+		miner   = common.Address{0x01}
+		rival   = common.Address{0x02}
+		config  = &params.ChainConfig{NUC: &params.NUCConfig{CoinbaseTxsBlock: common.Big0}}
+		ledger  = &RewardLedger{db: db, config: config, quit: make(chan struct{})}
+	)
+	rawdb.WriteHeader(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), 0)
+
+	// Index a chain rewarding the miner only
+	first := rewardLedgerHeaders(t, db, genesis, 5, 0, miner)
+	ledger.indexHead(first[4])
+	checkAddressRewards(t, db, miner, []uint64{1, 2, 3, 4, 5})
+	checkAddressRewards(t, db, rival, nil)
+
+	// Reorg to a shorter fork from block 2 rewarding both, the miner loses 3-5
+	fork := rewardLedgerHeaders(t, db, first[1], 2, 1, miner, rival)
+	rawdb.DeleteCanonicalHash(db, 5)
+	ledger.indexHead(fork[1])
+	checkAddressRewards(t, db, miner, []uint64{1, 2, 3, 4})
+	checkAddressRewards(t, db, rival, []uint64{3, 4})
+
+	// The reorged out blocks remain available by hash
+	for _, header := range first[2:] {
+		if !bytes.Equal(rawdb.ReadBlockRewards(db, header.Hash(), header.Number.Uint64()), header.CoinbaseTxs) {
+			t.Fatalf("block %d: side chain rewards missing", header.Number)
+		}
+	}
+	// Rewind below the fork, dropping the rewound blocks entirely
+	for _, header := range fork {
+		rawdb.DeleteHeader(db, header.Hash(), header.Number.Uint64())
+		rawdb.DeleteCanonicalHash(db, header.Number.Uint64())
+	}
+	ledger.indexHead(first[1])
+	checkAddressRewards(t, db, miner, []uint64{1, 2})
+	checkAddressRewards(t, db, rival, nil)
+
+	for _, header := range fork {
+		if rawdb.HasBlockRewards(db, header.Hash(), header.Number.Uint64()) {
+			t.Fatalf("block %d: rewound block rewards retained", header.Number)
+		}
+	}
+}
+
+// Tests that an existing chain is indexed in bounded steps, leaving the rewards
+// of the frozen blocks to the ancient store.
+func TestRewardLedgerBackfill(t *testing.T) {
+	frdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temp freezer dir: %v", err)
+	}
+	defer os.RemoveAll(frdir)
+
+	kvdb := rawdb.NewMemoryDatabase()
+	db, err := rawdb.NewDatabaseWithFreezer(kvdb, frdir, "")
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	var (
+		genesis = &types.Header{Number: common.Big0}
+		miner   = common.Address{0x01}
+		config  = &params.ChainConfig{NUC: &params.NUCConfig{CoinbaseTxsBlock: common.Big0}}
+		ledger  = &RewardLedger{db: db, config: config, quit: make(chan struct{})}
+		frozen  = 10
+	)
+	rawdb.WriteHeader(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), 0)
+	headers := rewardLedgerHeaders(t, db, genesis, 2*rewardLedgerStepBlocks+10, 0, miner)
+
+	// Freeze the first blocks, the way the freezer moves them out of the key-value store
+	rawdb.WriteAncientBlock(db, types.NewBlockWithHeader(genesis), nil, common.Big0)
+	for _, header := range headers[:frozen-1] {
+		rawdb.WriteAncientBlock(db, types.NewBlockWithHeader(header), nil, common.Big0)
+		rawdb.DeleteHeader(db, header.Hash(), header.Number.Uint64())
+		rawdb.DeleteCanonicalHash(db, header.Number.Uint64())
+	}
+	head := headers[len(headers)-1]
+	for step := 1; step <= 3; step++ {
+		if synced := ledger.indexHead(head); synced != (step == 3) {
+			t.Fatalf("step %d: sync status mismatch: have %v, want %v", step, synced, step == 3)
+		}
+		want := uint64(step*rewardLedgerStepBlocks - 1)
+		if step == 3 {
+			want = head.Number.Uint64()
+		}
+		if last := rawdb.ReadRewardLedgerHead(db); last == nil || *last != want {
+			t.Fatalf("step %d: ledger head mismatch: have %v, want %d", step, last, want)
+		}
+	}
+	numbers := make([]uint64, len(headers))
+	for i := range numbers {
+		numbers[i] = uint64(i + 1)
+	}
+	checkAddressRewards(t, db, miner, numbers)
+
+	// Frozen blocks are served from the ancient store, nothing written for them
+	for _, header := range headers[:frozen-1] {
+		number := header.Number.Uint64()
+		if rawdb.HasBlockRewards(kvdb, header.Hash(), number) || rawdb.ReadCanonicalRewardsHash(kvdb, number) != (common.Hash{}) {
+			t.Fatalf("block %d: frozen rewards stored in the key-value store", number)
+		}
+		if rewards, hash := rawdb.ReadCanonicalBlockRewards(db, number); hash != header.Hash() || !bytes.Equal(rewards, header.CoinbaseTxs) {
+			t.Fatalf("block %d: frozen rewards mismatch: have %x %x", number, hash, rewards)
+		}
+	}
+	// An indexed chain only gets its new blocks indexed
+	more := rewardLedgerHeaders(t, db, head, 3, 0, miner)
+	if !ledger.indexHead(more[2]) {
+		t.Fatalf("new blocks not indexed in one step")
+	}
+	checkAddressRewards(t, db, miner, append(numbers, numbers[len(numbers)-1]+1, numbers[len(numbers)-1]+2, numbers[len(numbers)-1]+3))
+}
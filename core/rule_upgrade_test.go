@@ -0,0 +1,85 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that a scheduled rule contract upgrade replaces the code and the given
+// storage slots of the contract at its block, and that blocks built and imported
+// across it agree on the resulting state.
+func TestNUCRuleUpgrade(t *testing.T) {
+	var (
+		legacy   = common.FromHex("6001600055")
+		upgraded = common.FromHex("602a60005260206000f3")
+		kept     = common.Hash{0x05}
+		replaced = common.Hash{0x01}
+	)
+	config := *params.TestChainConfig
+	config.NUC = &params.NUCConfig{RuleUpgrades: []params.NUCRuleUpgrade{{
+		Block:   big.NewInt(2),
+		Version: params.NUCRuleV1,
+		Code:    upgraded,
+		Storage: map[common.Hash]common.Hash{replaced: {0x02}},
+	}}}
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{params.NUCRuleContractAddress: {
+				Code:    legacy,
+				Balance: big.NewInt(1),
+				Storage: map[common.Hash]common.Hash{kept: {0x06}, replaced: {0x07}},
+			}},
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), db, 3, nil)
+
+	chaindb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(chaindb)
+	chain, err := NewBlockChain(chaindb, nil, &config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	for _, tt := range []struct {
+		number   uint64
+		code     []byte
+		replaced common.Hash
+	}{
+		{1, legacy, common.Hash{0x07}},
+		{2, upgraded, common.Hash{0x02}},
+		{3, upgraded, common.Hash{0x02}},
+	} {
+		statedb, err := chain.StateAt(chain.GetBlockByNumber(tt.number).Root())
+		if err != nil {
+			t.Fatalf("block %d: failed to open state: %v", tt.number, err)
+		}
+		if have := statedb.GetCode(params.NUCRuleContractAddress); !bytes.Equal(have, tt.code) {
+			t.Errorf("block %d: code mismatch: have %x, want %x", tt.number, have, tt.code)
+		}
+		if have := statedb.GetState(params.NUCRuleContractAddress, replaced); have != tt.replaced {
+			t.Errorf("block %d: replaced slot mismatch: have %x, want %x", tt.number, have, tt.replaced)
+		}
+		if have, want := statedb.GetState(params.NUCRuleContractAddress, kept), (common.Hash{0x06}); have != want {
+			t.Errorf("block %d: kept slot mismatch: have %x, want %x", tt.number, have, want)
+		}
+		if have := statedb.GetBalance(params.NUCRuleContractAddress); have.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("block %d: balance mismatch: have %v, want %v", tt.number, have, 1)
+		}
+	}
+}
@@ -25,6 +25,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 var toAddr = common.BytesToAddress
@@ -87,6 +90,39 @@ func TestDump(t *testing.T) {
 	}
 }
 
+// Tests that the accumulated PoC balance of an account is covered by its Merkle
+// proof.
+func TestAccountProofPocBalance(t *testing.T) {
+	s := newStateTest()
+	address := toAddr([]byte{0x01})
+	s.state.AddBalance(address, big.NewInt(22))
+	s.state.AddAllPocBalance(address, big.NewInt(120))
+	root, _ := s.state.Commit(false)
+
+	proof, err := s.state.GetProof(address)
+	if err != nil {
+		t.Fatalf("failed to prove account: %v", err)
+	}
+	proofDb := memorydb.New()
+	for _, node := range proof {
+		proofDb.Put(crypto.Keccak256(node), node)
+	}
+	blob, _, err := trie.VerifyProof(root, crypto.Keccak256(address.Bytes()), proofDb)
+	if err != nil {
+		t.Fatalf("failed to verify account proof: %v", err)
+	}
+	var account Account
+	if err := rlp.DecodeBytes(blob, &account); err != nil {
+		t.Fatalf("failed to decode proven account: %v", err)
+	}
+	if account.AllPocBalance.Cmp(big.NewInt(120)) != 0 {
+		t.Errorf("proven PoC balance mismatch: have %v, want %v", account.AllPocBalance, 120)
+	}
+	if account.Balance.Cmp(big.NewInt(22)) != 0 {
+		t.Errorf("proven balance mismatch: have %v, want %v", account.Balance, 22)
+	}
+}
+
 func TestNull(t *testing.T) {
 	s := newStateTest()
 	address := common.HexToAddress("0x823140710bf13990e4500136726d8b55")
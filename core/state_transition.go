@@ -212,12 +212,19 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		vmerr error
 	)
 	if contractCreation {
-		contractObj := GetContractInstance(msg.Data())
-		if contractObj == nil {
-			return nil, 0, false, errors.New("contract data format wrong!")
+		kind, kindErr := st.contractKind()
+		if kindErr != nil {
+			if !evm.ChainConfig().IsNUCContractKindFailure(evm.BlockNumber) {
+				return nil, 0, false, kindErr
+			}
+			// Fail the creation the way the EVM does, consuming all the gas
+			st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+			st.gas, vmerr = 0, kindErr
+		} else {
+			contractObj := kind.New()
+			contractObj.SetEVM(evm)
+			ret, _, st.gas, vmerr = contractObj.Create(sender, st.data, st.gas, st.value)
 		}
-		contractObj.SetEVM(evm)
-		ret, _, st.gas, vmerr = contractObj.Create(sender, st.data, st.gas, st.value)
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
@@ -235,15 +242,69 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		}
 	}
 	st.refundGas()
-	// fee used 30%
-	gasFee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
-	coinbaseFee := new(big.Int).Mul(gasFee, big.NewInt(30))
-	coinbaseFee = new(big.Int).Div(coinbaseFee, big.NewInt(100))
-	st.state.AddBalance(st.evm.Coinbase, coinbaseFee)
+	st.payFee()
 
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// contractKind looks up the contract kind selected by the creation data and
+// charges the gas of its deployment. From the contract kind failure fork on, a
+// lookup error only fails the creation, which the receipt can't tell apart from
+// other failures: the transaction pool and eth_call report the typed error to
+// the submitter instead.
+func (st *StateTransition) contractKind() (*ContractKind, error) {
+	kind, err := LookupContractKind(st.evm.ChainConfig(), st.evm.BlockNumber, st.data)
+	if err != nil {
+		return nil, err
+	}
+	if kind.Gas != nil {
+		gas, err := kind.Gas(st.data)
+		if err != nil {
+			return nil, err
+		}
+		if err := st.useGas(gas); err != nil {
+			return nil, err
+		}
+	}
+	return kind, nil
+}
+
+// payFee splits the fee of the transaction according to the fee policy in force.
+// The participants' share is pooled until the block is finalized, while the
+// burnt share simply isn't credited to anyone.
+func (st *StateTransition) payFee() {
+	policy := st.evm.ChainConfig().NUCFeePolicy(st.evm.BlockNumber)
+	split := splitFee(policy, st.gasUsed(), st.gasPrice)
+
+	st.state.AddBalance(st.evm.Coinbase, split.Coinbase)
+	if split.Participants.Sign() > 0 {
+		st.state.AddBalance(params.NUCFeePoolAddress, split.Participants)
+	}
+	if split.Team.Sign() > 0 {
+		st.state.AddBalance(policy.Treasury, split.Team)
+	}
+}
+
+// splitFee splits the fee of a transaction according to the given fee policy.
+// Without a policy, the legacy split credits 30% of the fee to the coinbase and
+// destroys the remaining 70%, the consensus engine minting the participants'
+// and the team's fees on its own when finalizing the block.
+func splitFee(policy *params.NUCFeePolicy, gasUsed uint64, gasPrice *big.Int) *params.NUCFeeSplit {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), gasPrice)
+	if policy != nil {
+		return policy.Split(fee)
+	}
+	coinbase := new(big.Int).Mul(fee, big.NewInt(30))
+	coinbase.Div(coinbase, big.NewInt(100))
+
+	return &params.NUCFeeSplit{
+		Coinbase:     coinbase,
+		Participants: new(big.Int),
+		Team:         new(big.Int),
+		Burn:         fee.Sub(fee, coinbase),
+	}
+}
+
 func (st *StateTransition) refundGas() {
 	// Apply refund counter, capped to half of the used gas.
 	refund := st.gasUsed() / 2
@@ -0,0 +1,258 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// supplyLedgerHeadChanSize is the size of channel listening to ChainHeadEvent.
+	supplyLedgerHeadChanSize = 10
+
+	// supplyLedgerSideChanSize is the size of channel listening to ChainSideEvent.
+	supplyLedgerSideChanSize = 10
+)
+
+// errMissingBlockData is returned if the body or receipts of a block to account
+// for aren't available (yet).
+var errMissingBlockData = errors.New("missing block data")
+
+// SupplyLedger accounts for the coin supply of the blocks in the chain database.
+// The totals of every block build upon those of its parent and are stored per
+// block hash, so side chains are accounted for too and reorgs don't need to
+// rewrite anything.
+type SupplyLedger struct {
+	db       ethdb.Database
+	chain    *BlockChain
+	reporter consensus.SupplyReporter
+
+	stuck bool // Set if a block's issuance can't be accounted, halting the ledger
+
+	headCh  chan ChainHeadEvent
+	sideCh  chan ChainSideEvent
+	headSub event.Subscription
+	sideSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSupplyLedger creates a supply ledger on top of the chain's database and
+// starts accounting from the current head. Nil is returned if the consensus
+// engine can't report the issuance of blocks.
+func NewSupplyLedger(db ethdb.Database, chain *BlockChain) *SupplyLedger {
+	reporter, ok := chain.Engine().(consensus.SupplyReporter)
+	if !ok {
+		return nil
+	}
+	ledger := &SupplyLedger{
+		db:       db,
+		chain:    chain,
+		reporter: reporter,
+		headCh:   make(chan ChainHeadEvent, supplyLedgerHeadChanSize),
+		sideCh:   make(chan ChainSideEvent, supplyLedgerSideChanSize),
+		quit:     make(chan struct{}),
+	}
+	ledger.headSub = chain.SubscribeChainHeadEvent(ledger.headCh)
+	ledger.sideSub = chain.SubscribeChainSideEvent(ledger.sideCh)
+
+	ledger.wg.Add(1)
+	go ledger.loop()
+	return ledger
+}
+
+// Stop terminates the accounting and waits for the pending writes to finish.
+func (l *SupplyLedger) Stop() {
+	l.headSub.Unsubscribe()
+	l.sideSub.Unsubscribe()
+	close(l.quit)
+	l.wg.Wait()
+}
+
+// loop keeps the ledger in sync with the chain until stopped.
+func (l *SupplyLedger) loop() {
+	defer l.wg.Done()
+
+	l.account(l.chain.CurrentBlock().Header())
+	for {
+		select {
+		case ev := <-l.headCh:
+			l.account(ev.Block.Header())
+
+		case ev := <-l.sideCh:
+			l.account(ev.Block.Header())
+
+		case <-l.headSub.Err():
+			return
+		case <-l.sideSub.Err():
+			return
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// account stores the supply of the given block, first accounting for any of its
+// ancestors not yet in the ledger, in ascending order.
+func (l *SupplyLedger) account(header *types.Header) {
+	if l.stuck {
+		return
+	}
+	type blockID struct {
+		hash   common.Hash
+		number uint64
+	}
+	var (
+		pending []blockID
+		parent  *rawdb.BlockSupply
+		hash    = header.Hash()
+		number  = header.Number.Uint64()
+	)
+	for {
+		if parent = rawdb.ReadBlockSupply(l.db, hash, number); parent != nil {
+			break
+		}
+		pending = append(pending, blockID{hash, number})
+		if number == 0 {
+			break
+		}
+		header := rawdb.ReadHeader(l.db, hash, number)
+		if header == nil {
+			log.Warn("Missing header for supply ledger", "number", number, "hash", hash)
+			return
+		}
+		hash, number = header.ParentHash, number-1
+	}
+	batch := l.db.NewBatch()
+	for i := len(pending) - 1; i >= 0; i-- {
+		supply, split, err := l.blockSupply(pending[i].hash, pending[i].number, parent)
+		if err == errMissingBlockData {
+			log.Debug("Block supply not accountable yet", "number", pending[i].number, "hash", pending[i].hash)
+			break
+		}
+		if err != nil {
+			log.Warn("Failed to account block supply, halting ledger", "number", pending[i].number, "hash", pending[i].hash, "err", err)
+			l.stuck = true
+			break
+		}
+		rawdb.WriteBlockSupply(batch, pending[i].hash, pending[i].number, supply)
+		rawdb.WriteBlockFeeSplit(batch, pending[i].hash, pending[i].number, split)
+		parent = supply
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to write supply ledger", "err", err)
+			}
+			batch.Reset()
+
+			select {
+			case <-l.quit:
+				return
+			default:
+			}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write supply ledger", "err", err)
+	}
+}
+
+// blockSupply accounts for the coins created and destroyed by a block on top of
+// its parent's totals, along with the split of its transaction fees. The genesis
+// allocation is accounted as minted by the genesis block.
+func (l *SupplyLedger) blockSupply(hash common.Hash, number uint64, parent *rawdb.BlockSupply) (*rawdb.BlockSupply, *rawdb.BlockFeeSplit, error) {
+	split := &rawdb.BlockFeeSplit{
+		Coinbase:     new(big.Int),
+		Participants: new(big.Int),
+		Team:         new(big.Int),
+		Burnt:        new(big.Int),
+	}
+	if number == 0 {
+		header := rawdb.ReadHeader(l.db, hash, number)
+		if header == nil {
+			return nil, nil, errMissingBlockData
+		}
+		alloc, err := genesisSupply(l.db, header.Root)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &rawdb.BlockSupply{
+			Minted:      alloc,
+			Fees:        new(big.Int),
+			Team:        new(big.Int),
+			Burnt:       new(big.Int),
+			TotalMinted: new(big.Int).Set(alloc),
+			TotalFees:   new(big.Int),
+			TotalTeam:   new(big.Int),
+			TotalBurnt:  new(big.Int),
+		}, split, nil
+	}
+	block := rawdb.ReadBlock(l.db, hash, number)
+	if block == nil {
+		return nil, nil, errMissingBlockData
+	}
+	receipts := rawdb.ReadReceipts(l.db, hash, number, l.chain.Config())
+	if len(receipts) != len(block.Transactions()) {
+		return nil, nil, errMissingBlockData
+	}
+	issuance, err := l.reporter.BlockIssuance(l.chain, block)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy := l.chain.Config().NUCFeePolicy(block.Number())
+	for i, tx := range block.Transactions() {
+		fee := splitFee(policy, receipts[i].GasUsed, tx.GasPrice())
+		split.Coinbase.Add(split.Coinbase, fee.Coinbase)
+		split.Participants.Add(split.Participants, fee.Participants)
+		split.Team.Add(split.Team, fee.Team)
+		split.Burnt.Add(split.Burnt, fee.Burn)
+	}
+	// The legacy fees of the participants and the team are minted instead
+	if policy == nil {
+		split.Participants.Set(issuance.Fees)
+		split.Team.Set(issuance.Team)
+	}
+	return &rawdb.BlockSupply{
+		Minted:      issuance.Minted,
+		Fees:        issuance.Fees,
+		Team:        issuance.Team,
+		Burnt:       split.Burnt,
+		TotalMinted: new(big.Int).Add(parent.TotalMinted, issuance.Minted),
+		TotalFees:   new(big.Int).Add(parent.TotalFees, issuance.Fees),
+		TotalTeam:   new(big.Int).Add(parent.TotalTeam, issuance.Team),
+		TotalBurnt:  new(big.Int).Add(parent.TotalBurnt, split.Burnt),
+	}, split, nil
+}
+
+// genesisSupply sums the balances of all the accounts in the genesis state.
+func genesisSupply(db ethdb.Database, root common.Hash) (*big.Int, error) {
+	tr, err := state.NewDatabase(db).OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		var account state.Account
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			return nil, fmt.Errorf("invalid genesis account: %v", err)
+		}
+		total.Add(total, account.Balance)
+	}
+	return total, it.Err
+}
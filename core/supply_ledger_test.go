@@ -0,0 +1,189 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// checkSupplyLedger verifies that the supply accounted for every block of the
+// chain ending in head chains onto its parent and matches the sum of all the
+// balances in the block's state.
+func checkSupplyLedger(t *testing.T, db ethdb.Database, head *types.Header) {
+	t.Helper()
+
+	for header := head; ; header = rawdb.ReadHeader(db, header.ParentHash, header.Number.Uint64()-1) {
+		number := header.Number.Uint64()
+		supply := rawdb.ReadBlockSupply(db, header.Hash(), number)
+		if supply == nil {
+			t.Fatalf("block %d: supply not accounted", number)
+		}
+		balances, err := genesisSupply(db, header.Root)
+		if err != nil {
+			t.Fatalf("block %d: failed to sum balances: %v", number, err)
+		}
+		if have := supply.Supply(); have.Cmp(balances) != 0 {
+			t.Fatalf("block %d: supply mismatch: have %v, want %v", number, have, balances)
+		}
+		if number == 0 {
+			return
+		}
+		parent := rawdb.ReadBlockSupply(db, header.ParentHash, number-1)
+		if parent == nil {
+			t.Fatalf("block %d: parent supply not accounted", number)
+		}
+		if want := new(big.Int).Add(parent.TotalBurnt, supply.Burnt); supply.TotalBurnt.Cmp(want) != 0 {
+			t.Fatalf("block %d: total burnt mismatch: have %v, want %v", number, supply.TotalBurnt, want)
+		}
+		if want := new(big.Int).Add(parent.TotalMinted, supply.Minted); supply.TotalMinted.Cmp(want) != 0 {
+			t.Fatalf("block %d: total minted mismatch: have %v, want %v", number, supply.TotalMinted, want)
+		}
+	}
+}
+
+// Tests that the supply ledger accounts for the canonical chain and its side
+// chains, in line with the actual balances.
+func TestSupplyLedger(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		signer  = types.HomesteadSigner{}
+	)
+	// Rewards in the legacy encoding can't be accounted
+	config.NUC = &params.NUCConfig{CoinbaseTxsBlock: common.Big0}
+
+	gspec := &Genesis{Config: &config, Alloc: GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}}}
+	genesis := gspec.MustCommit(db)
+	// Transactions are charged on their gas limit when redistributing the fees
+	transfers := func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1000), 2*params.TxGas, big.NewInt(1e9), nil), signer, key)
+		b.AddTx(tx)
+	}
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 4, transfers)
+	fork, _ := GenerateChain(gspec.Config, blocks[1], ethash.NewFaker(), db, 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0x02})
+		transfers(i, b)
+	})
+	archive := &CacheConfig{TrieCleanLimit: 256, TrieDirtyLimit: 256, TrieDirtyDisabled: true, TrieTimeLimit: 5 * time.Minute}
+	chain, err := NewBlockChain(db, archive, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	ledger := &SupplyLedger{db: db, chain: chain, reporter: chain.Engine().(consensus.SupplyReporter), quit: make(chan struct{})}
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	ledger.account(chain.CurrentBlock().Header())
+	checkSupplyLedger(t, db, chain.CurrentBlock().Header())
+
+	supply := rawdb.ReadBlockSupply(db, blocks[0].Hash(), 1)
+	if supply.Minted.Cmp(ethash.FrontierBlockReward) != 0 {
+		t.Errorf("minted mismatch: have %v, want %v", supply.Minted, ethash.FrontierBlockReward)
+	}
+	if supply.Team.Sign() <= 0 || supply.Burnt.Sign() <= 0 {
+		t.Errorf("fees not accounted: team %v, burnt %v", supply.Team, supply.Burnt)
+	}
+	// Reorg to the longer fork, accounting only for the new blocks
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("fork didn't become canonical")
+	}
+	ledger.account(chain.CurrentBlock().Header())
+	checkSupplyLedger(t, db, chain.CurrentBlock().Header())
+	checkSupplyLedger(t, db, blocks[len(blocks)-1].Header())
+}
+
+// Tests that the fee policy splits the actual transaction fees without creating
+// or losing coins, and that the split is recorded along with the supply.
+func TestSupplyLedgerFeePolicy(t *testing.T) {
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.Address{0x03}
+		treasury = common.Address{0xee}
+		config   = *params.TestChainConfig
+		signer   = types.HomesteadSigner{}
+	)
+	config.NUC = &params.NUCConfig{CoinbaseTxsBlock: common.Big0, FeePolicies: []params.NUCFeePolicy{
+		{Block: big.NewInt(2), Coinbase: 20, Participants: 60, Team: 10, Burn: 10, Treasury: treasury},
+	}}
+	gspec := &Genesis{Config: &config, Alloc: GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}}}
+	genesis := gspec.MustCommit(db)
+
+	// Over-provision the gas limit to check that only the gas used is charged
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbase)
+		for j := 0; j < 2; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1000), 3*params.TxGas, big.NewInt(1e9+1), nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	archive := &CacheConfig{TrieCleanLimit: 256, TrieDirtyLimit: 256, TrieDirtyDisabled: true, TrieTimeLimit: 5 * time.Minute}
+	chain, err := NewBlockChain(db, archive, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	ledger := &SupplyLedger{db: db, chain: chain, reporter: chain.Engine().(consensus.SupplyReporter), quit: make(chan struct{})}
+	ledger.account(chain.CurrentBlock().Header())
+	checkSupplyLedger(t, db, chain.CurrentBlock().Header())
+
+	earned := new(big.Int)
+	for _, block := range blocks {
+		split := rawdb.ReadBlockFeeSplit(db, block.Hash(), block.NumberU64())
+		if split == nil {
+			t.Fatalf("block %d: fee split not recorded", block.NumberU64())
+		}
+		earned.Add(earned, split.Coinbase)
+
+		statedb, err := chain.StateAt(block.Root())
+		if err != nil {
+			t.Fatalf("block %d: missing state: %v", block.NumberU64(), err)
+		}
+		if pool := statedb.GetBalance(params.NUCFeePoolAddress); pool.Sign() != 0 {
+			t.Errorf("block %d: fee pool not shared out: %v", block.NumberU64(), pool)
+		}
+		if balance := statedb.GetBalance(coinbase); balance.Cmp(earned) != 0 {
+			t.Errorf("block %d: coinbase balance mismatch: have %v, want %v", block.NumberU64(), balance, earned)
+		}
+		if block.NumberU64() < 2 {
+			continue
+		}
+		// Fees are charged on the gas used and the split adds up to them
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(block.GasUsed()), big.NewInt(1e9+1))
+		total := new(big.Int).Add(split.Coinbase, split.Participants)
+		total.Add(total, split.Team)
+		total.Add(total, split.Burnt)
+		if total.Cmp(fee) != 0 {
+			t.Errorf("block %d: fee split mismatch: have %v, want %v", block.NumberU64(), total, fee)
+		}
+		supply := rawdb.ReadBlockSupply(db, block.Hash(), block.NumberU64())
+		if supply.Burnt.Cmp(split.Burnt) != 0 || supply.Team.Sign() != 0 || supply.Minted.Cmp(ethash.FrontierBlockReward) != 0 {
+			t.Errorf("block %d: supply mismatch: minted %v, team %v, burnt %v", block.NumberU64(), supply.Minted, supply.Team, supply.Burnt)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// revertReasonSelector is the selector of the Error(string) revert reasons
+// emitted by Solidity's require and revert.
+var revertReasonSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// RevertError is returned by a system call reverted by the called contract.
+type RevertError struct {
+	Reason string // Decoded Error(string) reason, empty if there is none
+	Data   []byte // Raw revert data
+}
+
+// Error implements error.
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// newRevertError creates a revert error out of the revert data of a call.
+func newRevertError(data []byte) *RevertError {
+	return &RevertError{Reason: unpackRevertReason(data), Data: common.CopyBytes(data)}
+}
+
+// unpackRevertReason decodes an Error(string) revert reason, returning an empty
+// string if the data holds none.
+func unpackRevertReason(data []byte) string {
+	if len(data) < 4+64 || !bytes.Equal(data[:4], revertReasonSelector) {
+		return ""
+	}
+	data = data[4:]
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+		return ""
+	}
+	size := new(big.Int).SetBytes(data[offset.Uint64() : offset.Uint64()+32])
+	start := offset.Uint64() + 32
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start {
+		return ""
+	}
+	return string(data[start : start+size.Uint64()])
+}
+
+// SystemCall executes a read-only call of a contract on behalf of the protocol,
+// e.g. for the consensus engine to read the NUC rule contract while finalizing a
+// block. The call runs with static call semantics, on a copy of the given state:
+// unmodified accounts are read through from the shared trie, while nothing the
+// call does, touches included, ever reaches the given state. No gas is paid and
+// no nonce is bumped.
+//
+// A call reverted by the contract returns a *RevertError, a call attempting to
+// modify the state fails with vm.ErrWriteProtection.
+func SystemCall(config *params.ChainConfig, chain ChainContext, header *types.Header, statedb *state.StateDB, vmConfig vm.Config, from, to common.Address, input []byte, gas uint64) ([]byte, error) {
+	msg := types.NewMessage(from, &to, 0, new(big.Int), gas, new(big.Int), input, false)
+	context := NewEVMContext(msg, header, chain, &header.Coinbase)
+	evm := vm.NewEVM(context, statedb.Copy(), config, vmConfig)
+
+	res, _, err := evm.StaticCall(vm.AccountRef(from), to, input, gas)
+	if err == vm.ErrExecutionReverted {
+		return nil, newRevertError(res)
+	}
+	return res, err
+}
+
+// ApplySystemMessage executes a call of a contract on behalf of the protocol as
+// a regular message sent by from, without gas price nor value, applying all the
+// state changes of the message to the given state: the sender's nonce is bumped
+// and the block's coinbase is touched, besides whatever the call does. This is
+// how the consensus engine called the NUC rule contract before system calls, so
+// historical blocks depend on it. Failed reports whether the call failed in the
+// EVM, the error whether the message could be applied at all.
+func ApplySystemMessage(config *params.ChainConfig, chain ChainContext, header *types.Header, statedb vm.StateDB, vmConfig vm.Config, from, to common.Address, input []byte, gas uint64) ([]byte, bool, error) {
+	msg := types.NewMessage(from, &to, 0, new(big.Int), gas, new(big.Int), input, false)
+	context := NewEVMContext(msg, header, chain, &header.Coinbase)
+	evm := vm.NewEVM(context, statedb, config, vmConfig)
+
+	res, _, failed, err := ApplyMessage(evm, msg, new(GasPool).AddGas(math.MaxUint64))
+	return res, failed, err
+}
+
+// SystemCall implements consensus.SystemCaller, running the call with the VM
+// configuration of the chain.
+func (bc *BlockChain) SystemCall(header *types.Header, statedb *state.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, error) {
+	return SystemCall(bc.chainConfig, bc, header, statedb, bc.vmConfig, from, to, input, gas)
+}
+
+// ApplySystemMessage implements consensus.SystemCaller, running the call with
+// the VM configuration of the chain.
+func (bc *BlockChain) ApplySystemMessage(header *types.Header, statedb vm.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, bool, error) {
+	return ApplySystemMessage(bc.chainConfig, bc, header, statedb, bc.vmConfig, from, to, input, gas)
+}
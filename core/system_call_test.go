@@ -0,0 +1,112 @@
+// Copyright 2019 The nuc Team
+
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// systemCallChain is a chain context without any ancestors, system calls always
+// naming the author explicitly.
+type systemCallChain struct{}
+
+func (systemCallChain) Engine() consensus.Engine                    { return nil }
+func (systemCallChain) GetHeader(common.Hash, uint64) *types.Header { return nil }
+
+// Tests that system calls return the called code's output, report reverts and
+// write attempts as typed errors and never modify the state they run on.
+func TestSystemCall(t *testing.T) {
+	var (
+		caller  = common.Address{0x11}
+		returns = common.Address{0x01}
+		writes  = common.Address{0x02}
+		reverts = common.Address{0x03}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	// MSTORE(0, 42), RETURN(0, 32)
+	statedb.SetCode(returns, common.FromHex("602a60005260206000f3"))
+	// SSTORE(0, 1), STOP
+	statedb.SetCode(writes, common.FromHex("600160005500"))
+	// Error("nope") laid out in memory, REVERT(0, 100)
+	statedb.SetCode(reverts, common.FromHex(
+		"7f08c379a0"+strings.Repeat("00", 28)+"600052"+
+			"6020600452"+
+			"6004602452"+
+			"7f6e6f7065"+strings.Repeat("00", 28)+"604452"+
+			"60646000fd"))
+	root := statedb.IntermediateRoot(true)
+
+	header := &types.Header{
+		Number:        big.NewInt(1),
+		Coinbase:      common.Address{0xcb},
+		Difficulty:    big.NewInt(1),
+		NUCDifficulty: big.NewInt(1),
+		GasLimit:      10000000,
+	}
+	call := func(to common.Address) ([]byte, error) {
+		return SystemCall(params.TestChainConfig, systemCallChain{}, header, statedb, vm.Config{}, caller, to, nil, 100000)
+	}
+	res, err := call(returns)
+	if err != nil {
+		t.Fatalf("failed to call contract: %v", err)
+	}
+	if want := common.LeftPadBytes([]byte{42}, 32); !bytes.Equal(res, want) {
+		t.Errorf("result mismatch: have %x, want %x", res, want)
+	}
+	if _, err := call(writes); err != vm.ErrWriteProtection {
+		t.Errorf("write error mismatch: have %v, want %v", err, vm.ErrWriteProtection)
+	}
+	_, err = call(reverts)
+	revert, ok := err.(*RevertError)
+	if !ok {
+		t.Fatalf("revert error mismatch: have %v, want %T", err, revert)
+	}
+	if revert.Reason != "nope" || len(revert.Data) != 100 {
+		t.Errorf("revert mismatch: have %q with %d bytes, want %q with %d bytes", revert.Reason, len(revert.Data), "nope", 100)
+	}
+	if have := statedb.IntermediateRoot(true); have != root {
+		t.Errorf("state modified by system calls: have %x, want %x", have, root)
+	}
+	if statedb.Exist(caller) || statedb.Exist(header.Coinbase) {
+		t.Errorf("accounts touched by system calls")
+	}
+}
+
+// Tests that revert reasons are only decoded from well formed Error(string) data.
+func TestUnpackRevertReason(t *testing.T) {
+	reason := func(offset, size uint64, text string) []byte {
+		data := append(common.CopyBytes(revertReasonSelector), common.LeftPadBytes(new(big.Int).SetUint64(offset).Bytes(), 32)...)
+		data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(size).Bytes(), 32)...)
+		return append(data, common.RightPadBytes([]byte(text), 32)...)
+	}
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{reason(32, 4, "nope"), "nope"},
+		{reason(32, 0, ""), ""},
+		{reason(32, 33, "nope"), ""},
+		{reason(64, 4, "nope"), ""},
+		{reason(1<<62, 4, "nope"), ""},
+		{reason(32, 1<<63, "nope"), ""},
+		{reason(32, 4, "nope")[:67], ""},
+		{append([]byte{0, 0, 0, 0}, reason(32, 4, "nope")[4:]...), ""},
+		{nil, ""},
+	}
+	for i, tt := range tests {
+		if have := unpackRevertReason(tt.data); have != tt.want {
+			t.Errorf("test %d: reason mismatch: have %q, want %q", i, have, tt.want)
+		}
+	}
+}
@@ -218,7 +218,8 @@ type TxPool struct {
 	signer      types.Signer
 	mu          sync.RWMutex
 
-	istanbul bool // Fork indicator whether we are in the istanbul stage.
+	istanbul      bool     // Fork indicator whether we are in the istanbul stage.
+	pendingNumber *big.Int // Number of the pending block, contract kinds must be active in
 
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
@@ -550,6 +551,13 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Gas() < intrGas {
 		return ErrIntrinsicGas
 	}
+	// Contract creations must select a contract kind deployable in the pending
+	// block, reporting the reason to the submitter instead of mining a failure
+	if tx.To() == nil {
+		if _, err := LookupContractKind(pool.chainconfig, pool.pendingNumber, tx.Data()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1150,6 +1158,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
+	pool.pendingNumber = next
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -0,0 +1,77 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const (
+	multihashSHA256    = 0x12 // Multihash code of sha2-256
+	multihashSHA256Len = 32   // Digest length of sha2-256
+)
+
+// base58Alphabet is the bitcoin base58 alphabet used by IPFS.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var errInvalidCID = errors.New("invalid CID: not a base58 sha2-256 multihash")
+
+// DataMultihash returns the sha2-256 multihash of the payload of a data contract.
+// It identifies the raw payload bytes, which is not the same as the CID IPFS
+// assigns to a file chunked into a DAG.
+func DataMultihash(payload []byte) []byte {
+	digest := sha256.Sum256(payload)
+	return append([]byte{multihashSHA256, multihashSHA256Len}, digest[:]...)
+}
+
+// EncodeCID returns the CIDv0 (base58) representation of a multihash.
+func EncodeCID(multihash []byte) string {
+	var (
+		num   = new(big.Int).SetBytes(multihash)
+		radix = big.NewInt(58)
+		mod   = new(big.Int)
+		out   []byte
+	)
+	for num.Sign() > 0 {
+		num.DivMod(num, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range multihash {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// DecodeCID parses a CIDv0 into its multihash, only accepting sha2-256 ones.
+func DecodeCID(cid string) ([]byte, error) {
+	var (
+		num   = new(big.Int)
+		radix = big.NewInt(58)
+		zeros int
+	)
+	for zeros < len(cid) && cid[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	for _, c := range cid {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, errInvalidCID
+		}
+		num.Mul(num, radix)
+		num.Add(num, big.NewInt(int64(digit)))
+	}
+	multihash := append(make([]byte, zeros), num.Bytes()...)
+	if len(multihash) != 2+multihashSHA256Len || multihash[0] != multihashSHA256 || multihash[1] != multihashSHA256Len {
+		return nil, errInvalidCID
+	}
+	return multihash, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDataCID(t *testing.T) {
+	tests := []struct {
+		payload []byte
+		cid     string
+	}{
+		{nil, "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"},
+		{[]byte("hello world"), "QmaozNR7DZHQK1ZcU9p7QdrshMvXqWK6gpu5rmrkPdT3L4"},
+	}
+	for i, tt := range tests {
+		multihash := DataMultihash(tt.payload)
+		if have := EncodeCID(multihash); have != tt.cid {
+			t.Errorf("test %d: CID mismatch: have %s, want %s", i, have, tt.cid)
+		}
+		decoded, err := DecodeCID(tt.cid)
+		if err != nil {
+			t.Errorf("test %d: failed to decode CID: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(decoded, multihash) {
+			t.Errorf("test %d: multihash mismatch: have %x, want %x", i, decoded, multihash)
+		}
+	}
+	for _, cid := range []string{"", "Qm", "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1", "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR10", "zb2rhe5P4gXftAwvA4eXQ5HJwsER2owDyS9sKaQRRVQPn93bA"} {
+		if _, err := DecodeCID(cid); err == nil {
+			t.Errorf("invalid CID %q accepted", cid)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// CoinbaseTxsVersion is the leading byte of every RLP encoded header.CoinbaseTxs.
+	// It must be bumped whenever the layout of CoinbaseTx changes.
+	CoinbaseTxsVersion = 1
+
+	// legacyCoinbaseTxSize is the size of a reward in the legacy encoding: the
+	// address followed by the poc, pow and pool rewards on 8 bytes each.
+	legacyCoinbaseTxSize = common.AddressLength + 3*8
+)
+
+var (
+	ErrUnknownCoinbaseTxsVersion = errors.New("unknown coinbase txs version")
+	ErrUnsortedCoinbaseTxs       = errors.New("coinbase txs not sorted by address")
+	ErrLegacyCoinbaseTxsSize     = errors.New("legacy coinbase txs size not a multiple of 44 bytes")
+)
+
+// CoinbaseTx is the mining reward credited to a single address by a block, as
+// committed to by the header's CoinbaseTxs. Missing rewards are encoded as zero,
+// the rewards decoded are never nil.
+type CoinbaseTx struct {
+	Address    common.Address
+	PocReward  *big.Int
+	PowReward  *big.Int
+	PoolReward *big.Int
+	PostReward *big.Int
+}
+
+// Total returns the sum of all the reward categories, missing ones counting as
+// zero.
+func (tx *CoinbaseTx) Total() *big.Int {
+	total := new(big.Int)
+	for _, reward := range []*big.Int{tx.PocReward, tx.PowReward, tx.PoolReward, tx.PostReward} {
+		if reward != nil {
+			total.Add(total, reward)
+		}
+	}
+	return total
+}
+
+// EncodeCoinbaseTxs prefixes the RLP encoding of the given rewards with the
+// current version byte. The rewards are encoded as given, callers are expected
+// to sort them by address.
+func EncodeCoinbaseTxs(txs []CoinbaseTx) ([]byte, error) {
+	blob, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{CoinbaseTxsVersion}, blob...), nil
+}
+
+// DecodeCoinbaseTxs parses a header.CoinbaseTxs blob. Only the canonical encoding
+// is accepted: the version must be known, entries must be strictly sorted by
+// address and amounts must be minimal RLP integers, so that decoding and
+// re-encoding always yields the original bytes. An empty blob (e.g. the genesis
+// header) decodes into an empty reward list.
+func DecodeCoinbaseTxs(b []byte) ([]CoinbaseTx, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if b[0] != CoinbaseTxsVersion {
+		return nil, fmt.Errorf("%v: %d", ErrUnknownCoinbaseTxsVersion, b[0])
+	}
+	var txs []CoinbaseTx
+	if err := rlp.DecodeBytes(b[1:], &txs); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(txs); i++ {
+		if bytes.Compare(txs[i-1].Address[:], txs[i].Address[:]) >= 0 {
+			return nil, ErrUnsortedCoinbaseTxs
+		}
+	}
+	return txs, nil
+}
+
+// EncodeLegacyCoinbaseTxs encodes the given rewards the way header.CoinbaseTxs
+// were before the NUC coinbase txs fork: the address and the poc, pow and pool
+// rewards of every entry are concatenated, the post rewards aren't recorded. The
+// rewards are truncated to their 8 leading bytes, zero padded on the left.
+func EncodeLegacyCoinbaseTxs(txs []CoinbaseTx) []byte {
+	blob := make([]byte, 0, len(txs)*legacyCoinbaseTxSize)
+	for _, tx := range txs {
+		blob = append(blob, tx.Address.Bytes()...)
+		for _, reward := range []*big.Int{tx.PocReward, tx.PowReward, tx.PoolReward} {
+			var b []byte
+			if reward != nil {
+				b = reward.Bytes()
+			}
+			if len(b) < 8 {
+				b = append(make([]byte, 8-len(b)), b...)
+			}
+			blob = append(blob, b[:8]...)
+		}
+	}
+	return blob
+}
+
+// DecodeLegacyCoinbaseTxs parses a header.CoinbaseTxs blob of a block before the
+// NUC coinbase txs fork. The post rewards weren't recorded and decode as zero.
+func DecodeLegacyCoinbaseTxs(b []byte) ([]CoinbaseTx, error) {
+	if len(b)%legacyCoinbaseTxSize != 0 {
+		return nil, ErrLegacyCoinbaseTxsSize
+	}
+	var txs []CoinbaseTx
+	for ; len(b) > 0; b = b[legacyCoinbaseTxSize:] {
+		txs = append(txs, CoinbaseTx{
+			Address:    common.BytesToAddress(b[:common.AddressLength]),
+			PocReward:  new(big.Int).SetBytes(b[common.AddressLength : common.AddressLength+8]),
+			PowReward:  new(big.Int).SetBytes(b[common.AddressLength+8 : common.AddressLength+16]),
+			PoolReward: new(big.Int).SetBytes(b[common.AddressLength+16 : legacyCoinbaseTxSize]),
+			PostReward: new(big.Int),
+		})
+	}
+	return txs, nil
+}
+
+// EncodeBlockCoinbaseTxs encodes the given rewards with the encoding in force at
+// the given block.
+func EncodeBlockCoinbaseTxs(config *params.ChainConfig, number *big.Int, txs []CoinbaseTx) ([]byte, error) {
+	if !config.IsNUCCoinbaseTxs(number) {
+		return EncodeLegacyCoinbaseTxs(txs), nil
+	}
+	return EncodeCoinbaseTxs(txs)
+}
+
+// DecodeBlockCoinbaseTxs parses the header.CoinbaseTxs of the given block, with
+// the encoding in force at it.
+func DecodeBlockCoinbaseTxs(config *params.ChainConfig, number *big.Int, b []byte) ([]CoinbaseTx, error) {
+	if !config.IsNUCCoinbaseTxs(number) {
+		return DecodeLegacyCoinbaseTxs(b)
+	}
+	return DecodeCoinbaseTxs(b)
+}
@@ -0,0 +1,28 @@
+// Copyright 2019 The nuc Team
+
+// +build gofuzz
+
+package types
+
+import "bytes"
+
+// Fuzz implements a go-fuzz fuzzer method to test that the coinbase txs
+// decoder only accepts canonical encodings: every blob that decodes must
+// re-encode into exactly the same bytes.
+func Fuzz(data []byte) int {
+	if len(data) == 0 {
+		return -1
+	}
+	txs, err := DecodeCoinbaseTxs(data)
+	if err != nil {
+		return 0
+	}
+	enc, err := EncodeCoinbaseTxs(txs)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(enc, data) {
+		panic("content mismatch")
+	}
+	return 1
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// randomCoinbaseTxs generates rewards sorted by address, with amounts wider than
+// 64 bits so truncation in the encoding would be detected.
+func randomCoinbaseTxs(r *rand.Rand, n int) []CoinbaseTx {
+	txs := make([]CoinbaseTx, n)
+	for i := range txs {
+		r.Read(txs[i].Address[:])
+		txs[i].PocReward = new(big.Int).Rand(r, new(big.Int).Lsh(common.Big1, 100))
+		txs[i].PowReward = new(big.Int).Rand(r, new(big.Int).Lsh(common.Big1, 80))
+		txs[i].PoolReward = new(big.Int).Rand(r, new(big.Int).Lsh(common.Big1, 72))
+		txs[i].PostReward = new(big.Int).Rand(r, new(big.Int).Lsh(common.Big1, 64))
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return bytes.Compare(txs[i].Address[:], txs[j].Address[:]) < 0
+	})
+	return txs
+}
+
+func TestCoinbaseTxsRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 17, 256} {
+		txs := randomCoinbaseTxs(r, n)
+		enc, err := EncodeCoinbaseTxs(txs)
+		if err != nil {
+			t.Fatalf("%d entries: encode failed: %v", n, err)
+		}
+		if enc[0] != CoinbaseTxsVersion {
+			t.Fatalf("%d entries: version mismatch: have %d, want %d", n, enc[0], CoinbaseTxsVersion)
+		}
+		dec, err := DecodeCoinbaseTxs(enc)
+		if err != nil {
+			t.Fatalf("%d entries: decode failed: %v", n, err)
+		}
+		if len(dec) != len(txs) {
+			t.Fatalf("%d entries: entry count mismatch: have %d", n, len(dec))
+		}
+		for i, want := range txs {
+			have := dec[i]
+			if have.Address != want.Address || have.PocReward.Cmp(want.PocReward) != 0 || have.PowReward.Cmp(want.PowReward) != 0 ||
+				have.PoolReward.Cmp(want.PoolReward) != 0 || have.PostReward.Cmp(want.PostReward) != 0 {
+				t.Fatalf("%d entries: reward %d mismatch: have %+v, want %+v", n, i, have, want)
+			}
+		}
+		reenc, err := EncodeCoinbaseTxs(dec)
+		if err != nil {
+			t.Fatalf("%d entries: re-encode failed: %v", n, err)
+		}
+		if !bytes.Equal(enc, reenc) {
+			t.Fatalf("%d entries: re-encoding mismatch", n)
+		}
+	}
+}
+
+// Tests that missing amounts are encoded as zero and decode as non-nil zeroes.
+func TestCoinbaseTxsNilAmounts(t *testing.T) {
+	txs := []CoinbaseTx{{Address: common.Address{1}}}
+
+	enc, err := EncodeCoinbaseTxs(txs)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	dec, err := DecodeCoinbaseTxs(enc)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	legacy, err := DecodeLegacyCoinbaseTxs(EncodeLegacyCoinbaseTxs(txs))
+	if err != nil {
+		t.Fatalf("legacy decode failed: %v", err)
+	}
+	for _, have := range []CoinbaseTx{dec[0], legacy[0]} {
+		for _, amount := range []*big.Int{have.PocReward, have.PowReward, have.PoolReward, have.PostReward} {
+			if amount == nil || amount.Sign() != 0 {
+				t.Fatalf("amount mismatch: have %v, want 0", amount)
+			}
+		}
+	}
+}
+
+func TestDecodeCoinbaseTxsInvalid(t *testing.T) {
+	a, b := common.Address{1}, common.Address{2}
+	entries := []CoinbaseTx{
+		{Address: a, PocReward: new(big.Int), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)},
+		{Address: b, PocReward: new(big.Int), PowReward: new(big.Int), PoolReward: new(big.Int), PostReward: new(big.Int)},
+	}
+	enc, err := EncodeCoinbaseTxs(entries)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	// Swap the two entries to produce an unsorted list.
+	unsorted, err := EncodeCoinbaseTxs([]CoinbaseTx{entries[1], entries[0]})
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	duplicate, err := EncodeCoinbaseTxs([]CoinbaseTx{entries[0], entries[0]})
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	tests := []struct {
+		name string
+		blob []byte
+	}{
+		{"unknown version", append([]byte{CoinbaseTxsVersion + 1}, enc[1:]...)},
+		{"truncated", enc[:len(enc)-1]},
+		{"trailing data", append(append([]byte{}, enc...), 0x80)},
+		{"unsorted", unsorted},
+		{"duplicate", duplicate},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeCoinbaseTxs(tt.blob); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+// Tests that the rewards of a block are encoded the legacy way before the NUC
+// coinbase txs fork and the canonical way from it on.
+func TestBlockCoinbaseTxs(t *testing.T) {
+	config := &params.ChainConfig{NUC: &params.NUCConfig{CoinbaseTxsBlock: big.NewInt(10)}}
+	txs := randomCoinbaseTxs(rand.New(rand.NewSource(2)), 3)
+
+	for _, number := range []int64{9, 10} {
+		enc, err := EncodeBlockCoinbaseTxs(config, big.NewInt(number), txs)
+		if err != nil {
+			t.Fatalf("block %d: encode failed: %v", number, err)
+		}
+		if legacy := len(enc) == 3*legacyCoinbaseTxSize; legacy != (number < 10) {
+			t.Fatalf("block %d: encoding mismatch: have %x", number, enc)
+		}
+		dec, err := DecodeBlockCoinbaseTxs(config, big.NewInt(number), enc)
+		if err != nil {
+			t.Fatalf("block %d: decode failed: %v", number, err)
+		}
+		if len(dec) != len(txs) {
+			t.Fatalf("block %d: entry count mismatch: have %d, want %d", number, len(dec), len(txs))
+		}
+	}
+	if _, err := DecodeLegacyCoinbaseTxs(make([]byte, legacyCoinbaseTxSize+1)); err != ErrLegacyCoinbaseTxsSize {
+		t.Errorf("malformed legacy encoding error mismatch: have %v, want %v", err, ErrLegacyCoinbaseTxsSize)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ParticipantRole is the role an account is registered for in the NUC rule
+// contract.
+type ParticipantRole uint8
+
+const (
+	PocParticipant  ParticipantRole = iota // Mortgaged PoC miner
+	PowParticipant                         // PoW miner having bought a ticket
+	PoolParticipant                        // Mining pool having bought a ticket
+)
+
+// String implements fmt.Stringer.
+func (r ParticipantRole) String() string {
+	switch r {
+	case PocParticipant:
+		return "poc"
+	case PowParticipant:
+		return "pow"
+	case PoolParticipant:
+		return "pool"
+	default:
+		return fmt.Sprintf("role(%d)", uint8(r))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r ParticipantRole) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Participant is an account registered in the NUC rule contract.
+type Participant struct {
+	Role    ParticipantRole
+	Address common.Address
+	Pool    common.Address // Pool the participant is bound to, zero if none
+	Amount  *big.Int       // Mortgaged (PoC) or ticket (PoW, pool) balance, never nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The nuc Team
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BlockIssuance is the amount of coins created by the rewards of a block, on top
+// of the balances moved around by its transactions.
+type BlockIssuance struct {
+	Minted *big.Int // Block and uncle rewards created by the issuance schedule
+	Fees   *big.Int // Fees minted for the rewarded participants (includer rewards only under a fee policy)
+	Team   *big.Int // Share of the legacy fees minted for the team
+}
+
+// Total returns the sum of all the coins created.
+func (i *BlockIssuance) Total() *big.Int {
+	total := new(big.Int)
+	for _, amount := range []*big.Int{i.Minted, i.Fees, i.Team} {
+		if amount != nil {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
+// BlockRewards is the breakdown of the rewards a block credits when finalized,
+// uncle rewards aside.
+type BlockRewards struct {
+	Participants []*ParticipantReward // Rewarded participants, sorted by address
+	Team         *big.Int             // Fees credited to the team treasury
+	Treasury     common.Address       // Team treasury
+	Leftover     *big.Int             // Block reward nobody earned
+	Coinbase     common.Address       // Default coinbase receiving the leftover
+}
+
+// ParticipantReward is the reward credited to a single participant of a block.
+type ParticipantReward struct {
+	Address common.Address
+	Poc     *big.Int
+	Pow     *big.Int
+	Pool    *big.Int
+	Post    *big.Int
+	Fee     *big.Int // Share of the transaction fees and includer rewards
+}
+
+// Total returns the sum of all the reward categories, fee share included.
+func (r *ParticipantReward) Total() *big.Int {
+	total := new(big.Int)
+	for _, reward := range []*big.Int{r.Poc, r.Pow, r.Pool, r.Post, r.Fee} {
+		if reward != nil {
+			total.Add(total, reward)
+		}
+	}
+	return total
+}
@@ -27,4 +27,9 @@ var (
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrContractAddressCollision = errors.New("contract address collision")
 	ErrNoCompatibleInterpreter  = errors.New("no compatible interpreter")
+
+	// ErrExecutionReverted and ErrWriteProtection are returned by the EVM when
+	// the code reverts and when a static call attempts to modify the state.
+	ErrExecutionReverted = errExecutionReverted
+	ErrWriteProtection   = errWriteProtection
 )
@@ -481,11 +481,11 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
-// Create IPFS file input data as deployment code
+// Create3 creates an IPFS data contract, storing the input data as the code of
+// the contract without running it. The data keeps its 0x0001 prefix, so calling
+// the contract stops right away. Storing the data is charged per byte like the
+// code deposit of regular contracts, from the NUC data contract gas fork on.
 func (evm *EVM) Create3(caller ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
-
-	codeAndHash := &codeAndHash{code: code}
-	//contractAddr
 	address := crypto.CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
 
 	// Depth check execution. Fail if we're trying to execute above the
@@ -504,48 +504,73 @@ func (evm *EVM) Create3(caller ContractRef, code []byte, gas uint64, value *big.
 	if evm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
 		return nil, common.Address{}, 0, ErrContractAddressCollision
 	}
+	if !evm.chainConfig.IsNUCDataContractGas(evm.BlockNumber) {
+		return evm.create3Legacy(caller, address, code, gas, value)
+	}
 	// Create a new account on the state
-	//snapshot := evm.StateDB.Snapshot()
+	snapshot := evm.StateDB.Snapshot()
 	evm.StateDB.CreateAccount(address)
-	if evm.ChainConfig().IsEIP158(evm.BlockNumber) {
+	if evm.chainRules.IsEIP158 {
 		evm.StateDB.SetNonce(address, 1)
 	}
 	evm.Transfer(evm.StateDB, caller.Address(), address, value)
 
-	// initialise a new contract and set the code that is to be used by the
-	// EVM. The contract is a scoped environment for this execution context
-	// only.
-
-	//#######contract := NewContract(caller, AccountRef(address), value, gas)
-
-	//#######contract.SetCodeOptionalHash(&address, codeAndHash)
+	// The contract only tracks the gas, there's no code to run
+	contract := NewContract(caller, AccountRef(address), value, gas)
 
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, address, gas, nil
 	}
-
 	if evm.vmConfig.Debug && evm.depth == 0 {
-		_ = evm.vmConfig.Tracer.CaptureStart(caller.Address(), address, true, codeAndHash.code, gas, value)
+		evm.vmConfig.Tracer.CaptureStart(caller.Address(), address, true, code, gas, value)
 	}
-	//start := time.Now()
-	////ret, err := run(evm, contract, nil, false)
-	ret = codeAndHash.code
-	err = nil
+	start := time.Now()
+
 	// check whether the max code size has been exceeded
-	maxCodeSizeExceeded := evm.ChainConfig().IsEIP158(evm.BlockNumber) && len(ret) > params.MaxCodeSize
-	// if the contract creation ran successfully and no errors were returned
-	// calculate the gas required to store the code. If the code could not
-	// be stored due to not enough gas set an error and let it be handled
-	// by the error checking condition below.
-	// Assign err if contract code size exceeds the max while the err is still empty.
-	if maxCodeSizeExceeded {
+	ret = code
+	maxCodeSizeExceeded := evm.chainRules.IsEIP158 && len(ret) > params.MaxCodeSize
+	if !maxCodeSizeExceeded {
+		createDataGas := uint64(len(ret)) * params.CreateDataGas
+		if contract.UseGas(createDataGas) {
+			evm.StateDB.SetCode(address, ret)
+		} else {
+			err = ErrCodeStoreOutOfGas
+		}
+	}
+	// Failing to store the data reverts the creation and consumes all the gas,
+	// the same way it does for regular contracts
+	if maxCodeSizeExceeded || (err != nil && (evm.chainRules.IsHomestead || err != ErrCodeStoreOutOfGas)) {
+		evm.StateDB.RevertToSnapshot(snapshot)
+		contract.UseGas(contract.Gas)
+	}
+	if maxCodeSizeExceeded && err == nil {
 		err = errMaxCodeSizeExceeded
 	}
 	if evm.vmConfig.Debug && evm.depth == 0 {
-		//evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+		evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 	}
+	return ret, address, contract.Gas, err
+}
 
-	//#######return ret, address, contract.Gas, err
-	return ret, address, 100, err
+// create3Legacy creates an IPFS data contract the way it was done before the NUC
+// data contract gas fork: the account is created but the data isn't stored, and
+// 100 gas is left over whatever the gas given.
+func (evm *EVM) create3Legacy(caller ContractRef, address common.Address, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	evm.StateDB.CreateAccount(address)
+	if evm.chainRules.IsEIP158 {
+		evm.StateDB.SetNonce(address, 1)
+	}
+	evm.Transfer(evm.StateDB, caller.Address(), address, value)
 
+	if evm.vmConfig.NoRecursion && evm.depth > 0 {
+		return nil, address, gas, nil
+	}
+	if evm.vmConfig.Debug && evm.depth == 0 {
+		evm.vmConfig.Tracer.CaptureStart(caller.Address(), address, true, code, gas, value)
+	}
+	ret = code
+	if evm.chainRules.IsEIP158 && len(ret) > params.MaxCodeSize {
+		err = errMaxCodeSizeExceeded
+	}
+	return ret, address, 100, err
 }
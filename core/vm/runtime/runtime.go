@@ -146,6 +146,24 @@ func Create(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
 	return code, address, leftOverGas, err
 }
 
+// CreateIPFS stores the input as an IPFS data contract using the EVM create3
+// method.
+func CreateIPFS(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	setDefaults(cfg)
+
+	if cfg.State == nil {
+		cfg.State, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	}
+	var (
+		vmenv  = NewEnv(cfg)
+		sender = vm.AccountRef(cfg.Origin)
+	)
+	return vmenv.Create3(sender, input, cfg.GasLimit, cfg.Value)
+}
+
 // Call executes the code given by the contract's address. It will return the
 // EVM's return value or an error if it failed.
 //
@@ -17,9 +17,11 @@
 package runtime
 
 import (
+	"bytes"
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -117,6 +119,171 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// ipfsTracer records the gas an IPFS data contract creation reports to tracers.
+type ipfsTracer struct {
+	started bool
+	gas     uint64
+	gasUsed uint64
+	err     error
+}
+
+func (t *ipfsTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.started, t.gas = create, gas
+	return nil
+}
+
+func (t *ipfsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *ipfsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *ipfsTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.gasUsed, t.err = gasUsed, err
+	return nil
+}
+
+// ipfsChainConfig returns a chain config with all the forks active, the NUC data
+// contract gas one included from the given block.
+func ipfsChainConfig(fork *big.Int) *params.ChainConfig {
+	return &params.ChainConfig{
+		ChainID:        big.NewInt(1),
+		HomesteadBlock: new(big.Int),
+		EIP150Block:    new(big.Int),
+		EIP155Block:    new(big.Int),
+		EIP158Block:    new(big.Int),
+		NUC:            &params.NUCConfig{DataContractGasBlock: fork},
+	}
+}
+
+func TestCreateIPFS(t *testing.T) {
+	var (
+		data   = append([]byte{0x0, 0x1}, []byte("QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")...)
+		stored = uint64(len(data)) * params.CreateDataGas
+		origin = common.HexToAddress("0x0a")
+	)
+	tests := []struct {
+		gas      uint64
+		leftOver uint64
+		err      error
+	}{
+		{stored + 1000, 1000, nil},
+		{stored, 0, nil},
+		{stored - 1, 0, vm.ErrCodeStoreOutOfGas},
+	}
+	for i, tt := range tests {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.SetBalance(origin, big.NewInt(100))
+
+		tracer := new(ipfsTracer)
+		cfg := &Config{
+			ChainConfig: ipfsChainConfig(big.NewInt(10)),
+			BlockNumber: big.NewInt(10),
+			State:       statedb,
+			Origin:      origin,
+			GasLimit:    tt.gas,
+			Value:       big.NewInt(10),
+			EVMConfig:   vm.Config{Debug: true, Tracer: tracer},
+		}
+		_, address, leftOver, err := CreateIPFS(data, cfg)
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if leftOver != tt.leftOver {
+			t.Errorf("test %d: leftover gas mismatch: have %d, want %d", i, leftOver, tt.leftOver)
+		}
+		if !tracer.started || tracer.gas != tt.gas || tracer.gasUsed != tt.gas-leftOver || tracer.err != err {
+			t.Errorf("test %d: trace mismatch: have %+v", i, tracer)
+		}
+		// Failed creations are reverted, except for the sender's nonce
+		if nonce := statedb.GetNonce(origin); nonce != 1 {
+			t.Errorf("test %d: sender nonce mismatch: have %d, want %d", i, nonce, 1)
+		}
+		if tt.err != nil {
+			if statedb.Exist(address) {
+				t.Errorf("test %d: failed creation left the contract", i)
+			}
+			if balance := statedb.GetBalance(origin); balance.Cmp(big.NewInt(100)) != 0 {
+				t.Errorf("test %d: sender balance mismatch: have %v, want %v", i, balance, 100)
+			}
+			continue
+		}
+		if code := statedb.GetCode(address); !bytes.Equal(code, data) {
+			t.Errorf("test %d: stored data mismatch: have %x, want %x", i, code, data)
+		}
+		if balance := statedb.GetBalance(address); balance.Cmp(big.NewInt(10)) != 0 {
+			t.Errorf("test %d: contract balance mismatch: have %v, want %v", i, balance, 10)
+		}
+	}
+}
+
+// Tests that IPFS data contract creations before the NUC data contract gas fork
+// don't store the data and leave 100 gas over, whatever the gas given.
+func TestCreateIPFSLegacy(t *testing.T) {
+	var (
+		data   = append([]byte{0x0, 0x1}, []byte("QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")...)
+		origin = common.HexToAddress("0x0a")
+	)
+	for i, gas := range []uint64{1000000, uint64(len(data)) * params.CreateDataGas, 1} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.SetBalance(origin, big.NewInt(100))
+
+		tracer := new(ipfsTracer)
+		cfg := &Config{
+			ChainConfig: ipfsChainConfig(big.NewInt(10)),
+			BlockNumber: big.NewInt(9),
+			State:       statedb,
+			Origin:      origin,
+			GasLimit:    gas,
+			Value:       big.NewInt(10),
+			EVMConfig:   vm.Config{Debug: true, Tracer: tracer},
+		}
+		ret, address, leftOver, err := CreateIPFS(data, cfg)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if leftOver != 100 {
+			t.Errorf("test %d: leftover gas mismatch: have %d, want %d", i, leftOver, 100)
+		}
+		if !bytes.Equal(ret, data) {
+			t.Errorf("test %d: returned data mismatch: have %x, want %x", i, ret, data)
+		}
+		if !tracer.started || tracer.gas != gas {
+			t.Errorf("test %d: trace mismatch: have %+v", i, tracer)
+		}
+		if code := statedb.GetCode(address); len(code) != 0 {
+			t.Errorf("test %d: data stored before the fork: %x", i, code)
+		}
+		if nonce := statedb.GetNonce(address); nonce != 1 {
+			t.Errorf("test %d: contract nonce mismatch: have %d, want %d", i, nonce, 1)
+		}
+		if balance := statedb.GetBalance(address); balance.Cmp(big.NewInt(10)) != 0 {
+			t.Errorf("test %d: contract balance mismatch: have %v, want %v", i, balance, 10)
+		}
+	}
+}
+
+// Tests that calling an IPFS data contract stops right away without running the
+// stored data.
+func TestCallIPFS(t *testing.T) {
+	data := append([]byte{0x0, 0x1}, byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE))
+
+	cfg := &Config{ChainConfig: ipfsChainConfig(new(big.Int)), GasLimit: 1000000}
+	_, address, _, err := CreateIPFS(data, cfg)
+	if err != nil {
+		t.Fatalf("failed to create data contract: %v", err)
+	}
+	if code := cfg.State.GetCode(address); !bytes.Equal(code, data) {
+		t.Fatalf("stored data mismatch: have %x, want %x", code, data)
+	}
+	ret, leftOver, err := Call(address, nil, cfg)
+	if err != nil || len(ret) != 0 || leftOver != cfg.GasLimit {
+		t.Fatalf("data contract ran: ret %x, leftover %d, err %v", ret, leftOver, err)
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
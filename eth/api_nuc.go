@@ -0,0 +1,123 @@
+// Copyright 2019 The nuc Team
+
+package eth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// pendingRewardsChanSize is the size of channel listening to PendingRewardsEvent.
+const pendingRewardsChanSize = 10
+
+// PublicNUCAPI provides the NUC specific RPC methods of full nodes.
+type PublicNUCAPI struct {
+	e *Ethereum
+}
+
+// NewPublicNUCAPI creates a new NUC API for full nodes.
+func NewPublicNUCAPI(e *Ethereum) *PublicNUCAPI {
+	return &PublicNUCAPI{e}
+}
+
+// RPCParticipantReward is the projected reward of a participant of the block
+// being sealed.
+type RPCParticipantReward struct {
+	Address common.Address `json:"address"`
+	Poc     *hexutil.Big   `json:"poc"`
+	Pow     *hexutil.Big   `json:"pow"`
+	Pool    *hexutil.Big   `json:"pool"`
+	Post    *hexutil.Big   `json:"post"`
+	Fee     *hexutil.Big   `json:"fee"`
+	Total   *hexutil.Big   `json:"total"`
+}
+
+// RPCPendingRewards is the breakdown of the rewards the block being sealed would
+// credit, uncle rewards aside.
+type RPCPendingRewards struct {
+	Number          hexutil.Uint64          `json:"number"`
+	ParentHash      common.Hash             `json:"parentHash"`
+	SealHash        common.Hash             `json:"sealHash"`
+	Participants    []*RPCParticipantReward `json:"participants"`
+	TeamFee         *hexutil.Big            `json:"teamFee"`
+	Treasury        common.Address          `json:"treasury"`
+	Leftover        *hexutil.Big            `json:"leftover"`
+	LeftoverAddress common.Address          `json:"leftoverAddress"`
+}
+
+// PendingRewards returns the rewards of the last block committed for sealing,
+// or nil if the consensus engine can't break them down.
+func (api *PublicNUCAPI) PendingRewards() *RPCPendingRewards {
+	block, rewards := api.e.Miner().PendingRewards()
+	if block == nil {
+		return nil
+	}
+	return api.e.rpcPendingRewards(block, rewards)
+}
+
+// PendingRewards creates a subscription that is triggered each time the miner
+// commits a new block for sealing, with the rewards the block would credit.
+func (api *PrivateMinerAPI) PendingRewards(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.PendingRewardsEvent, pendingRewardsChanSize)
+		sub := api.e.Miner().SubscribePendingRewardsEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, api.e.rpcPendingRewards(ev.Block, ev.Rewards))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// rpcPendingRewards converts the rewards of a block committed for sealing into
+// their RPC representation.
+func (s *Ethereum) rpcPendingRewards(block *types.Block, rewards *types.BlockRewards) *RPCPendingRewards {
+	hexBig := func(v *big.Int) *hexutil.Big {
+		if v == nil {
+			v = new(big.Int)
+		}
+		return (*hexutil.Big)(v)
+	}
+	result := &RPCPendingRewards{
+		Number:          hexutil.Uint64(block.NumberU64()),
+		ParentHash:      block.ParentHash(),
+		SealHash:        s.engine.SealHash(block.Header()),
+		Participants:    make([]*RPCParticipantReward, 0, len(rewards.Participants)),
+		TeamFee:         hexBig(rewards.Team),
+		Treasury:        rewards.Treasury,
+		Leftover:        hexBig(rewards.Leftover),
+		LeftoverAddress: rewards.Coinbase,
+	}
+	for _, reward := range rewards.Participants {
+		result.Participants = append(result.Participants, &RPCParticipantReward{
+			Address: reward.Address,
+			Poc:     hexBig(reward.Poc),
+			Pow:     hexBig(reward.Pow),
+			Pool:    hexBig(reward.Pool),
+			Post:    hexBig(reward.Post),
+			Fee:     hexBig(reward.Fee),
+			Total:   hexBig(reward.Total()),
+		})
+	}
+	return result
+}
@@ -84,6 +84,10 @@ type Ethereum struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
+	rewardLedger  *core.RewardLedger             // Mining reward index following the chain head
+	supplyLedger  *core.SupplyLedger             // Coin supply accounting of the chain's blocks, nil if unsupported
+	dataIndex     *core.DataContractIndex        // IPFS data contract index following the chain head
+	participants  *core.ParticipantTracker       // Rule contract participant changes, nil if unsupported
 
 	APIBackend *EthAPIBackend
 
@@ -141,6 +145,11 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	// Remote miners are notified by the consensus engine, which needs to know the format
+	config.Ethash.NotifyNUC = config.Miner.NotifyNUC
+	config.Ethash.StratumAddr = config.Miner.Stratum
+	config.Ethash.StratumDifficulty = config.Miner.StratumDifficulty
+
 	eth := &Ethereum{
 		config:         config,
 		chainDb:        chainDb,
@@ -195,6 +204,10 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	eth.rewardLedger = core.NewRewardLedger(chainDb, eth.blockchain)
+	eth.supplyLedger = core.NewSupplyLedger(chainDb, eth.blockchain)
+	eth.dataIndex = core.NewDataContractIndex(chainDb, eth.blockchain)
+	eth.participants = core.NewParticipantTracker(eth.blockchain)
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
@@ -259,12 +272,15 @@ func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainCo
 		return ethash.NewShared()
 	default:
 		engine := ethash.New(ethash.Config{
-			CacheDir:       ctx.ResolvePath(config.CacheDir),
-			CachesInMem:    config.CachesInMem,
-			CachesOnDisk:   config.CachesOnDisk,
-			DatasetDir:     config.DatasetDir,
-			DatasetsInMem:  config.DatasetsInMem,
-			DatasetsOnDisk: config.DatasetsOnDisk,
+			CacheDir:          ctx.ResolvePath(config.CacheDir),
+			CachesInMem:       config.CachesInMem,
+			CachesOnDisk:      config.CachesOnDisk,
+			DatasetDir:        config.DatasetDir,
+			DatasetsInMem:     config.DatasetsInMem,
+			DatasetsOnDisk:    config.DatasetsOnDisk,
+			NotifyNUC:         config.NotifyNUC,
+			StratumAddr:       config.StratumAddr,
+			StratumDifficulty: config.StratumDifficulty,
 		}, notify, noverify)
 		engine.SetThreads(-1) // Disable CPU mining
 		return engine
@@ -310,6 +326,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateMinerAPI(s),
 			Public:    false,
+		}, {
+			Namespace: "nuc",
+			Version:   "1.0",
+			Service:   NewPublicNUCAPI(s),
+			Public:    true,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -548,6 +569,14 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	s.rewardLedger.Stop()
+	if s.supplyLedger != nil {
+		s.supplyLedger.Stop()
+	}
+	s.dataIndex.Stop()
+	if s.participants != nil {
+		s.participants.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 	s.protocolManager.Stop()
@@ -0,0 +1,141 @@
+// Copyright 2019 The nuc Team
+
+package filters
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ParticipantCriteria selects the rule contract participant changes of interest.
+type ParticipantCriteria struct {
+	// Addresses of the participants, or of the pools they bind to or unbind
+	// from. All changes are selected if empty.
+	Addresses []common.Address `json:"addresses"`
+}
+
+// RPCParticipantChange is a change of the rule contract participants, as returned
+// by the participant subscriptions and filters.
+type RPCParticipantChange struct {
+	Kind        core.ParticipantChange `json:"kind"`
+	Role        types.ParticipantRole  `json:"role"`
+	Address     common.Address         `json:"address"`
+	Pool        common.Address         `json:"pool"`
+	Amount      *hexutil.Big           `json:"amount"`
+	BlockNumber hexutil.Uint64         `json:"blockNumber"`
+	BlockHash   common.Hash            `json:"blockHash"`
+}
+
+// newRPCParticipantChange converts a participant change event for RPC.
+func newRPCParticipantChange(ev core.ParticipantChangeEvent) *RPCParticipantChange {
+	return &RPCParticipantChange{
+		Kind:        ev.Kind,
+		Role:        ev.Role,
+		Address:     ev.Address,
+		Pool:        ev.Pool,
+		Amount:      (*hexutil.Big)(ev.Amount),
+		BlockNumber: hexutil.Uint64(ev.Block),
+		BlockHash:   ev.BlockHash,
+	}
+}
+
+// NucParticipants creates a subscription that fires for every change of the rule
+// contract participants matching the given criteria, as canonical blocks are
+// imported. Changes undone by a reorg are sent as the opposite change.
+func (api *PublicFilterAPI) NucParticipants(ctx context.Context, crit *ParticipantCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if crit == nil {
+		crit = new(ParticipantCriteria)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		changes := make(chan core.ParticipantChangeEvent, 128)
+		changesSub := api.events.SubscribeParticipants(crit.Addresses, changes)
+
+		for {
+			select {
+			case ev := <-changes:
+				notifier.Notify(rpcSub.ID, newRPCParticipantChange(ev))
+			case <-rpcSub.Err():
+				changesSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				changesSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewParticipantFilter creates a filter that fetches the changes of the rule
+// contract participants matching the given criteria. The changes can be polled
+// with eth_getFilterChanges.
+func (api *PublicFilterAPI) NewParticipantFilter(crit *ParticipantCriteria) rpc.ID {
+	if crit == nil {
+		crit = new(ParticipantCriteria)
+	}
+	var (
+		changes    = make(chan core.ParticipantChangeEvent)
+		changesSub = api.events.SubscribeParticipants(crit.Addresses, changes)
+	)
+
+	api.filtersMu.Lock()
+	api.filters[changesSub.ID] = &filter{typ: ParticipantsSubscription, deadline: time.NewTimer(deadline), s: changesSub}
+	api.filtersMu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case ev := <-changes:
+				api.filtersMu.Lock()
+				if f, found := api.filters[changesSub.ID]; found {
+					f.changes = append(f.changes, ev)
+				}
+				api.filtersMu.Unlock()
+			case <-changesSub.Err():
+				api.filtersMu.Lock()
+				delete(api.filters, changesSub.ID)
+				api.filtersMu.Unlock()
+				return
+			}
+		}
+	}()
+
+	return changesSub.ID
+}
+
+// returnParticipantChanges converts the participant changes of a filter for RPC,
+// returning an empty array if there are none.
+func returnParticipantChanges(changes []core.ParticipantChangeEvent) []*RPCParticipantChange {
+	result := make([]*RPCParticipantChange, len(changes))
+	for i, ev := range changes {
+		result[i] = newRPCParticipantChange(ev)
+	}
+	return result
+}
+
+// matchParticipantChange reports whether a participant change concerns any of
+// the given participants or pools, or whether no address is given at all.
+func matchParticipantChange(ev core.ParticipantChangeEvent, addresses []common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	for _, addr := range addresses {
+		if addr == ev.Address || (ev.Pool != (common.Address{}) && addr == ev.Pool) {
+			return true
+		}
+	}
+	return false
+}
@@ -53,6 +53,8 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// ParticipantsSubscription queries the changes of the rule contract participants
+	ParticipantsSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -68,6 +70,8 @@ const (
 	logsChanSize = 10
 	// chainEvChanSize is the size of channel listening to ChainEvent.
 	chainEvChanSize = 10
+	// participantsChanSize is the size of channel listening to ParticipantChangeEvent.
+	participantsChanSize = 64
 )
 
 var (
@@ -84,6 +88,9 @@ type subscription struct {
 	headers   chan *types.Header
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
+
+	participantsCrit []common.Address // participants or pools of interest, all if empty
+	participants     chan core.ParticipantChangeEvent
 }
 
 // EventSystem creates subscriptions, processes events and broadcasts them to the
@@ -101,6 +108,8 @@ type EventSystem struct {
 	chainSub      event.Subscription         // Subscription for new chain event
 	pendingLogSub *event.TypeMuxSubscription // Subscription for pending log event
 
+	participantsSub event.Subscription // Subscription for participant change event
+
 	// Channels
 	install   chan *subscription         // install filter for event notification
 	uninstall chan *subscription         // remove filter for event notification
@@ -108,6 +117,8 @@ type EventSystem struct {
 	logsCh    chan []*types.Log          // Channel to receive new log event
 	rmLogsCh  chan core.RemovedLogsEvent // Channel to receive removed log event
 	chainCh   chan core.ChainEvent       // Channel to receive new chain event
+
+	participantsCh chan core.ParticipantChangeEvent // Channel to receive participant change event
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -127,6 +138,8 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 		logsCh:    make(chan []*types.Log, logsChanSize),
 		rmLogsCh:  make(chan core.RemovedLogsEvent, rmLogsChanSize),
 		chainCh:   make(chan core.ChainEvent, chainEvChanSize),
+
+		participantsCh: make(chan core.ParticipantChangeEvent, participantsChanSize),
 	}
 
 	// Subscribe events
@@ -134,12 +147,13 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
 	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
 	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
+	m.participantsSub = m.backend.SubscribeParticipantChangeEvent(m.participantsCh)
 	// TODO(rjl493456442): use feed to subscribe pending log event
 	m.pendingLogSub = m.mux.Subscribe(core.PendingLogsEvent{})
 
 	// Make sure none of the subscriptions are empty
 	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil ||
-		m.pendingLogSub.Closed() {
+		m.participantsSub == nil || m.pendingLogSub.Closed() {
 		log.Crit("Subscribe for event system failed")
 	}
 
@@ -175,6 +189,7 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
+			case <-sub.f.participants:
 			}
 		}
 
@@ -314,6 +329,27 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 	return es.subscribe(sub)
 }
 
+// SubscribeParticipants creates a subscription that writes the changes of the
+// rule contract participants. Only the changes of the given participants, or of
+// participants bound to or unbound from the given pools, are written, unless no
+// address is given.
+func (es *EventSystem) SubscribeParticipants(addresses []common.Address, participants chan core.ParticipantChangeEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       ParticipantsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+
+		participantsCrit: addresses,
+		participants:     participants,
+	}
+	return es.subscribe(sub)
+}
+
 type filterIndex map[Type]map[rpc.ID]*subscription
 
 // broadcast event to filters that match criteria.
@@ -355,6 +391,12 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		for _, f := range filters[PendingTransactionsSubscription] {
 			f.hashes <- hashes
 		}
+	case core.ParticipantChangeEvent:
+		for _, f := range filters[ParticipantsSubscription] {
+			if matchParticipantChange(e, f.participantsCrit) {
+				f.participants <- e
+			}
+		}
 	case core.ChainEvent:
 		for _, f := range filters[BlocksSubscription] {
 			f.headers <- e.Block.Header()
@@ -453,6 +495,7 @@ func (es *EventSystem) eventLoop() {
 		es.logsSub.Unsubscribe()
 		es.rmLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
+		es.participantsSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -471,6 +514,8 @@ func (es *EventSystem) eventLoop() {
 			es.broadcast(index, ev)
 		case ev := <-es.chainCh:
 			es.broadcast(index, ev)
+		case ev := <-es.participantsCh:
+			es.broadcast(index, ev)
 		case ev, active := <-es.pendingLogSub.Chan():
 			if !active { // system stopped
 				return
@@ -506,6 +551,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-es.chainSub.Err():
 			return
+		case <-es.participantsSub.Err():
+			return
 		}
 	}
 }
@@ -46,6 +46,8 @@ type testBackend struct {
 	rmLogsFeed *event.Feed
 	logsFeed   *event.Feed
 	chainFeed  *event.Feed
+
+	participantFeed *event.Feed
 }
 
 func (b *testBackend) ChainDb() ethdb.Database {
@@ -120,6 +122,10 @@ func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subsc
 	return b.chainFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeParticipantChangeEvent(ch chan<- core.ParticipantChangeEvent) event.Subscription {
+	return b.participantFeed.Subscribe(ch)
+}
+
 func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
@@ -166,7 +172,7 @@ func TestBlockSubscription(t *testing.T) {
 		rmLogsFeed  = new(event.Feed)
 		logsFeed    = new(event.Feed)
 		chainFeed   = new(event.Feed)
-		backend     = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend     = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api         = NewPublicFilterAPI(backend, false)
 		genesis     = new(core.Genesis).MustCommit(db)
 		chain, _    = core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
@@ -223,7 +229,7 @@ func TestPendingTxFilter(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		transactions = []*types.Transaction{
@@ -283,7 +289,7 @@ func TestLogFilterCreation(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		testCases = []struct {
@@ -332,7 +338,7 @@ func TestInvalidLogFilterCreation(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 	)
 
@@ -359,7 +365,7 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 		blockHash  = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
 	)
@@ -389,7 +395,7 @@ func TestLogFilter(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -508,7 +514,7 @@ func TestPendingLogsSubscription(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -614,3 +620,68 @@ func TestPendingLogsSubscription(t *testing.T) {
 		}
 	}
 }
+
+// TestParticipantFilter tests whether participant filters only return the
+// changes of the participants and pools they are interested in.
+func TestParticipantFilter(t *testing.T) {
+	var (
+		mux             = new(event.TypeMux)
+		db              = rawdb.NewMemoryDatabase()
+		txFeed          = new(event.Feed)
+		rmLogsFeed      = new(event.Feed)
+		logsFeed        = new(event.Feed)
+		chainFeed       = new(event.Feed)
+		participantFeed = new(event.Feed)
+		backend         = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, participantFeed}
+		api             = NewPublicFilterAPI(backend, false)
+
+		miner = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		other = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		pool  = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+		changes = []core.ParticipantChangeEvent{
+			{Kind: core.ParticipantJoined, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(100), Block: 1},
+			{Kind: core.ParticipantJoined, Role: types.PowParticipant, Address: other, Amount: big.NewInt(10), Block: 1},
+			{Kind: core.ParticipantBound, Role: types.PowParticipant, Address: other, Pool: pool, Amount: big.NewInt(10), Block: 2},
+			{Kind: core.ParticipantLeft, Role: types.PocParticipant, Address: miner, Amount: big.NewInt(100), Block: 3},
+		}
+	)
+	all := api.NewParticipantFilter(nil)
+	byMiner := api.NewParticipantFilter(&ParticipantCriteria{Addresses: []common.Address{miner}})
+	byPool := api.NewParticipantFilter(&ParticipantCriteria{Addresses: []common.Address{pool}})
+
+	time.Sleep(1 * time.Second)
+	for _, ev := range changes {
+		participantFeed.Send(ev)
+	}
+	tests := []struct {
+		id   rpc.ID
+		want []int // Indexes of the expected changes
+	}{
+		{all, []int{0, 1, 2, 3}},
+		{byMiner, []int{0, 3}},
+		{byPool, []int{2}},
+	}
+	for i, tt := range tests {
+		var have []*RPCParticipantChange
+
+		timeout := time.Now().Add(1 * time.Second)
+		for len(have) < len(tt.want) && time.Now().Before(timeout) {
+			results, err := api.GetFilterChanges(tt.id)
+			if err != nil {
+				t.Fatalf("test %d: unable to retrieve participant changes: %v", i, err)
+			}
+			have = append(have, results.([]*RPCParticipantChange)...)
+			time.Sleep(100 * time.Millisecond)
+		}
+		if len(have) != len(tt.want) {
+			t.Fatalf("test %d: change count mismatch: have %d, want %d", i, len(have), len(tt.want))
+		}
+		for j, index := range tt.want {
+			want := changes[index]
+			if have[j].Kind != want.Kind || have[j].Address != want.Address || have[j].Pool != want.Pool || uint64(have[j].BlockNumber) != want.Block {
+				t.Errorf("test %d, change %d: mismatch: have %+v, want %+v", i, j, have[j], want)
+			}
+		}
+	}
+}
@@ -64,6 +64,14 @@ func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*state.GetBalance(a.address)), nil
 }
 
+func (a *Account) AllPocBalance(ctx context.Context) (hexutil.Big, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*state.GetAllPocBalance(a.address)), nil
+}
+
 func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error) {
 	state, err := a.getState(ctx)
 	if err != nil {
@@ -42,6 +42,9 @@ const schema string = `
         address: Address!
         # Balance is the balance of the account, in wei.
         balance: BigInt!
+        # AllPocBalance is the total PoC reward paid to the account, in wei. PoC
+        # payouts stop once it reaches 120% of the account's mortgage.
+        allPocBalance: BigInt!
         # TransactionCount is the number of transactions sent from this account,
         # or in the case of a contract, the number of contracts created. Otherwise
         # known as the nonce.
@@ -21,7 +21,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/ethereum/go-ethereum/ethdb/reward"
 	"math/big"
 	"strings"
 	"time"
@@ -541,13 +540,14 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 
 // Result structs for GetProof
 type AccountResult struct {
-	Address      common.Address  `json:"address"`
-	AccountProof []string        `json:"accountProof"`
-	Balance      *hexutil.Big    `json:"balance"`
-	CodeHash     common.Hash     `json:"codeHash"`
-	Nonce        hexutil.Uint64  `json:"nonce"`
-	StorageHash  common.Hash     `json:"storageHash"`
-	StorageProof []StorageResult `json:"storageProof"`
+	Address       common.Address  `json:"address"`
+	AccountProof  []string        `json:"accountProof"`
+	Balance       *hexutil.Big    `json:"balance"`
+	AllPocBalance *hexutil.Big    `json:"allPocBalance"`
+	CodeHash      common.Hash     `json:"codeHash"`
+	Nonce         hexutil.Uint64  `json:"nonce"`
+	StorageHash   common.Hash     `json:"storageHash"`
+	StorageProof  []StorageResult `json:"storageProof"`
 }
 type StorageResult struct {
 	Key   string       `json:"key"`
@@ -595,13 +595,14 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 	}
 
 	return &AccountResult{
-		Address:      address,
-		AccountProof: common.ToHexArray(accountProof),
-		Balance:      (*hexutil.Big)(state.GetBalance(address)),
-		CodeHash:     codeHash,
-		Nonce:        hexutil.Uint64(state.GetNonce(address)),
-		StorageHash:  storageHash,
-		StorageProof: storageProof,
+		Address:       address,
+		AccountProof:  common.ToHexArray(accountProof),
+		Balance:       (*hexutil.Big)(state.GetBalance(address)),
+		AllPocBalance: (*hexutil.Big)(state.GetAllPocBalance(address)),
+		CodeHash:      codeHash,
+		Nonce:         hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:   storageHash,
+		StorageProof:  storageProof,
 	}, state.Error()
 }
 
@@ -648,8 +649,7 @@ func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.B
 			}
 		}
 		if fullTx {
-			cbx := reward.GetRewardsByNumber(block.Header().Number)
-			response["coinbase_txs"] = common.ToHex(cbx)
+			response["coinbase_txs"] = hexutil.Bytes(block.Header().CoinbaseTxs)
 		}
 		return response, err
 	}
@@ -831,6 +831,13 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 	if args.Data != nil {
 		data = []byte(*args.Data)
 	}
+	// Report creations of unknown or inactive contract kinds, as the state
+	// transition only marks them failed from the contract kind failure fork on
+	if args.To == nil {
+		if _, err := core.LookupContractKind(b.ChainConfig(), header.Number, data); err != nil {
+			return nil, 0, false, err
+		}
+	}
 
 	// Create new call message
 	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, data, false)
@@ -930,6 +937,24 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash
 		args.From = &common.Address{}
 	}
 	fmt.Println("========args.From", args.From.String())
+	// Reject creations of unknown or inactive contract kinds upfront, as they'd
+	// fail at any allowance
+	if args.To == nil {
+		header, err := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+		if err != nil {
+			return 0, err
+		}
+		if header == nil {
+			return 0, fmt.Errorf("block %v not found", blockNrOrHash)
+		}
+		var data []byte
+		if args.Data != nil {
+			data = *args.Data
+		}
+		if _, err := core.LookupContractKind(b.ChainConfig(), header.Number, data); err != nil {
+			return 0, err
+		}
+	}
 	// Create a helper to check if a gas allowance results in an executable transaction
 	executable := func(gas uint64) bool {
 		args.Gas = (*hexutil.Uint64)(&gas)
@@ -0,0 +1,680 @@
+// Copyright 2019 The nuc Team
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxRewardsPerQuery caps the number of rewards returned by nuc_getRewards.
+	maxRewardsPerQuery = 10000
+
+	// ruleCallTimeout caps the execution time of the rule contract getters.
+	ruleCallTimeout = 5 * time.Second
+
+	// maxDataContractsPerQuery caps the number of data contracts returned by
+	// nuc_listDataContracts.
+	maxDataContractsPerQuery = 10000
+)
+
+// burnAddressSelector is the selector of the rule contract's BURN_ADDRESS getter.
+var burnAddressSelector = crypto.Keccak256([]byte("BURN_ADDRESS()"))[:4]
+
+// two256 is 2^256, the seal target of a block being 2^256 / NUC difficulty.
+var two256 = new(big.Int).Lsh(common.Big1, 256)
+
+// PublicNUCAPI provides an API to access the NUC mining rewards.
+type PublicNUCAPI struct {
+	b Backend
+}
+
+// NewPublicNUCAPI creates a new NUC protocol API.
+func NewPublicNUCAPI(b Backend) *PublicNUCAPI {
+	return &PublicNUCAPI{b}
+}
+
+// RPCRewards is the breakdown of mining rewards per category.
+type RPCRewards struct {
+	Poc   *hexutil.Big `json:"poc"`
+	Pow   *hexutil.Big `json:"pow"`
+	Pool  *hexutil.Big `json:"pool"`
+	Post  *hexutil.Big `json:"post"`
+	Total *hexutil.Big `json:"total"`
+}
+
+// newRPCRewards assembles the rewards breakdown.
+func newRPCRewards(poc, pow, pool, post *big.Int) RPCRewards {
+	tx := &types.CoinbaseTx{PocReward: poc, PowReward: pow, PoolReward: pool, PostReward: post}
+	return RPCRewards{
+		Poc:   (*hexutil.Big)(poc),
+		Pow:   (*hexutil.Big)(pow),
+		Pool:  (*hexutil.Big)(pool),
+		Post:  (*hexutil.Big)(post),
+		Total: (*hexutil.Big)(tx.Total()),
+	}
+}
+
+// RPCAddressReward is the reward credited to an address by a canonical block.
+type RPCAddressReward struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	RPCRewards
+}
+
+// RPCRewardSummary is the total of the canonical rewards credited to an address.
+type RPCRewardSummary struct {
+	Address common.Address `json:"address"`
+	Blocks  hexutil.Uint64 `json:"blocks"`
+	RPCRewards
+}
+
+// RPCBlockRewardEntry is the reward credited to a single address by a block.
+type RPCBlockRewardEntry struct {
+	Address common.Address `json:"address"`
+	RPCRewards
+}
+
+// RPCBlockRewards is the decoded CoinbaseTxs of a block.
+type RPCBlockRewards struct {
+	BlockNumber hexutil.Uint64         `json:"blockNumber"`
+	BlockHash   common.Hash            `json:"blockHash"`
+	Rewards     []*RPCBlockRewardEntry `json:"rewards"`
+	Total       *hexutil.Big           `json:"total"`
+}
+
+// GetRewards returns the rewards credited to an address by the canonical blocks
+// in the inclusive range, in ascending block order.
+func (s *PublicNUCAPI) GetRewards(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]*RPCAddressReward, error) {
+	from, err := s.resolveNumber(ctx, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.resolveNumber(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	entries := rawdb.ReadAddressRewards(s.b.ChainDb(), address, from, to, maxRewardsPerQuery+1)
+	if len(entries) > maxRewardsPerQuery {
+		return nil, fmt.Errorf("too many rewards in block range %d-%d, limit is %d", from, to, maxRewardsPerQuery)
+	}
+	rewards := make([]*RPCAddressReward, 0, len(entries))
+	for _, entry := range entries {
+		rewards = append(rewards, &RPCAddressReward{
+			BlockNumber: hexutil.Uint64(entry.BlockNumber),
+			BlockHash:   entry.BlockHash,
+			RPCRewards:  newRPCRewards(entry.PocReward, entry.PowReward, entry.PoolReward, entry.PostReward),
+		})
+	}
+	return rewards, nil
+}
+
+// GetRewardSummary returns the totals of the rewards credited to an address by
+// the canonical chain.
+func (s *PublicNUCAPI) GetRewardSummary(ctx context.Context, address common.Address) (*RPCRewardSummary, error) {
+	summary := rawdb.ReadAddressRewardSummary(s.b.ChainDb(), address)
+	if summary == nil {
+		summary = new(rawdb.AddressRewardSummary)
+	}
+	return &RPCRewardSummary{
+		Address:    address,
+		Blocks:     hexutil.Uint64(summary.Blocks),
+		RPCRewards: newRPCRewards(summary.PocReward, summary.PowReward, summary.PoolReward, summary.PostReward),
+	}, nil
+}
+
+// GetBlockRewards returns the rewards committed to by the given block, decoded
+// from its header.
+func (s *PublicNUCAPI) GetBlockRewards(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*RPCBlockRewards, error) {
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	entries, err := types.DecodeBlockCoinbaseTxs(s.b.ChainConfig(), header.Number, header.CoinbaseTxs)
+	if err != nil {
+		return nil, err
+	}
+	rewards := &RPCBlockRewards{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		Rewards:     make([]*RPCBlockRewardEntry, 0, len(entries)),
+	}
+	total := new(big.Int)
+	for _, entry := range entries {
+		rewards.Rewards = append(rewards.Rewards, &RPCBlockRewardEntry{
+			Address:    entry.Address,
+			RPCRewards: newRPCRewards(entry.PocReward, entry.PowReward, entry.PoolReward, entry.PostReward),
+		})
+		total.Add(total, entry.Total())
+	}
+	rewards.Total = (*hexutil.Big)(total)
+	return rewards, nil
+}
+
+// RPCSupply is the coin supply accounting of a block. Circulating supply excludes
+// the coins held by the rule contract's BURN_ADDRESS, if it defines one.
+type RPCSupply struct {
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Minted      *hexutil.Big    `json:"minted"`
+	Fees        *hexutil.Big    `json:"fees"`
+	Team        *hexutil.Big    `json:"team"`
+	Burnt       *hexutil.Big    `json:"burnt"`
+	TotalMinted *hexutil.Big    `json:"totalMinted"`
+	TotalFees   *hexutil.Big    `json:"totalFees"`
+	TotalTeam   *hexutil.Big    `json:"totalTeam"`
+	TotalBurnt  *hexutil.Big    `json:"totalBurnt"`
+	Supply      *hexutil.Big    `json:"supply"`
+	BurnAddress *common.Address `json:"burnAddress"`
+	BurnHeld    *hexutil.Big    `json:"burnHeld"`
+	Circulating *hexutil.Big    `json:"circulating"`
+	FeeSplit    *RPCFeeSplit    `json:"feeSplit"`
+}
+
+// RPCFeeSplit is how the transaction fees of a block were split.
+type RPCFeeSplit struct {
+	Coinbase     *hexutil.Big `json:"coinbase"`
+	Participants *hexutil.Big `json:"participants"`
+	Team         *hexutil.Big `json:"team"`
+	Burnt        *hexutil.Big `json:"burnt"`
+}
+
+// GetSupply returns the coin supply accounting of the given block: the coins it
+// minted, redistributed and burnt, the totals of its chain and the circulating
+// supply once the coins held by BURN_ADDRESS are taken out.
+func (s *PublicNUCAPI) GetSupply(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*RPCSupply, error) {
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	var (
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+	)
+	supply := rawdb.ReadBlockSupply(s.b.ChainDb(), hash, number)
+	if supply == nil {
+		return nil, fmt.Errorf("supply of block %d not accounted", number)
+	}
+	result := &RPCSupply{
+		BlockNumber: hexutil.Uint64(number),
+		BlockHash:   hash,
+		Minted:      (*hexutil.Big)(supply.Minted),
+		Fees:        (*hexutil.Big)(supply.Fees),
+		Team:        (*hexutil.Big)(supply.Team),
+		Burnt:       (*hexutil.Big)(supply.Burnt),
+		TotalMinted: (*hexutil.Big)(supply.TotalMinted),
+		TotalFees:   (*hexutil.Big)(supply.TotalFees),
+		TotalTeam:   (*hexutil.Big)(supply.TotalTeam),
+		TotalBurnt:  (*hexutil.Big)(supply.TotalBurnt),
+		Supply:      (*hexutil.Big)(supply.Supply()),
+		BurnHeld:    new(hexutil.Big),
+		Circulating: (*hexutil.Big)(supply.Supply()),
+	}
+	if split := rawdb.ReadBlockFeeSplit(s.b.ChainDb(), hash, number); split != nil {
+		result.FeeSplit = &RPCFeeSplit{
+			Coinbase:     (*hexutil.Big)(split.Coinbase),
+			Participants: (*hexutil.Big)(split.Participants),
+			Team:         (*hexutil.Big)(split.Team),
+			Burnt:        (*hexutil.Big)(split.Burnt),
+		}
+	}
+	at := rpc.BlockNumberOrHashWithHash(hash, false)
+	data := hexutil.Bytes(burnAddressSelector)
+	res, _, failed, err := DoCall(ctx, s.b, CallArgs{To: &params.NUCRuleContractAddress, Data: &data}, at, nil, vm.Config{}, ruleCallTimeout, s.b.RPCGasCap())
+	if err != nil || failed || len(res) != common.HashLength {
+		return result, nil
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, at)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	burn := common.BytesToAddress(res)
+	held := new(big.Int).Set(state.GetBalance(burn))
+
+	result.BurnAddress = &burn
+	result.BurnHeld = (*hexutil.Big)(held)
+	result.Circulating = (*hexutil.Big)(new(big.Int).Sub(supply.Supply(), held))
+	return result, nil
+}
+
+// GetPocAccumulated returns the PoC rewards accumulated by an address at the
+// given block. PoC payouts stop once this total reaches 120% of the address's
+// mortgage.
+func (s *PublicNUCAPI) GetPocAccumulated(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.GetAllPocBalance(address)), state.Error()
+}
+
+// RPCPool is a mining pool registered in the rule contract, with the miners bound
+// to it and their contributions to its cut.
+type RPCPool struct {
+	Address       common.Address         `json:"address"`
+	BlockNumber   hexutil.Uint64         `json:"blockNumber"`
+	BlockHash     common.Hash            `json:"blockHash"`
+	Mortgage      *hexutil.Big           `json:"mortgage"`
+	Poc           []common.Address       `json:"poc"`
+	Pow           []common.Address       `json:"pow"`
+	FromBlock     hexutil.Uint64         `json:"fromBlock"`
+	PoolReward    *hexutil.Big           `json:"poolReward"`
+	Contributions []*RPCPoolContribution `json:"contributions"`
+}
+
+// RPCPoolContribution is the cut a pool received from the rewards of one of its
+// bound miners.
+type RPCPoolContribution struct {
+	Address common.Address `json:"address"`
+	Poc     *hexutil.Big   `json:"poc"`
+	Pow     *hexutil.Big   `json:"pow"`
+}
+
+// GetPool returns the pool registered at the given address as of the given block:
+// its mortgage and the PoC and PoW miners bound to it. The pool cut received in
+// the blocks from fromBlock (defaulting to the given block) up to the given one
+// is taken from the reward ledger, along with the contribution of every bound
+// miner. Contributions are derived from the rewards the miners received, see
+// ethash.BoundPoolCut, and assume they were bound throughout the range. Null is
+// returned if the address isn't a registered pool.
+func (s *PublicNUCAPI) GetPool(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash, fromBlock *rpc.BlockNumber) (*RPCPool, error) {
+	snap, header, err := s.ruleSnapshot(ctx, blockNrOrHash)
+	if snap == nil || err != nil {
+		return nil, err
+	}
+	var pool *RPCPool
+	for _, u := range snap.Poolers {
+		if u.UserAddr == address {
+			pool = &RPCPool{
+				Address:     address,
+				BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+				BlockHash:   header.Hash(),
+				Mortgage:    (*hexutil.Big)(u.BuyBalance),
+				Poc:         []common.Address{},
+				Pow:         []common.Address{},
+			}
+			break
+		}
+	}
+	if pool == nil {
+		return nil, nil
+	}
+	members := make(map[common.Address]*RPCPoolContribution)
+	member := func(addr common.Address) *RPCPoolContribution {
+		if members[addr] == nil {
+			members[addr] = &RPCPoolContribution{Address: addr, Poc: new(hexutil.Big), Pow: new(hexutil.Big)}
+			pool.Contributions = append(pool.Contributions, members[addr])
+		}
+		return members[addr]
+	}
+	for _, u := range snap.Pocers {
+		if u.BindPoolAddr == address {
+			pool.Poc = append(pool.Poc, u.UserAddr)
+			member(u.UserAddr)
+		}
+	}
+	for _, u := range snap.Powers {
+		if u.BindPoolAddr == address {
+			pool.Pow = append(pool.Pow, u.UserAddr)
+			member(u.UserAddr)
+		}
+	}
+	// Sum the pool cut and the contributions over the requested range
+	to := header.Number.Uint64()
+	from := to
+	if fromBlock != nil {
+		if from, err = s.resolveNumber(ctx, *fromBlock); err != nil {
+			return nil, err
+		}
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	pool.FromBlock = hexutil.Uint64(from)
+
+	rewards, err := s.rangeRewards(address, from, to)
+	if err != nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	for _, entry := range rewards {
+		total.Add(total, entry.PoolReward)
+	}
+	pool.PoolReward = (*hexutil.Big)(total)
+
+	config := s.b.ChainConfig()
+	for _, contribution := range pool.Contributions {
+		rewards, err := s.rangeRewards(contribution.Address, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range rewards {
+			if config.NUCRewardVersion(new(big.Int).SetUint64(entry.BlockNumber)) != params.NUCRewardV4 {
+				continue
+			}
+			(*big.Int)(contribution.Poc).Add((*big.Int)(contribution.Poc), ethash.BoundPoolCut(entry.PocReward))
+			(*big.Int)(contribution.Pow).Add((*big.Int)(contribution.Pow), ethash.BoundPoolCut(entry.PowReward))
+		}
+	}
+	if pool.Contributions == nil {
+		pool.Contributions = []*RPCPoolContribution{}
+	}
+	return pool, nil
+}
+
+// RPCBinding is the pool a miner is bound to, per mining method.
+type RPCBinding struct {
+	Address     common.Address  `json:"address"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	PocPool     *common.Address `json:"pocPool"`
+	PowPool     *common.Address `json:"powPool"`
+}
+
+// GetBinding returns the pools a miner's PoC and PoW registrations are bound to
+// as of the latest block. A pool is null if the miner isn't registered for the
+// method or not bound.
+func (s *PublicNUCAPI) GetBinding(ctx context.Context, address common.Address) (*RPCBinding, error) {
+	snap, header, err := s.ruleSnapshot(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if snap == nil || err != nil {
+		return nil, err
+	}
+	binding := &RPCBinding{
+		Address:     address,
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+	}
+	for _, u := range snap.Pocers {
+		if u.UserAddr == address && u.BindPoolAddr != (common.Address{}) {
+			pool := u.BindPoolAddr
+			binding.PocPool = &pool
+		}
+	}
+	for _, u := range snap.Powers {
+		if u.UserAddr == address && u.BindPoolAddr != (common.Address{}) {
+			pool := u.BindPoolAddr
+			binding.PowPool = &pool
+		}
+	}
+	return binding, nil
+}
+
+// ruleSnapshot returns the participant snapshot of the rule contract as of the
+// post state of the given block.
+func (s *PublicNUCAPI) ruleSnapshot(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*ethash.RuleSnapshot, *types.Header, error) {
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, nil, err
+	}
+	return ethash.GetRuleSnapshot(header, state, &backendChain{ctx: ctx, b: s.b}), header, nil
+}
+
+// rangeRewards returns the rewards credited to an address by the canonical blocks
+// in the inclusive range, failing if there are too many.
+func (s *PublicNUCAPI) rangeRewards(address common.Address, from, to uint64) ([]*rawdb.AddressReward, error) {
+	entries := rawdb.ReadAddressRewards(s.b.ChainDb(), address, from, to, maxRewardsPerQuery+1)
+	if len(entries) > maxRewardsPerQuery {
+		return nil, fmt.Errorf("too many rewards in block range %d-%d, limit is %d", from, to, maxRewardsPerQuery)
+	}
+	return entries, nil
+}
+
+// backendChain adapts a Backend into the consensus.ChainReader the rule contract
+// is called with. Only headers, blocks and the config can be looked up.
+type backendChain struct {
+	ctx context.Context
+	b   Backend
+}
+
+func (c *backendChain) Config() *params.ChainConfig      { return c.b.ChainConfig() }
+func (c *backendChain) ChainConfig() *params.ChainConfig { return c.b.ChainConfig() }
+func (c *backendChain) CurrentHeader() *types.Header     { return c.b.CurrentBlock().Header() }
+
+func (c *backendChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header, _ := c.b.HeaderByHash(c.ctx, hash)
+	if header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
+func (c *backendChain) GetHeaderByNumber(number uint64) *types.Header {
+	header, _ := c.b.HeaderByNumber(c.ctx, rpc.BlockNumber(number))
+	return header
+}
+
+func (c *backendChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, _ := c.b.HeaderByHash(c.ctx, hash)
+	return header
+}
+
+func (c *backendChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	block, _ := c.b.BlockByHash(c.ctx, hash)
+	if block == nil || block.NumberU64() != number {
+		return nil
+	}
+	return block
+}
+
+func (c *backendChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return nil, errors.New("state lookup not supported")
+}
+
+// Engine implements core.ChainContext. System calls name the block's coinbase
+// as the author, so no engine is ever needed.
+func (c *backendChain) Engine() consensus.Engine { return nil }
+
+// SystemCall implements consensus.SystemCaller.
+func (c *backendChain) SystemCall(header *types.Header, statedb *state.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, error) {
+	return core.SystemCall(c.Config(), c, header, statedb, vm.Config{}, from, to, input, gas)
+}
+
+// ApplySystemMessage implements consensus.SystemCaller.
+func (c *backendChain) ApplySystemMessage(header *types.Header, statedb vm.StateDB, from, to common.Address, input []byte, gas uint64) ([]byte, bool, error) {
+	return core.ApplySystemMessage(c.Config(), c, header, statedb, vm.Config{}, from, to, input, gas)
+}
+
+// RPCDataContract is an IPFS data contract created by the canonical chain. The
+// payload is only filled in when looking up a single contract.
+type RPCDataContract struct {
+	Address     common.Address `json:"address"`
+	CID         string         `json:"cid"`
+	Size        hexutil.Uint64 `json:"size"`
+	Creator     common.Address `json:"creator"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Payload     hexutil.Bytes  `json:"payload,omitempty"`
+}
+
+// newRPCDataContract converts an indexed data contract into its RPC form.
+func newRPCDataContract(contract *rawdb.DataContract) *RPCDataContract {
+	return &RPCDataContract{
+		Address:     contract.Address,
+		CID:         types.EncodeCID(contract.Multihash),
+		Size:        hexutil.Uint64(contract.Size),
+		Creator:     contract.Creator,
+		TxHash:      contract.TxHash,
+		BlockHash:   contract.BlockHash,
+		BlockNumber: hexutil.Uint64(contract.BlockNumber),
+	}
+}
+
+// GetDataContract returns the IPFS data contract at the given address along with
+// its payload, or nil if there's no such contract in the canonical chain.
+func (s *PublicNUCAPI) GetDataContract(ctx context.Context, address common.Address) (*RPCDataContract, error) {
+	contract := rawdb.ReadDataContract(s.b.ChainDb(), address)
+	if contract == nil {
+		return nil, nil
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := newRPCDataContract(contract)
+	result.Payload = core.DataContractPayload(state.GetCode(address))
+	return result, state.Error()
+}
+
+// GetDataContractByCID returns the oldest IPFS data contract storing the payload
+// with the given CID (a base58 sha2-256 multihash of the raw payload), or nil if
+// there's no such contract in the canonical chain.
+func (s *PublicNUCAPI) GetDataContractByCID(ctx context.Context, cid string) (*RPCDataContract, error) {
+	multihash, err := types.DecodeCID(cid)
+	if err != nil {
+		return nil, err
+	}
+	addresses := rawdb.ReadDataContractsByCID(s.b.ChainDb(), multihash, 1)
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	return s.GetDataContract(ctx, addresses[0])
+}
+
+// ListDataContracts returns the IPFS data contracts created by the given account
+// in the canonical chain, oldest first and without their payloads.
+func (s *PublicNUCAPI) ListDataContracts(ctx context.Context, creator common.Address) ([]*RPCDataContract, error) {
+	db := s.b.ChainDb()
+
+	results := []*RPCDataContract{}
+	for _, address := range rawdb.ReadCreatorDataContracts(db, creator, maxDataContractsPerQuery) {
+		if contract := rawdb.ReadDataContract(db, address); contract != nil {
+			results = append(results, newRPCDataContract(contract))
+		}
+	}
+	return results, nil
+}
+
+// resolveNumber converts a block number, possibly a special tag, into the number
+// of a canonical block.
+func (s *PublicNUCAPI) resolveNumber(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	if number >= 0 {
+		return uint64(number), nil
+	}
+	header, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil || err != nil {
+		return 0, fmt.Errorf("block %d not found", number)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// RPCDifficultyFactor is a NUC difficulty discount rule as evaluated for a block.
+// A discount divides the difficulty by the divisor.
+type RPCDifficultyFactor struct {
+	Rule      string          `json:"rule"`
+	Enforced  bool            `json:"enforced"`
+	Qualified bool            `json:"qualified"`
+	Applied   bool            `json:"applied"`
+	Value     *hexutil.Big    `json:"value"`
+	Threshold *hexutil.Big    `json:"threshold"`
+	Divisor   hexutil.Uint64  `json:"divisor"`
+	Blocks    *hexutil.Uint64 `json:"blocks,omitempty"`
+}
+
+// RPCDifficultyBreakdown explains the NUC difficulty of a block, either sealed
+// or projected, in which case it has no hash.
+type RPCDifficultyBreakdown struct {
+	BlockNumber   hexutil.Uint64         `json:"blockNumber"`
+	BlockHash     *common.Hash           `json:"blockHash"`
+	Coinbase      common.Address         `json:"coinbase"`
+	Difficulty    *hexutil.Big           `json:"difficulty"`
+	Factors       []*RPCDifficultyFactor `json:"factors"`
+	NUCDifficulty *hexutil.Big           `json:"nucDifficulty"`
+	SealTarget    *hexutil.Big           `json:"sealTarget"`
+}
+
+// GetDifficultyBreakdown explains how the NUC difficulty of a block derives from
+// its difficulty: the discounts applied, why and the resulting seal target.
+func (s *PublicNUCAPI) GetDifficultyBreakdown(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*RPCDifficultyBreakdown, error) {
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	if header.Number.Sign() == 0 {
+		return nil, errors.New("genesis block has no difficulty discounts")
+	}
+	breakdown, err := s.difficultyBreakdown(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+	hash := header.Hash()
+	breakdown.BlockHash = &hash
+	return breakdown, nil
+}
+
+// ProjectDifficulty explains the NUC difficulty the given address would seal the
+// next block at, were it mined on top of the current head right now.
+func (s *PublicNUCAPI) ProjectDifficulty(ctx context.Context, address common.Address) (*RPCDifficultyBreakdown, error) {
+	parent := s.b.CurrentBlock().Header()
+
+	timestamp := uint64(time.Now().Unix())
+	if timestamp <= parent.Time {
+		timestamp = parent.Time + 1
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Coinbase:   address,
+		Difficulty: ethash.CalcDifficulty(s.b.ChainConfig(), timestamp, parent),
+		Time:       timestamp,
+	}
+	return s.difficultyBreakdown(ctx, header)
+}
+
+// difficultyBreakdown explains the NUC difficulty of a header, projecting it if
+// the header has none.
+func (s *PublicNUCAPI) difficultyBreakdown(ctx context.Context, header *types.Header) (*RPCDifficultyBreakdown, error) {
+	parentState, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(header.ParentHash, false))
+	if parentState == nil || err != nil {
+		return nil, fmt.Errorf("parent state of block %d unavailable: %v", header.Number, err)
+	}
+	breakdown := consensus.ExplainNUCDifficulty(&backendChain{ctx: ctx, b: s.b}, header, parentState)
+
+	result := &RPCDifficultyBreakdown{
+		BlockNumber:   hexutil.Uint64(header.Number.Uint64()),
+		Coinbase:      header.Coinbase,
+		Difficulty:    (*hexutil.Big)(breakdown.Difficulty),
+		NUCDifficulty: (*hexutil.Big)(breakdown.NUCDifficulty),
+		SealTarget:    (*hexutil.Big)(new(big.Int)),
+	}
+	if breakdown.NUCDifficulty.Sign() > 0 {
+		result.SealTarget = (*hexutil.Big)(new(big.Int).Div(two256, breakdown.NUCDifficulty))
+	}
+	for _, factor := range breakdown.Factors {
+		rpcFactor := &RPCDifficultyFactor{
+			Rule:      factor.Rule,
+			Enforced:  factor.Enforced,
+			Qualified: factor.Qualified,
+			Applied:   factor.Applied,
+			Value:     (*hexutil.Big)(factor.Value),
+			Threshold: (*hexutil.Big)(factor.Threshold),
+			Divisor:   hexutil.Uint64(factor.Divisor),
+		}
+		if factor.Blocks > 0 {
+			blocks := hexutil.Uint64(factor.Blocks)
+			rpcFactor.Blocks = &blocks
+		}
+		result.Factors = append(result.Factors, rpcFactor)
+	}
+	return result, nil
+}
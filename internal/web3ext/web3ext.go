@@ -27,6 +27,7 @@ var Modules = map[string]string{
 	"eth":        EthJs,
 	"miner":      MinerJs,
 	"net":        NetJs,
+	"nuc":        NUCJs,
 	"personal":   PersonalJs,
 	"rpc":        RpcJs,
 	"shh":        ShhJs,
@@ -126,6 +127,11 @@ web3._extend({
 			call: 'ethash_getWork',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'getWorkNUC',
+			call: 'ethash_getWorkNUC',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'getHashrate',
 			call: 'ethash_getHashrate',
@@ -754,6 +760,91 @@ web3._extend({
 });
 `
 
+const NUCJs = `
+web3._extend({
+	property: 'nuc',
+	methods: [
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'nuc_getRewards',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRewardSummary',
+			call: 'nuc_getRewardSummary',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockRewards',
+			call: 'nuc_getBlockRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSupply',
+			call: 'nuc_getSupply',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPocAccumulated',
+			call: 'nuc_getPocAccumulated',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPool',
+			call: 'nuc_getPool',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBinding',
+			call: 'nuc_getBinding',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDifficultyBreakdown',
+			call: 'nuc_getDifficultyBreakdown',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'projectDifficulty',
+			call: 'nuc_projectDifficulty',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'pendingRewards',
+			call: 'nuc_pendingRewards',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getDataContract',
+			call: 'nuc_getDataContract',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDataContractByCID',
+			call: 'nuc_getDataContractByCID',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'listDataContracts',
+			call: 'nuc_listDataContracts',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+	],
+	properties: []
+});
+`
+
 const AccountingJs = `
 web3._extend({
 	property: 'accounting',
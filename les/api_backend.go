@@ -229,6 +229,15 @@ func (b *LesApiBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEven
 	return b.eth.blockchain.SubscribeRemovedLogsEvent(ch)
 }
 
+// SubscribeParticipantChangeEvent implements filters.Backend. Light clients don't
+// track the participants of the rule contract, so no event is ever sent.
+func (b *LesApiBackend) SubscribeParticipantChangeEvent(ch chan<- core.ParticipantChangeEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
 func (b *LesApiBackend) Downloader() *downloader.Downloader {
 	return b.eth.Downloader()
 }
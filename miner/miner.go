@@ -43,14 +43,17 @@ type Backend interface {
 
 // Config is the configuration parameters of mining.
 type Config struct {
-	Etherbase common.Address `toml:",omitempty"` // Public address for block mining rewards (default = first account)
-	Notify    []string       `toml:",omitempty"` // HTTP URL list to be notified of new work packages(only useful in ethash).
-	ExtraData hexutil.Bytes  `toml:",omitempty"` // Block extra data set by the miner
-	GasFloor  uint64         // Target gas floor for mined blocks.
-	GasCeil   uint64         // Target gas ceiling for mined blocks.
-	GasPrice  *big.Int       // Minimum gas price for mining a transaction
-	Recommit  time.Duration  // The time interval for miner to re-create mining work.
-	Noverify  bool           // Disable remote mining solution verification(only useful in ethash).
+	Etherbase         common.Address `toml:",omitempty"` // Public address for block mining rewards (default = first account)
+	Notify            []string       `toml:",omitempty"` // HTTP URL list to be notified of new work packages(only useful in ethash).
+	NotifyNUC         bool           `toml:",omitempty"` // Notify with NUC work packages instead of the classic ones(only useful in ethash).
+	Stratum           string         `toml:",omitempty"` // Listening address of the stratum server, disabled if empty(only useful in ethash).
+	StratumDifficulty float64        `toml:",omitempty"` // Share difficulty assigned to the stratum workers(only useful in ethash).
+	ExtraData         hexutil.Bytes  `toml:",omitempty"` // Block extra data set by the miner
+	GasFloor          uint64         // Target gas floor for mined blocks.
+	GasCeil           uint64         // Target gas ceiling for mined blocks.
+	GasPrice          *big.Int       // Minimum gas price for mining a transaction
+	Recommit          time.Duration  // The time interval for miner to re-create mining work.
+	Noverify          bool           // Disable remote mining solution verification(only useful in ethash).
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -178,6 +181,21 @@ func (miner *Miner) PendingBlock() *types.Block {
 	return miner.worker.pendingBlock()
 }
 
+// PendingRewards returns the last block committed for sealing along with the
+// rewards it would credit, or nil if the consensus engine can't break them down.
+//
+// Note, the block isn't necessarily the pending block: it is updated only when
+// the block is finalized for sealing, not as transactions are added to it.
+func (miner *Miner) PendingRewards() (*types.Block, *types.BlockRewards) {
+	return miner.worker.pendingRewards()
+}
+
+// SubscribePendingRewardsEvent starts delivering the rewards of every block
+// committed for sealing.
+func (miner *Miner) SubscribePendingRewardsEvent(ch chan<- core.PendingRewardsEvent) event.Subscription {
+	return miner.worker.subscribePendingRewardsEvent(ch)
+}
+
 func (miner *Miner) SetEtherbase(addr common.Address) {
 	miner.coinbase = addr
 	miner.worker.setEtherbase(addr)
@@ -163,6 +163,12 @@ type worker struct {
 	snapshotBlock *types.Block
 	snapshotState *state.StateDB
 
+	rewardsMu    sync.RWMutex        // The lock used to protect the rewards snapshot
+	rewardsBlock *types.Block        // Last block committed for sealing whose rewards are known
+	rewards      *types.BlockRewards // Rewards of rewardsBlock
+	rewardsFeed  event.Feed
+	scope        event.SubscriptionScope
+
 	// atomic status counters
 	running int32 // The indicator whether the consensus engine is running or not.
 	newTxs  int32 // New arrival transaction count since last sealing work submitting.
@@ -267,6 +273,19 @@ func (w *worker) pendingBlock() *types.Block {
 	return w.snapshotBlock
 }
 
+// pendingRewards returns the last block committed for sealing along with the
+// rewards it would credit, or nil if the engine can't break them down.
+func (w *worker) pendingRewards() (*types.Block, *types.BlockRewards) {
+	w.rewardsMu.RLock()
+	defer w.rewardsMu.RUnlock()
+	return w.rewardsBlock, w.rewards
+}
+
+// subscribePendingRewardsEvent registers a subscription of PendingRewardsEvent.
+func (w *worker) subscribePendingRewardsEvent(ch chan<- core.PendingRewardsEvent) event.Subscription {
+	return w.scope.Track(w.rewardsFeed.Subscribe(ch))
+}
+
 // start sets the running status as 1 and triggers new work submitting.
 func (w *worker) start() {
 	atomic.StoreInt32(&w.running, 1)
@@ -287,6 +306,7 @@ func (w *worker) isRunning() bool {
 // Note the worker does not support being closed multiple times.
 func (w *worker) close() {
 	close(w.exitCh)
+	w.scope.Close()
 }
 
 // newWorkLoop is a standalone goroutine to submit new mining work upon received events.
@@ -894,6 +914,9 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	if w.chainConfig.DAOForkSupport && w.chainConfig.DAOForkBlock != nil && w.chainConfig.DAOForkBlock.Cmp(header.Number) == 0 {
 		misc.ApplyDAOHardFork(env.state)
 	}
+	if upgrade := w.chainConfig.NUCRuleUpgrade(header.Number); upgrade != nil {
+		misc.ApplyNUCRuleUpgrade(env.state, upgrade)
+	}
 	// Accumulate the uncles for the current block
 	uncles := make([]*types.Header, 0, 2)
 	commitUncles := func(blocks map[common.Hash]*types.Block) {
@@ -1002,6 +1025,26 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 	}
 	if update {
 		w.updateSnapshot()
+		w.updateRewards(block, receipts)
 	}
 	return nil
 }
+
+// updateRewards breaks down the rewards of the block committed for sealing, if
+// the engine supports it, and posts them to the subscribers.
+func (w *worker) updateRewards(block *types.Block, receipts []*types.Receipt) {
+	reporter, ok := w.engine.(consensus.RewardReporter)
+	if !ok {
+		return
+	}
+	rewards, err := reporter.BlockRewards(w.chain, block, receipts)
+	if err != nil {
+		log.Debug("Pending block rewards unavailable", "number", block.Number(), "err", err)
+		return
+	}
+	w.rewardsMu.Lock()
+	w.rewardsBlock, w.rewards = block, rewards
+	w.rewardsMu.Unlock()
+
+	w.rewardsFeed.Send(core.PendingRewardsEvent{Block: block, Rewards: rewards})
+}
@@ -309,6 +309,38 @@ func testEmptyWork(t *testing.T, chainConfig *params.ChainConfig, engine consens
 	}
 }
 
+// Tests that the rewards of every block committed for sealing are posted and
+// kept as the pending rewards.
+func TestPendingRewards(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	events := make(chan core.PendingRewardsEvent, 4)
+	sub := w.subscribePendingRewardsEvent(events)
+	defer sub.Unsubscribe()
+
+	w.skipSealHook = func(task *task) bool { return true }
+	w.start()
+
+	select {
+	case ev := <-events:
+		if ev.Block.NumberU64() != 1 {
+			t.Fatalf("block number mismatch: have %d, want 1", ev.Block.NumberU64())
+		}
+		if block, rewards := w.pendingRewards(); block == nil || rewards == nil || block.Number().Uint64() < 1 {
+			t.Fatalf("pending rewards not kept: have %v/%v", block, rewards)
+		}
+		if ev.Rewards.Leftover == nil || ev.Rewards.Team == nil {
+			t.Fatalf("incomplete rewards: %+v", ev.Rewards)
+		}
+	case <-time.NewTimer(3 * time.Second).C:
+		t.Fatalf("pending rewards timeout")
+	}
+}
+
 func TestStreamUncleBlock(t *testing.T) {
 	ethash := ethash.NewFaker()
 	defer ethash.Close()
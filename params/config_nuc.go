@@ -0,0 +1,722 @@
+// Copyright 2019 The nuc Team
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Reward algorithm versions that can be scheduled through NUCConfig.RewardRules.
+const (
+	NUCRewardV1 = 1 // Per category split among contract users (accumulateRewardsold)
+	NUCRewardV2 = 2 // Weighted PoC/PoW/Pool split with ranking and PoST rewards
+	NUCRewardV3 = 3 // Mortgage based rewards with pow/pool children
+	NUCRewardV4 = 4 // PoC/PoW rewards with a 10% cut to the bound pool
+
+	// NUCRewardLatest is the reward version used when a chain config doesn't
+	// schedule any reward rules.
+	NUCRewardLatest = NUCRewardV4
+)
+
+// Rule contract ABI versions that can be scheduled through NUCConfig.RuleUpgrades,
+// each read by the Go caller of the same version under consensus/ethash/nuc_token.
+const (
+	NUCRuleV1 = 1 // Rule contract read by the V4 rewards
+
+	// NUCRuleLatest is the newest rule contract ABI the consensus engine reads.
+	NUCRuleLatest = NUCRuleV1
+)
+
+// NUCRuleContractAddress is where the rule contract holding the NUC participants
+// lives.
+var NUCRuleContractAddress = common.HexToAddress("0x0000000000000000000000000000000000000011")
+
+// NUCFeePoolAddress collects the participants' share of the transaction fees
+// of a block under a fee policy, until the block is finalized and the pool is
+// shared out between the rewarded participants.
+var NUCFeePoolAddress = common.HexToAddress("0x000000000000000000000000000000000000fee0")
+
+// NUCConfig holds the NUC specific consensus rules, each of them scheduled by
+// block number.
+type NUCConfig struct {
+	// RewardRules lists the reward algorithm switches, ordered by block. The
+	// rule with the highest block not above the current one is in force.
+	RewardRules []NUCRewardRule `json:"rewardRules,omitempty"`
+
+	// CoinbaseTxsBlock records the rewards in the header's CoinbaseTxs with the
+	// versioned RLP encoding. Before it, the legacy encoding is used: 44 bytes per
+	// rewarded address holding the poc, pow and pool rewards as 8 byte integers
+	// (nil = legacy encoding).
+	CoinbaseTxsBlock *big.Int `json:"coinbaseTxsBlock,omitempty"`
+
+	// TxCountDiscountBlock enforces the NUC difficulty discount of miners that
+	// recently sent transactions (nil = not enforced).
+	TxCountDiscountBlock *big.Int `json:"txCountDiscountBlock,omitempty"`
+
+	// BalanceDiscountBlock enforces the NUC difficulty discount of miners
+	// holding a large balance (nil = not enforced).
+	BalanceDiscountBlock *big.Int `json:"balanceDiscountBlock,omitempty"`
+
+	// ContractKindFailureBlock includes the contract creations rejected by the
+	// contract kind registry as failed transactions instead of invalidating
+	// them (nil = rejections invalidate the transaction).
+	ContractKindFailureBlock *big.Int `json:"contractKindFailureBlock,omitempty"`
+
+	// DataContractGasBlock charges the IPFS data contract creations for storing
+	// the data and stores it as the code of the contract. Before it, the data
+	// isn't stored and a creation leaves 100 gas over, whatever it was given
+	// (nil = legacy creations).
+	DataContractGasBlock *big.Int `json:"dataContractGasBlock,omitempty"`
+
+	// SystemCallBlock runs the consensus reads of the rule contract as
+	// side-effect-free system calls. Before it, every read is a message from
+	// the rule contract to itself bumping its nonce and touching the coinbase
+	// (nil = legacy reads).
+	SystemCallBlock *big.Int `json:"systemCallBlock,omitempty"`
+
+	// SupplyInvariantBlock rejects the blocks whose finalization credits other
+	// coins than accounted from their rewards, or schedules participant rewards
+	// above the block reward. Before it, violations are only logged
+	// (nil = logged only).
+	SupplyInvariantBlock *big.Int `json:"supplyInvariantBlock,omitempty"`
+
+	// FeePolicies lists the transaction fee splits, ordered by block. Before the
+	// first policy, the legacy split is in force: 30% of the fee to the coinbase
+	// and a fee derived from the gas limits of the transactions minted for the
+	// participants and the team when finalizing the block.
+	FeePolicies []NUCFeePolicy `json:"feePolicies,omitempty"`
+
+	// RuleUpgrades lists the governed upgrades of the rule contract, ordered by
+	// block. Before the first one, the contract is read through NUCRuleV1.
+	RuleUpgrades []NUCRuleUpgrade `json:"ruleUpgrades,omitempty"`
+
+	// DifficultyRules lists the parameters of the NUC difficulty discounts,
+	// ordered by block. Before the first one, DefaultNUCDifficultyRules are in
+	// force.
+	DifficultyRules []NUCDifficultyRules `json:"difficultyRules,omitempty"`
+
+	// RuleStorageLayouts lists the storage layouts of the rule contract, ordered
+	// by block. From the first one on, the participants are decoded straight
+	// from the contract storage instead of being paged through its list getters,
+	// which can run out of gas. Before it, the getters are called.
+	RuleStorageLayouts []NUCRuleStorageLayout `json:"ruleStorageLayouts,omitempty"`
+}
+
+// NUCRuleUpgrade replaces the code and overwrites storage slots of the rule
+// contract at the start of a given block, before any transaction runs, and
+// switches the consensus engine to reading it through the given ABI version.
+type NUCRuleUpgrade struct {
+	Block   *big.Int                    `json:"block"`             // Block the upgrade is applied at
+	Version uint64                      `json:"version"`           // Rule contract ABI version from the block on
+	Code    hexutil.Bytes               `json:"code,omitempty"`    // New runtime code, nil to keep the current one
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"` // Storage slots to overwrite, zero values clear them
+}
+
+// String implements the stringer interface.
+func (u NUCRuleUpgrade) String() string {
+	return fmt.Sprintf("v%d code %d bytes, %d slots@%v", u.Version, len(u.Code), len(u.Storage), u.Block)
+}
+
+// NUCRuleStorageLayout describes where the rule contract keeps its participants
+// from a given block on: the storage slots of its state variables, as reported
+// by `solc --storage-layout` for the deployed code. Every participant set is an
+// address array, whose order the list getters follow, next to a mapping from
+// those addresses to the participant structs.
+type NUCRuleStorageLayout struct {
+	Block     *big.Int `json:"block"`     // Block the layout is read from
+	PocAddrs  uint64   `json:"pocAddrs"`  // Slot of the address[] of the PoC participants
+	Pocers    uint64   `json:"pocers"`    // Slot of the mapping(address => Pocer)
+	PowAddrs  uint64   `json:"powAddrs"`  // Slot of the address[] of the PoW participants
+	Powers    uint64   `json:"powers"`    // Slot of the mapping(address => Power)
+	PoolAddrs uint64   `json:"poolAddrs"` // Slot of the address[] of the pool participants
+	Poolers   uint64   `json:"poolers"`   // Slot of the mapping(address => Pooler)
+}
+
+// String implements the stringer interface.
+func (l NUCRuleStorageLayout) String() string {
+	return fmt.Sprintf("poc %d/%d pow %d/%d pool %d/%d@%v", l.PocAddrs, l.Pocers, l.PowAddrs, l.Powers, l.PoolAddrs, l.Poolers, l.Block)
+}
+
+// NUCDifficultyRules parameterises the NUC difficulty discounts from a given
+// block on. Every discount divides the difficulty, and they add up.
+type NUCDifficultyRules struct {
+	Block        *big.Int         `json:"block"`        // Block the rules activate at
+	TxCount      uint64           `json:"txCount"`      // Transactions the coinbase must have sent recently
+	TxBlocks     uint64           `json:"txBlocks"`     // Blocks the transactions are counted in, the parent included
+	TxDivisor    uint64           `json:"txDivisor"`    // Divisor of the tx count discount
+	BalanceTiers []NUCBalanceTier `json:"balanceTiers"` // Balance discounts, ordered by balance
+}
+
+// NUCBalanceTier is a balance difficulty discount, granted if the coinbase holds
+// more than the given number of whole NUC at the parent block. Only the highest
+// tier reached applies.
+type NUCBalanceTier struct {
+	Balance uint64 `json:"balance"` // Whole NUC to exceed
+	Divisor uint64 `json:"divisor"` // Divisor of the discount
+}
+
+// DefaultNUCDifficultyRules are the NUC difficulty discounts in force until the
+// chain config schedules others: halving the difficulty of a coinbase having
+// sent 10 transactions in the last 6 blocks, and again above 1000 NUC.
+var DefaultNUCDifficultyRules = NUCDifficultyRules{
+	Block:        common.Big0,
+	TxCount:      10,
+	TxBlocks:     6,
+	TxDivisor:    2,
+	BalanceTiers: []NUCBalanceTier{{Balance: 1000, Divisor: 2}},
+}
+
+// BalanceTier returns the highest tier reached by a balance of the given whole
+// NUC, or nil if none is.
+func (r *NUCDifficultyRules) BalanceTier(balance uint64) *NUCBalanceTier {
+	var tier *NUCBalanceTier
+	for i := range r.BalanceTiers {
+		if balance > r.BalanceTiers[i].Balance {
+			tier = &r.BalanceTiers[i]
+		}
+	}
+	return tier
+}
+
+// String implements the stringer interface.
+func (r NUCDifficultyRules) String() string {
+	return fmt.Sprintf("tx %d/%d:%d balance %v@%v", r.TxCount, r.TxBlocks, r.TxDivisor, r.BalanceTiers, r.Block)
+}
+
+// String implements the stringer interface.
+func (t NUCBalanceTier) String() string {
+	return fmt.Sprintf("%d:%d", t.Balance, t.Divisor)
+}
+
+// NUCRewardRule activates a reward algorithm version at a given block.
+type NUCRewardRule struct {
+	Block   *big.Int `json:"block"`   // Block the rule activates at
+	Version uint64   `json:"version"` // Reward algorithm version to switch to
+}
+
+// NUCFeePolicy splits the fee of every transaction, i.e. the gas used times the
+// gas price, from a given block on. Shares are percentages summing up to 100.
+type NUCFeePolicy struct {
+	Block        *big.Int       `json:"block"`        // Block the policy activates at
+	Coinbase     uint64         `json:"coinbase"`     // Share credited to the block's coinbase
+	Participants uint64         `json:"participants"` // Share split equally between the rewarded participants
+	Team         uint64         `json:"team"`         // Share credited to the team treasury
+	Burn         uint64         `json:"burn"`         // Share destroyed
+	Treasury     common.Address `json:"treasury"`     // Team treasury, also receiving the rounding dust
+}
+
+// NUCFeeSplit is a transaction fee split according to a fee policy.
+type NUCFeeSplit struct {
+	Coinbase     *big.Int
+	Participants *big.Int
+	Team         *big.Int
+	Burn         *big.Int
+}
+
+// Split divides a fee according to the policy. Rounding dust goes to the team,
+// so the shares always add up to the fee.
+func (p *NUCFeePolicy) Split(fee *big.Int) *NUCFeeSplit {
+	share := func(percent uint64) *big.Int {
+		r := new(big.Int).Mul(fee, new(big.Int).SetUint64(percent))
+		return r.Div(r, big.NewInt(100))
+	}
+	split := &NUCFeeSplit{
+		Coinbase:     share(p.Coinbase),
+		Participants: share(p.Participants),
+		Burn:         share(p.Burn),
+	}
+	split.Team = new(big.Int).Sub(fee, split.Coinbase)
+	split.Team.Sub(split.Team, split.Participants)
+	split.Team.Sub(split.Team, split.Burn)
+	return split
+}
+
+// String implements the stringer interface.
+func (p NUCFeePolicy) String() string {
+	return fmt.Sprintf("%d/%d/%d/%d@%v", p.Coinbase, p.Participants, p.Team, p.Burn, p.Block)
+}
+
+// String implements the stringer interface, returning the NUC rule details.
+func (c *NUCConfig) String() string {
+	if c == nil {
+		return "{}"
+	}
+	return fmt.Sprintf("{RewardRules: %v CoinbaseTxs: %v TxCountDiscount: %v BalanceDiscount: %v ContractKindFailure: %v DataContractGas: %v SystemCall: %v SupplyInvariant: %v FeePolicies: %v RuleUpgrades: %v DifficultyRules: %v RuleStorageLayouts: %v}",
+		c.RewardRules, c.CoinbaseTxsBlock, c.TxCountDiscountBlock, c.BalanceDiscountBlock, c.ContractKindFailureBlock, c.DataContractGasBlock, c.SystemCallBlock, c.SupplyInvariantBlock, c.FeePolicies, c.RuleUpgrades, c.DifficultyRules, c.RuleStorageLayouts)
+}
+
+// String implements the stringer interface.
+func (r NUCRewardRule) String() string {
+	return fmt.Sprintf("v%d@%v", r.Version, r.Block)
+}
+
+// NUCRewardVersion returns the reward algorithm version in force at block num.
+func (c *ChainConfig) NUCRewardVersion(num *big.Int) uint64 {
+	rules := c.NUC.rewardRules()
+	if len(rules) == 0 {
+		return NUCRewardLatest
+	}
+	version := uint64(0)
+	for _, rule := range rules {
+		if !isForked(rule.Block, num) {
+			break
+		}
+		version = rule.Version
+	}
+	return version
+}
+
+// NUCFeePolicy returns the fee policy in force at block num, or nil if the legacy
+// fee split still is.
+func (c *ChainConfig) NUCFeePolicy(num *big.Int) *NUCFeePolicy {
+	var policy *NUCFeePolicy
+	for i, p := range c.NUC.feePolicies() {
+		if !isForked(p.Block, num) {
+			break
+		}
+		policy = &c.NUC.FeePolicies[i]
+	}
+	return policy
+}
+
+// NUCRuleVersion returns the rule contract ABI version in force at block num.
+func (c *ChainConfig) NUCRuleVersion(num *big.Int) uint64 {
+	version := uint64(NUCRuleV1)
+	for _, u := range c.NUC.ruleUpgrades() {
+		if !isForked(u.Block, num) {
+			break
+		}
+		version = u.Version
+	}
+	return version
+}
+
+// NUCRuleUpgrade returns the rule contract upgrade to apply at block num, or nil
+// if none is scheduled for it.
+func (c *ChainConfig) NUCRuleUpgrade(num *big.Int) *NUCRuleUpgrade {
+	upgrades := c.NUC.ruleUpgrades()
+	for i := range upgrades {
+		if upgrades[i].Block != nil && upgrades[i].Block.Cmp(num) == 0 {
+			return &upgrades[i]
+		}
+	}
+	return nil
+}
+
+// NUCDifficultyRules returns the NUC difficulty discount parameters in force at
+// block num.
+func (c *ChainConfig) NUCDifficultyRules(num *big.Int) *NUCDifficultyRules {
+	rules := &DefaultNUCDifficultyRules
+	for i, r := range c.NUC.difficultyRules() {
+		if !isForked(r.Block, num) {
+			break
+		}
+		rules = &c.NUC.DifficultyRules[i]
+	}
+	return rules
+}
+
+// NUCRuleStorageLayout returns the rule contract storage layout in force at block
+// num, or nil if the participants are still read through the list getters.
+func (c *ChainConfig) NUCRuleStorageLayout(num *big.Int) *NUCRuleStorageLayout {
+	var layout *NUCRuleStorageLayout
+	for i, l := range c.NUC.ruleStorageLayouts() {
+		if !isForked(l.Block, num) {
+			break
+		}
+		layout = &c.NUC.RuleStorageLayouts[i]
+	}
+	return layout
+}
+
+// IsNUCCoinbaseTxs returns whether num is either equal to the block the header's
+// CoinbaseTxs are RLP encoded from or greater.
+func (c *ChainConfig) IsNUCCoinbaseTxs(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.CoinbaseTxsBlock, num)
+}
+
+// IsNUCTxCountDiscount returns whether num is either equal to the block the tx
+// count difficulty discount is enforced from or greater.
+func (c *ChainConfig) IsNUCTxCountDiscount(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.TxCountDiscountBlock, num)
+}
+
+// IsNUCBalanceDiscount returns whether num is either equal to the block the
+// balance difficulty discount is enforced from or greater.
+func (c *ChainConfig) IsNUCBalanceDiscount(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.BalanceDiscountBlock, num)
+}
+
+// IsNUCContractKindFailure returns whether num is either equal to the block the
+// contract creations rejected by the contract kind registry fail from or greater.
+func (c *ChainConfig) IsNUCContractKindFailure(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.ContractKindFailureBlock, num)
+}
+
+// IsNUCDataContractGas returns whether num is either equal to the block the IPFS
+// data contract creations are charged for storing the data from or greater.
+func (c *ChainConfig) IsNUCDataContractGas(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.DataContractGasBlock, num)
+}
+
+// IsNUCSystemCall returns whether num is either equal to the block the rule
+// contract is read through side-effect-free system calls from or greater.
+func (c *ChainConfig) IsNUCSystemCall(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.SystemCallBlock, num)
+}
+
+// IsNUCSupplyInvariant returns whether num is either equal to the block the
+// supply invariant violations are rejected from or greater.
+func (c *ChainConfig) IsNUCSupplyInvariant(num *big.Int) bool {
+	return c.NUC != nil && isForked(c.NUC.SupplyInvariantBlock, num)
+}
+
+// rewardRules returns the scheduled reward rules, tolerating a nil config.
+func (c *NUCConfig) rewardRules() []NUCRewardRule {
+	if c == nil {
+		return nil
+	}
+	return c.RewardRules
+}
+
+// feePolicies returns the scheduled fee policies, tolerating a nil config.
+func (c *NUCConfig) feePolicies() []NUCFeePolicy {
+	if c == nil {
+		return nil
+	}
+	return c.FeePolicies
+}
+
+// ruleUpgrades returns the scheduled rule contract upgrades, tolerating a nil
+// config.
+func (c *NUCConfig) ruleUpgrades() []NUCRuleUpgrade {
+	if c == nil {
+		return nil
+	}
+	return c.RuleUpgrades
+}
+
+// difficultyRules returns the scheduled difficulty rules, tolerating a nil config.
+func (c *NUCConfig) difficultyRules() []NUCDifficultyRules {
+	if c == nil {
+		return nil
+	}
+	return c.DifficultyRules
+}
+
+// ruleStorageLayouts returns the scheduled rule contract storage layouts,
+// tolerating a nil config.
+func (c *NUCConfig) ruleStorageLayouts() []NUCRuleStorageLayout {
+	if c == nil {
+		return nil
+	}
+	return c.RuleStorageLayouts
+}
+
+// coinbaseTxsBlock returns the CoinbaseTxs encoding fork block, tolerating a nil
+// config.
+func (c *NUCConfig) coinbaseTxsBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.CoinbaseTxsBlock
+}
+
+func (c *NUCConfig) txCountDiscountBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.TxCountDiscountBlock
+}
+
+func (c *NUCConfig) balanceDiscountBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.BalanceDiscountBlock
+}
+
+func (c *NUCConfig) contractKindFailureBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.ContractKindFailureBlock
+}
+
+// dataContractGasBlock returns the data contract gas fork block, tolerating a nil
+// config.
+func (c *NUCConfig) dataContractGasBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.DataContractGasBlock
+}
+
+// systemCallBlock returns the system call fork block, tolerating a nil config.
+func (c *NUCConfig) systemCallBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.SystemCallBlock
+}
+
+// supplyInvariantBlock returns the supply invariant fork block, tolerating a nil
+// config.
+func (c *NUCConfig) supplyInvariantBlock() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.SupplyInvariantBlock
+}
+
+// checkRewardRules verifies that the reward rules are ordered by activation
+// block, start at genesis and only reference known algorithm versions.
+func (c *NUCConfig) checkRewardRules() error {
+	rules := c.rewardRules()
+	for i, rule := range rules {
+		if rule.Block == nil {
+			return fmt.Errorf("nuc reward rule #%d has no activation block", i)
+		}
+		if rule.Version < NUCRewardV1 || rule.Version > NUCRewardLatest {
+			return fmt.Errorf("nuc reward rule #%d has unsupported version %d", i, rule.Version)
+		}
+		if i == 0 && rule.Block.Sign() != 0 {
+			return fmt.Errorf("nuc reward rules must start at genesis, first rule at %v", rule.Block)
+		}
+		if i > 0 && rules[i-1].Block.Cmp(rule.Block) >= 0 {
+			return fmt.Errorf("unsupported nuc reward rule ordering: #%d at %v, but #%d at %v",
+				i-1, rules[i-1].Block, i, rule.Block)
+		}
+	}
+	return nil
+}
+
+// checkFeePolicies verifies that the fee policies are ordered by activation
+// block, split the whole fee and have a treasury. As only the V2+ reward
+// algorithms share out the pooled participants' fees, no V1 reward rule may be
+// in force once the first fee policy is.
+func (c *NUCConfig) checkFeePolicies() error {
+	policies := c.feePolicies()
+	if len(policies) > 0 && policies[0].Block != nil {
+		rules := c.rewardRules()
+		for i, rule := range rules {
+			if rule.Version != NUCRewardV1 {
+				continue
+			}
+			if i == len(rules)-1 || (rules[i+1].Block != nil && rules[i+1].Block.Cmp(policies[0].Block) > 0) {
+				return fmt.Errorf("nuc fee policy #0 at %v is in force along with the v1 reward rule #%d at %v", policies[0].Block, i, rule.Block)
+			}
+		}
+	}
+	for i, policy := range policies {
+		if policy.Block == nil {
+			return fmt.Errorf("nuc fee policy #%d has no activation block", i)
+		}
+		if total := policy.Coinbase + policy.Participants + policy.Team + policy.Burn; total != 100 {
+			return fmt.Errorf("nuc fee policy #%d splits %d%% of the fee, want 100%%", i, total)
+		}
+		if policy.Treasury == (common.Address{}) {
+			return fmt.Errorf("nuc fee policy #%d has no treasury", i)
+		}
+		if i > 0 && policies[i-1].Block.Cmp(policy.Block) >= 0 {
+			return fmt.Errorf("unsupported nuc fee policy ordering: #%d at %v, but #%d at %v",
+				i-1, policies[i-1].Block, i, policy.Block)
+		}
+	}
+	return nil
+}
+
+// checkDifficultyRules verifies that the difficulty rules are ordered by
+// activation block, count transactions in at least one block and have balance
+// tiers ordered by balance. Divisors must be positive, 1 disabling a discount.
+func (c *NUCConfig) checkDifficultyRules() error {
+	rules := c.difficultyRules()
+	for i, r := range rules {
+		if r.Block == nil {
+			return fmt.Errorf("nuc difficulty rules #%d have no activation block", i)
+		}
+		if r.TxBlocks == 0 {
+			return fmt.Errorf("nuc difficulty rules #%d count transactions in no block", i)
+		}
+		if r.TxDivisor == 0 {
+			return fmt.Errorf("nuc difficulty rules #%d have a zero tx count divisor", i)
+		}
+		for j, tier := range r.BalanceTiers {
+			if tier.Divisor == 0 {
+				return fmt.Errorf("nuc difficulty rules #%d have a zero divisor in balance tier #%d", i, j)
+			}
+			if j > 0 && r.BalanceTiers[j-1].Balance >= tier.Balance {
+				return fmt.Errorf("unsupported nuc balance tier ordering in difficulty rules #%d: #%d above %d NUC, but #%d above %d NUC",
+					i, j-1, r.BalanceTiers[j-1].Balance, j, tier.Balance)
+			}
+		}
+		if i > 0 && rules[i-1].Block.Cmp(r.Block) >= 0 {
+			return fmt.Errorf("unsupported nuc difficulty rules ordering: #%d at %v, but #%d at %v",
+				i-1, rules[i-1].Block, i, r.Block)
+		}
+	}
+	return nil
+}
+
+// checkRuleUpgrades verifies that the rule contract upgrades are ordered by
+// block, happen after genesis, only reference known ABI versions and install
+// deployable code.
+func (c *NUCConfig) checkRuleUpgrades() error {
+	upgrades := c.ruleUpgrades()
+	for i, u := range upgrades {
+		if u.Block == nil || u.Block.Sign() <= 0 {
+			return fmt.Errorf("nuc rule upgrade #%d has no activation block after genesis", i)
+		}
+		if u.Version < NUCRuleV1 || u.Version > NUCRuleLatest {
+			return fmt.Errorf("nuc rule upgrade #%d has unsupported version %d", i, u.Version)
+		}
+		if len(u.Code) > MaxCodeSize {
+			return fmt.Errorf("nuc rule upgrade #%d code exceeds %d bytes", i, MaxCodeSize)
+		}
+		if i > 0 && upgrades[i-1].Block.Cmp(u.Block) >= 0 {
+			return fmt.Errorf("unsupported nuc rule upgrade ordering: #%d at %v, but #%d at %v",
+				i-1, upgrades[i-1].Block, i, u.Block)
+		}
+	}
+	return nil
+}
+
+// checkRuleStorageLayouts verifies that the rule contract storage layouts are
+// ordered by block and keep every participant state variable in its own slot.
+func (c *NUCConfig) checkRuleStorageLayouts() error {
+	layouts := c.ruleStorageLayouts()
+	for i, l := range layouts {
+		if l.Block == nil {
+			return fmt.Errorf("nuc rule storage layout #%d has no activation block", i)
+		}
+		slots := map[uint64]bool{l.PocAddrs: true, l.Pocers: true, l.PowAddrs: true, l.Powers: true, l.PoolAddrs: true, l.Poolers: true}
+		if len(slots) != 6 {
+			return fmt.Errorf("nuc rule storage layout #%d shares slots between state variables", i)
+		}
+		if i > 0 && layouts[i-1].Block.Cmp(l.Block) >= 0 {
+			return fmt.Errorf("unsupported nuc rule storage layout ordering: #%d at %v, but #%d at %v",
+				i-1, layouts[i-1].Block, i, l.Block)
+		}
+	}
+	return nil
+}
+
+// checkCompatible reports the first NUC rule change that would alter the
+// validation of an already imported block.
+func (c *NUCConfig) checkCompatible(newcfg *NUCConfig, head *big.Int) *ConfigCompatError {
+	if s1, s2 := c.coinbaseTxsBlock(), newcfg.coinbaseTxsBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC coinbase txs block", s1, s2)
+	}
+	if s1, s2 := c.txCountDiscountBlock(), newcfg.txCountDiscountBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC tx count discount block", s1, s2)
+	}
+	if s1, s2 := c.balanceDiscountBlock(), newcfg.balanceDiscountBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC balance discount block", s1, s2)
+	}
+	if s1, s2 := c.contractKindFailureBlock(), newcfg.contractKindFailureBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC contract kind failure block", s1, s2)
+	}
+	if s1, s2 := c.dataContractGasBlock(), newcfg.dataContractGasBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC data contract gas block", s1, s2)
+	}
+	if s1, s2 := c.systemCallBlock(), newcfg.systemCallBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC system call block", s1, s2)
+	}
+	if s1, s2 := c.supplyInvariantBlock(), newcfg.supplyInvariantBlock(); isForkIncompatible(s1, s2, head) {
+		return newCompatError("NUC supply invariant block", s1, s2)
+	}
+	stored, updated := c.rewardRules(), newcfg.rewardRules()
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		var (
+			s1, s2 *big.Int
+			v1, v2 uint64
+		)
+		if i < len(stored) {
+			s1, v1 = stored[i].Block, stored[i].Version
+		}
+		if i < len(updated) {
+			s2, v2 = updated[i].Block, updated[i].Version
+		}
+		if isForkIncompatible(s1, s2, head) || (isForked(s1, head) && v1 != v2) {
+			return newCompatError(fmt.Sprintf("NUC reward rule #%d", i), s1, s2)
+		}
+	}
+	storedFees, updatedFees := c.feePolicies(), newcfg.feePolicies()
+	for i := 0; i < len(storedFees) || i < len(updatedFees); i++ {
+		var (
+			s1, s2 *big.Int
+			p1, p2 NUCFeePolicy
+		)
+		if i < len(storedFees) {
+			p1 = storedFees[i]
+			s1, p1.Block = p1.Block, nil
+		}
+		if i < len(updatedFees) {
+			p2 = updatedFees[i]
+			s2, p2.Block = p2.Block, nil
+		}
+		if isForkIncompatible(s1, s2, head) || (isForked(s1, head) && p1 != p2) {
+			return newCompatError(fmt.Sprintf("NUC fee policy #%d", i), s1, s2)
+		}
+	}
+	storedDiffs, updatedDiffs := c.difficultyRules(), newcfg.difficultyRules()
+	for i := 0; i < len(storedDiffs) || i < len(updatedDiffs); i++ {
+		var (
+			s1, s2 *big.Int
+			r1, r2 NUCDifficultyRules
+		)
+		if i < len(storedDiffs) {
+			r1 = storedDiffs[i]
+			s1, r1.Block = r1.Block, nil
+		}
+		if i < len(updatedDiffs) {
+			r2 = updatedDiffs[i]
+			s2, r2.Block = r2.Block, nil
+		}
+		if isForkIncompatible(s1, s2, head) || (isForked(s1, head) && !reflect.DeepEqual(r1, r2)) {
+			return newCompatError(fmt.Sprintf("NUC difficulty rules #%d", i), s1, s2)
+		}
+	}
+	storedUpgrades, updatedUpgrades := c.ruleUpgrades(), newcfg.ruleUpgrades()
+	for i := 0; i < len(storedUpgrades) || i < len(updatedUpgrades); i++ {
+		var (
+			s1, s2 *big.Int
+			u1, u2 NUCRuleUpgrade
+		)
+		if i < len(storedUpgrades) {
+			u1 = storedUpgrades[i]
+			s1, u1.Block = u1.Block, nil
+		}
+		if i < len(updatedUpgrades) {
+			u2 = updatedUpgrades[i]
+			s2, u2.Block = u2.Block, nil
+		}
+		if isForkIncompatible(s1, s2, head) || (isForked(s1, head) && !reflect.DeepEqual(u1, u2)) {
+			return newCompatError(fmt.Sprintf("NUC rule upgrade #%d", i), s1, s2)
+		}
+	}
+	storedLayouts, updatedLayouts := c.ruleStorageLayouts(), newcfg.ruleStorageLayouts()
+	for i := 0; i < len(storedLayouts) || i < len(updatedLayouts); i++ {
+		var (
+			s1, s2 *big.Int
+			l1, l2 NUCRuleStorageLayout
+		)
+		if i < len(storedLayouts) {
+			l1 = storedLayouts[i]
+			s1, l1.Block = l1.Block, nil
+		}
+		if i < len(updatedLayouts) {
+			l2 = updatedLayouts[i]
+			s2, l2.Block = l2.Block, nil
+		}
+		if isForkIncompatible(s1, s2, head) || (isForked(s1, head) && l1 != l2) {
+			return newCompatError(fmt.Sprintf("NUC rule storage layout #%d", i), s1, s2)
+		}
+	}
+	return nil
+}
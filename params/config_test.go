@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -79,3 +81,381 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestNUCRewardVersion(t *testing.T) {
+	config := &ChainConfig{NUC: &NUCConfig{RewardRules: []NUCRewardRule{
+		{Block: big.NewInt(0), Version: NUCRewardV1},
+		{Block: big.NewInt(100), Version: NUCRewardV3},
+		{Block: big.NewInt(200), Version: NUCRewardV4},
+	}}}
+	tests := []struct {
+		config *ChainConfig
+		number int64
+		want   uint64
+	}{
+		{&ChainConfig{}, 0, NUCRewardLatest},
+		{&ChainConfig{}, 1000, NUCRewardLatest},
+		{config, 0, NUCRewardV1},
+		{config, 99, NUCRewardV1},
+		{config, 100, NUCRewardV3},
+		{config, 199, NUCRewardV3},
+		{config, 200, NUCRewardV4},
+		{config, 100000, NUCRewardV4},
+	}
+	for i, tt := range tests {
+		if have := tt.config.NUCRewardVersion(big.NewInt(tt.number)); have != tt.want {
+			t.Errorf("test %d: reward version mismatch at block %d: have %d, want %d", i, tt.number, have, tt.want)
+		}
+	}
+}
+
+func TestNUCRewardRulesValidation(t *testing.T) {
+	tests := []struct {
+		rules []NUCRewardRule
+		valid bool
+	}{
+		{nil, true},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV2}}, true},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV2}, {Block: big.NewInt(10), Version: NUCRewardV4}}, true},
+		{[]NUCRewardRule{{Block: big.NewInt(5), Version: NUCRewardV2}}, false},
+		{[]NUCRewardRule{{Block: nil, Version: NUCRewardV2}}, false},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: 0}}, false},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardLatest + 1}}, false},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV2}, {Block: big.NewInt(0), Version: NUCRewardV4}}, false},
+	}
+	for i, tt := range tests {
+		config := &ChainConfig{NUC: &NUCConfig{RewardRules: tt.rules}}
+		if err := config.CheckConfigForkOrder(); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: err %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
+
+func TestNUCRewardRulesCompatible(t *testing.T) {
+	stored := &ChainConfig{NUC: &NUCConfig{RewardRules: []NUCRewardRule{
+		{Block: big.NewInt(0), Version: NUCRewardV3},
+		{Block: big.NewInt(100), Version: NUCRewardV4},
+	}}}
+	rescheduled := &ChainConfig{NUC: &NUCConfig{RewardRules: []NUCRewardRule{
+		{Block: big.NewInt(0), Version: NUCRewardV3},
+		{Block: big.NewInt(150), Version: NUCRewardV4},
+	}}}
+	if err := stored.CheckCompatible(rescheduled, 50); err != nil {
+		t.Errorf("unexpected error before the rule activated: %v", err)
+	}
+	err := stored.CheckCompatible(rescheduled, 120)
+	want := &ConfigCompatError{What: "NUC reward rule #1", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(150), RewindTo: 99}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+	replaced := &ChainConfig{NUC: &NUCConfig{RewardRules: []NUCRewardRule{
+		{Block: big.NewInt(0), Version: NUCRewardV2},
+		{Block: big.NewInt(100), Version: NUCRewardV4},
+	}}}
+	if err := stored.CheckCompatible(replaced, 1); err == nil {
+		t.Errorf("expected error when replacing an active reward version")
+	}
+}
+
+func TestNUCContractKindFailureCompatible(t *testing.T) {
+	stored := &ChainConfig{NUC: &NUCConfig{ContractKindFailureBlock: big.NewInt(100)}}
+	if !stored.IsNUCContractKindFailure(big.NewInt(100)) || stored.IsNUCContractKindFailure(big.NewInt(99)) {
+		t.Errorf("contract kind failure activation mismatch")
+	}
+	rescheduled := &ChainConfig{NUC: &NUCConfig{ContractKindFailureBlock: big.NewInt(150)}}
+	if err := stored.CheckCompatible(rescheduled, 50); err != nil {
+		t.Errorf("unexpected error before the fork activated: %v", err)
+	}
+	err := stored.CheckCompatible(rescheduled, 120)
+	want := &ConfigCompatError{What: "NUC contract kind failure block", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(150), RewindTo: 99}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}
+
+func TestNUCSupplyInvariantCompatible(t *testing.T) {
+	stored := &ChainConfig{NUC: &NUCConfig{SupplyInvariantBlock: big.NewInt(100)}}
+	if !stored.IsNUCSupplyInvariant(big.NewInt(100)) || stored.IsNUCSupplyInvariant(big.NewInt(99)) || (&ChainConfig{}).IsNUCSupplyInvariant(big.NewInt(100)) {
+		t.Errorf("supply invariant activation mismatch")
+	}
+	rescheduled := &ChainConfig{NUC: &NUCConfig{SupplyInvariantBlock: big.NewInt(150)}}
+	if err := stored.CheckCompatible(rescheduled, 50); err != nil {
+		t.Errorf("unexpected error before the fork activated: %v", err)
+	}
+	err := stored.CheckCompatible(rescheduled, 120)
+	want := &ConfigCompatError{What: "NUC supply invariant block", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(150), RewindTo: 99}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}
+
+func TestNUCFeePolicySplit(t *testing.T) {
+	policies := []NUCFeePolicy{
+		{Coinbase: 30, Participants: 65, Team: 5},
+		{Coinbase: 20, Participants: 60, Team: 10, Burn: 10},
+		{Coinbase: 33, Participants: 33, Team: 1, Burn: 33},
+		{Burn: 100},
+	}
+	for i, policy := range policies {
+		for _, fee := range []int64{0, 1, 99, 101, 21000 * 1e9, 1234567890123} {
+			split := policy.Split(big.NewInt(fee))
+			total := new(big.Int).Add(split.Coinbase, split.Participants)
+			total.Add(total, split.Team)
+			total.Add(total, split.Burn)
+			if total.Int64() != fee {
+				t.Errorf("policy %d, fee %d: split doesn't add up: have %v", i, fee, total)
+			}
+			if want := fee * int64(policy.Coinbase) / 100; split.Coinbase.Int64() != want {
+				t.Errorf("policy %d, fee %d: coinbase share mismatch: have %v, want %d", i, fee, split.Coinbase, want)
+			}
+			if dust := split.Team.Int64() - fee*int64(policy.Team)/100; dust < 0 || dust > 3 {
+				t.Errorf("policy %d, fee %d: team dust out of bounds: %d", i, fee, dust)
+			}
+		}
+	}
+}
+
+func TestNUCFeePolicies(t *testing.T) {
+	treasury := common.Address{0xee}
+	config := &ChainConfig{NUC: &NUCConfig{FeePolicies: []NUCFeePolicy{
+		{Block: big.NewInt(100), Coinbase: 30, Participants: 65, Team: 5, Treasury: treasury},
+		{Block: big.NewInt(200), Coinbase: 20, Participants: 60, Team: 10, Burn: 10, Treasury: treasury},
+	}}}
+	for _, tt := range []struct {
+		number int64
+		want   *NUCFeePolicy
+	}{
+		{0, nil}, {99, nil}, {100, &config.NUC.FeePolicies[0]}, {199, &config.NUC.FeePolicies[0]}, {200, &config.NUC.FeePolicies[1]},
+	} {
+		if have := config.NUCFeePolicy(big.NewInt(tt.number)); have != tt.want {
+			t.Errorf("block %d: fee policy mismatch: have %v, want %v", tt.number, have, tt.want)
+		}
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid fee policies rejected: %v", err)
+	}
+	invalid := [][]NUCFeePolicy{
+		{{Coinbase: 100, Treasury: treasury}},
+		{{Block: big.NewInt(0), Coinbase: 30, Participants: 60, Treasury: treasury}},
+		{{Block: big.NewInt(0), Coinbase: 100}},
+		{{Block: big.NewInt(5), Coinbase: 100, Treasury: treasury}, {Block: big.NewInt(5), Burn: 100, Treasury: treasury}},
+	}
+	for i, policies := range invalid {
+		if err := (&ChainConfig{NUC: &NUCConfig{FeePolicies: policies}}).CheckConfigForkOrder(); err == nil {
+			t.Errorf("invalid fee policies %d accepted", i)
+		}
+	}
+	// Changing an active policy is incompatible, a future one isn't
+	updated := &ChainConfig{NUC: &NUCConfig{FeePolicies: []NUCFeePolicy{
+		config.NUC.FeePolicies[0],
+		{Block: big.NewInt(200), Coinbase: 20, Participants: 60, Team: 20, Treasury: treasury},
+	}}}
+	if err := config.CheckCompatible(updated, 150); err != nil {
+		t.Errorf("unexpected error before the policy activated: %v", err)
+	}
+	err := config.CheckCompatible(updated, 250)
+	want := &ConfigCompatError{What: "NUC fee policy #1", StoredConfig: big.NewInt(200), NewConfig: big.NewInt(200), RewindTo: 199}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}
+
+// Tests that fee policies are rejected while the V1 reward algorithm, which
+// never shares out the pooled participants' fees, is in force.
+func TestNUCFeePolicyRewardRules(t *testing.T) {
+	policies := []NUCFeePolicy{{Block: big.NewInt(100), Coinbase: 30, Participants: 65, Team: 5, Treasury: common.Address{0xee}}}
+	for i, tt := range []struct {
+		rules []NUCRewardRule
+		valid bool
+	}{
+		{nil, true},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV2}}, true},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV1}, {Block: big.NewInt(100), Version: NUCRewardV4}}, true},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV1}}, false},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV1}, {Block: big.NewInt(101), Version: NUCRewardV4}}, false},
+		{[]NUCRewardRule{{Block: big.NewInt(0), Version: NUCRewardV4}, {Block: big.NewInt(200), Version: NUCRewardV1}}, false},
+	} {
+		err := (&ChainConfig{NUC: &NUCConfig{RewardRules: tt.rules, FeePolicies: policies}}).CheckConfigForkOrder()
+		if tt.valid && err != nil {
+			t.Errorf("test %d: valid config rejected: %v", i, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("test %d: invalid config accepted", i)
+		}
+	}
+}
+
+func TestNUCDifficultyRules(t *testing.T) {
+	tiers := []NUCBalanceTier{{Balance: 1000, Divisor: 2}, {Balance: 10000, Divisor: 4}}
+	config := &ChainConfig{NUC: &NUCConfig{DifficultyRules: []NUCDifficultyRules{
+		{Block: big.NewInt(100), TxCount: 10, TxBlocks: 6, TxDivisor: 2, BalanceTiers: tiers},
+		{Block: big.NewInt(200), TxCount: 20, TxBlocks: 10, TxDivisor: 2},
+	}}}
+	for _, tt := range []struct {
+		config *ChainConfig
+		number int64
+		want   *NUCDifficultyRules
+	}{
+		{&ChainConfig{}, 0, &DefaultNUCDifficultyRules},
+		{config, 99, &DefaultNUCDifficultyRules},
+		{config, 100, &config.NUC.DifficultyRules[0]},
+		{config, 199, &config.NUC.DifficultyRules[0]},
+		{config, 200, &config.NUC.DifficultyRules[1]},
+	} {
+		if have := tt.config.NUCDifficultyRules(big.NewInt(tt.number)); have != tt.want {
+			t.Errorf("block %d: difficulty rules mismatch: have %v, want %v", tt.number, have, tt.want)
+		}
+	}
+	// Only the highest tier reached applies, the tier balance must be exceeded
+	for _, tt := range []struct {
+		balance uint64
+		divisor uint64 // 0 if no tier is reached
+	}{
+		{0, 0}, {1000, 0}, {1001, 2}, {10000, 2}, {10001, 4}, {1 << 60, 4},
+	} {
+		var have uint64
+		if tier := config.NUC.DifficultyRules[0].BalanceTier(tt.balance); tier != nil {
+			have = tier.Divisor
+		}
+		if have != tt.divisor {
+			t.Errorf("balance %d: tier divisor mismatch: have %d, want %d", tt.balance, have, tt.divisor)
+		}
+	}
+	if tier := config.NUC.DifficultyRules[1].BalanceTier(1 << 60); tier != nil {
+		t.Errorf("tier reached without balance tiers: %v", tier)
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid difficulty rules rejected: %v", err)
+	}
+	invalid := [][]NUCDifficultyRules{
+		{{TxBlocks: 6, TxDivisor: 2}},
+		{{Block: big.NewInt(0), TxBlocks: 0, TxDivisor: 2}},
+		{{Block: big.NewInt(0), TxBlocks: 6, TxDivisor: 0}},
+		{{Block: big.NewInt(0), TxBlocks: 6, TxDivisor: 2, BalanceTiers: []NUCBalanceTier{{Balance: 1000, Divisor: 0}}}},
+		{{Block: big.NewInt(0), TxBlocks: 6, TxDivisor: 2, BalanceTiers: []NUCBalanceTier{tiers[1], tiers[0]}}},
+		{{Block: big.NewInt(5), TxBlocks: 6, TxDivisor: 2}, {Block: big.NewInt(5), TxBlocks: 6, TxDivisor: 2}},
+	}
+	for i, rules := range invalid {
+		if err := (&ChainConfig{NUC: &NUCConfig{DifficultyRules: rules}}).CheckConfigForkOrder(); err == nil {
+			t.Errorf("invalid difficulty rules %d accepted", i)
+		}
+	}
+	// Changing active rules is incompatible, future ones aren't
+	updated := &ChainConfig{NUC: &NUCConfig{DifficultyRules: []NUCDifficultyRules{
+		{Block: big.NewInt(100), TxCount: 10, TxBlocks: 6, TxDivisor: 2, BalanceTiers: tiers[:1]},
+		config.NUC.DifficultyRules[1],
+	}}}
+	if err := config.CheckCompatible(updated, 50); err != nil {
+		t.Errorf("unexpected error before the rules activated: %v", err)
+	}
+	err := config.CheckCompatible(updated, 150)
+	want := &ConfigCompatError{What: "NUC difficulty rules #0", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(100), RewindTo: 99}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}
+
+func TestNUCRuleUpgrades(t *testing.T) {
+	config := &ChainConfig{NUC: &NUCConfig{RuleUpgrades: []NUCRuleUpgrade{
+		{Block: big.NewInt(100), Version: NUCRuleV1, Code: []byte{0x00}},
+		{Block: big.NewInt(200), Version: NUCRuleV1, Storage: map[common.Hash]common.Hash{{0x01}: {0x02}}},
+	}}}
+	for _, tt := range []struct {
+		number  int64
+		upgrade *NUCRuleUpgrade
+	}{
+		{0, nil},
+		{99, nil},
+		{100, &config.NUC.RuleUpgrades[0]},
+		{101, nil},
+		{200, &config.NUC.RuleUpgrades[1]},
+		{201, nil},
+	} {
+		if have := config.NUCRuleUpgrade(big.NewInt(tt.number)); have != tt.upgrade {
+			t.Errorf("block %d: upgrade mismatch: have %v, want %v", tt.number, have, tt.upgrade)
+		}
+		if have := config.NUCRuleVersion(big.NewInt(tt.number)); have != NUCRuleV1 {
+			t.Errorf("block %d: version mismatch: have %d, want %d", tt.number, have, NUCRuleV1)
+		}
+	}
+	if have := (&ChainConfig{}).NUCRuleVersion(big.NewInt(0)); have != NUCRuleV1 {
+		t.Errorf("default version mismatch: have %d, want %d", have, NUCRuleV1)
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid rule upgrades rejected: %v", err)
+	}
+	invalid := [][]NUCRuleUpgrade{
+		{{Version: NUCRuleV1}},
+		{{Block: big.NewInt(0), Version: NUCRuleV1}},
+		{{Block: big.NewInt(5), Version: 0}},
+		{{Block: big.NewInt(5), Version: NUCRuleLatest + 1}},
+		{{Block: big.NewInt(5), Version: NUCRuleV1, Code: make([]byte, MaxCodeSize+1)}},
+		{{Block: big.NewInt(5), Version: NUCRuleV1}, {Block: big.NewInt(5), Version: NUCRuleV1}},
+	}
+	for i, upgrades := range invalid {
+		if err := (&ChainConfig{NUC: &NUCConfig{RuleUpgrades: upgrades}}).CheckConfigForkOrder(); err == nil {
+			t.Errorf("invalid rule upgrades %d accepted", i)
+		}
+	}
+	// Changing applied upgrades is incompatible, future ones aren't
+	updated := &ChainConfig{NUC: &NUCConfig{RuleUpgrades: []NUCRuleUpgrade{
+		config.NUC.RuleUpgrades[0],
+		{Block: big.NewInt(200), Version: NUCRuleV1, Storage: map[common.Hash]common.Hash{{0x01}: {0x03}}},
+	}}}
+	if err := config.CheckCompatible(updated, 150); err != nil {
+		t.Errorf("unexpected error before the upgrade applied: %v", err)
+	}
+	err := config.CheckCompatible(updated, 250)
+	want := &ConfigCompatError{What: "NUC rule upgrade #1", StoredConfig: big.NewInt(200), NewConfig: big.NewInt(200), RewindTo: 199}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}
+
+func TestNUCRuleStorageLayouts(t *testing.T) {
+	config := &ChainConfig{NUC: &NUCConfig{RuleStorageLayouts: []NUCRuleStorageLayout{
+		{Block: big.NewInt(100), PocAddrs: 0, Pocers: 1, PowAddrs: 2, Powers: 3, PoolAddrs: 4, Poolers: 5},
+		{Block: big.NewInt(200), PocAddrs: 6, Pocers: 7, PowAddrs: 8, Powers: 9, PoolAddrs: 10, Poolers: 11},
+	}}}
+	for _, tt := range []struct {
+		config *ChainConfig
+		number int64
+		want   *NUCRuleStorageLayout
+	}{
+		{&ChainConfig{}, 0, nil},
+		{config, 99, nil},
+		{config, 100, &config.NUC.RuleStorageLayouts[0]},
+		{config, 199, &config.NUC.RuleStorageLayouts[0]},
+		{config, 200, &config.NUC.RuleStorageLayouts[1]},
+	} {
+		if have := tt.config.NUCRuleStorageLayout(big.NewInt(tt.number)); have != tt.want {
+			t.Errorf("block %d: layout mismatch: have %v, want %v", tt.number, have, tt.want)
+		}
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid rule storage layouts rejected: %v", err)
+	}
+	invalid := [][]NUCRuleStorageLayout{
+		{{PocAddrs: 0, Pocers: 1, PowAddrs: 2, Powers: 3, PoolAddrs: 4, Poolers: 5}},
+		{{Block: big.NewInt(0), PocAddrs: 0, Pocers: 1, PowAddrs: 2, Powers: 3, PoolAddrs: 4, Poolers: 0}},
+		{config.NUC.RuleStorageLayouts[1], config.NUC.RuleStorageLayouts[0]},
+	}
+	for i, layouts := range invalid {
+		if err := (&ChainConfig{NUC: &NUCConfig{RuleStorageLayouts: layouts}}).CheckConfigForkOrder(); err == nil {
+			t.Errorf("invalid rule storage layouts %d accepted", i)
+		}
+	}
+	// Changing active layouts is incompatible, future ones aren't
+	updated := &ChainConfig{NUC: &NUCConfig{RuleStorageLayouts: []NUCRuleStorageLayout{
+		{Block: big.NewInt(100), PocAddrs: 0, Pocers: 1, PowAddrs: 2, Powers: 3, PoolAddrs: 4, Poolers: 12},
+		config.NUC.RuleStorageLayouts[1],
+	}}}
+	if err := config.CheckCompatible(updated, 50); err != nil {
+		t.Errorf("unexpected error before the layout activated: %v", err)
+	}
+	err := config.CheckCompatible(updated, 150)
+	want := &ConfigCompatError{What: "NUC rule storage layout #0", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(100), RewindTo: 99}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("error mismatch: have %v, want %v", err, want)
+	}
+}